@@ -1,15 +1,29 @@
 package api
 
 import (
+	"context"
 	"database/sql"
+	"log"
+	"time"
 
 	"github.com/delapaska/avito-rent/configs"
 	_ "github.com/delapaska/avito-rent/docs"
 	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/moderation"
+	"github.com/delapaska/avito-rent/notification"
+	"github.com/delapaska/avito-rent/service/audit"
 	"github.com/delapaska/avito-rent/service/auth"
+	"github.com/delapaska/avito-rent/service/developer"
 	dummyauth "github.com/delapaska/avito-rent/service/dummyAuth"
+	"github.com/delapaska/avito-rent/service/events"
+	"github.com/delapaska/avito-rent/service/favorite"
 	"github.com/delapaska/avito-rent/service/flat"
+	"github.com/delapaska/avito-rent/service/gql"
 	"github.com/delapaska/avito-rent/service/house"
+	"github.com/delapaska/avito-rent/service/message"
+	"github.com/delapaska/avito-rent/service/search"
+	"github.com/delapaska/avito-rent/service/viewing"
+	"github.com/delapaska/avito-rent/service/webhook"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -20,28 +34,75 @@ type APIServer struct {
 	engine *gin.Engine
 }
 
-func NewAPIServer(db *sql.DB) *APIServer {
+func NewAPIServer(db *sql.DB, psqlInfo string) *APIServer {
 
 	engine := gin.New()
 	engine.Use(gin.Recovery())
 	engine.Use(middleware.Logger())
+	auditStore := audit.NewStore(db)
+	engine.Use(middleware.Audit(auditStore))
 	engine.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	dummyStore := dummyauth.NewStore(db)
 	dummyHandler := dummyauth.NewHandler(dummyStore)
 	dummyHandler.RegisterRoutes(engine)
 
 	houseStore := house.NewStore(db)
-	houseHandler := house.NewHandler(houseStore)
+	searchStore := search.NewStore(db)
+	viewingStore := viewing.NewStore(db)
+	notifier := notification.NewNotifier(houseStore, searchStore, viewingStore)
+	go notifier.RunDigests(context.Background(), configs.Envs.DigestHour)
+	go notifier.RunViewingReminders(context.Background())
+	houseHandler := house.NewHandler(houseStore, notifier)
 	houseHandler.RegisterRoutes(engine)
 
-	flatStore := flat.NewStore(db)
-	flatHandler := flat.NewHandler(flatStore)
+	developerStore := developer.NewStore(db)
+	developerHandler := developer.NewHandler(developerStore)
+	developerHandler.RegisterRoutes(engine)
+
+	flatStore := flat.NewStore(db).WithRules(moderation.FromConfig(configs.Envs.Moderation)).
+		WithListingTTL(time.Duration(configs.Envs.ListingTTLDays) * 24 * time.Hour)
+	flatHandler := flat.NewHandler(flatStore, notifier)
 	flatHandler.RegisterRoutes(engine)
+	expiryLead := time.Duration(configs.Envs.ExpiryReminderDays) * 24 * time.Hour
+	go flat.NewExpirer(flatStore, notifier, expiryLead).Run(context.Background())
 
 	authStore := auth.NewStore(db)
 	authHandler := auth.NewHandler(authStore)
 	authHandler.RegisterRoutes(engine)
 
+	searchHandler := search.NewHandler(searchStore, authStore)
+	searchHandler.RegisterRoutes(engine)
+
+	favoriteStore := favorite.NewStore(db)
+	favoriteHandler := favorite.NewHandler(favoriteStore)
+	favoriteHandler.RegisterRoutes(engine)
+
+	viewingHandler := viewing.NewHandler(viewingStore, flatStore, authStore, notifier)
+	viewingHandler.RegisterRoutes(engine)
+
+	messageStore := message.NewStore(db)
+	messageHandler := message.NewHandler(messageStore, flatStore, authStore, notifier)
+	messageHandler.RegisterRoutes(engine)
+
+	gqlHandler := gql.NewHandler(houseStore, flatStore, authStore, notifier)
+	gqlHandler.RegisterRoutes(engine)
+
+	eventStore := events.NewStore(db)
+	eventBroker := events.NewBroker(eventStore)
+	if err := eventBroker.Listen(psqlInfo); err != nil {
+		log.Fatal(err)
+	}
+	eventHandler := events.NewHandler(eventStore, eventBroker)
+	eventHandler.RegisterRoutes(engine)
+
+	webhookStore := webhook.NewStore(db)
+	webhookHandler := webhook.NewHandler(webhookStore)
+	webhookHandler.RegisterRoutes(engine)
+	go webhook.NewDispatcher(webhookStore).Run(context.Background())
+
+	auditHandler := audit.NewHandler(auditStore)
+	auditHandler.RegisterRoutes(engine)
+
 	return &APIServer{
 		addr:   ":" + configs.Envs.Port,
 		engine: engine,
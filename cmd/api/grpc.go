@@ -0,0 +1,58 @@
+package api
+
+import (
+	"database/sql"
+	"net"
+	"time"
+
+	"github.com/delapaska/avito-rent/configs"
+	"github.com/delapaska/avito-rent/grpcapi"
+	"github.com/delapaska/avito-rent/grpcapi/pb"
+	"github.com/delapaska/avito-rent/moderation"
+	"github.com/delapaska/avito-rent/notification"
+	"github.com/delapaska/avito-rent/service/audit"
+	"github.com/delapaska/avito-rent/service/auth"
+	"github.com/delapaska/avito-rent/service/flat"
+	"github.com/delapaska/avito-rent/service/house"
+	"github.com/delapaska/avito-rent/service/search"
+	"github.com/delapaska/avito-rent/service/viewing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+type GRPCServer struct {
+	addr   string
+	server *grpc.Server
+}
+
+func NewGRPCServer(db *sql.DB) *GRPCServer {
+
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		grpcapi.AuthInterceptor(),
+		grpcapi.AuditInterceptor(audit.NewStore(db)),
+	))
+
+	houseStore := house.NewStore(db)
+	notifier := notification.NewNotifier(houseStore, search.NewStore(db), viewing.NewStore(db))
+
+	flatStore := flat.NewStore(db).WithRules(moderation.FromConfig(configs.Envs.Moderation)).
+		WithListingTTL(time.Duration(configs.Envs.ListingTTLDays) * 24 * time.Hour)
+
+	pb.RegisterHouseServiceServer(server, grpcapi.NewHouseServer(houseStore, notifier))
+	pb.RegisterFlatServiceServer(server, grpcapi.NewFlatServer(flatStore, notifier))
+	pb.RegisterAuthServiceServer(server, grpcapi.NewAuthServer(auth.NewStore(db)))
+	reflection.Register(server)
+
+	return &GRPCServer{
+		addr:   ":" + configs.Envs.GRPCPort,
+		server: server,
+	}
+}
+
+func (s *GRPCServer) Run() error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	return s.server.Serve(lis)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type config struct {
+	url        string
+	duration   time.Duration
+	readers    int
+	creators   int
+	moderators int
+	houses     []int
+	format     string
+	out        string
+	seed       int64
+}
+
+func main() {
+	var cfg config
+	var houses string
+	flag.StringVar(&cfg.url, "url", "http://localhost:8080", "base URL of the API")
+	flag.DurationVar(&cfg.duration, "duration", 30*time.Second, "how long to run the test")
+	flag.IntVar(&cfg.readers, "readers", 8, "clients reading GET /house/:id")
+	flag.IntVar(&cfg.creators, "creators", 2, "clients creating flats")
+	flag.IntVar(&cfg.moderators, "moderators", 1, "moderators taking flats through moderation")
+	flag.StringVar(&houses, "houses", "", "comma-separated house ids; a house is created when empty")
+	flag.StringVar(&cfg.format, "format", "text", "report format: text or json")
+	flag.StringVar(&cfg.out, "out", "", "write the report to a file instead of stdout")
+	flag.Int64Var(&cfg.seed, "seed", time.Now().UnixNano(), "random seed")
+	flag.Parse()
+
+	if cfg.format != "text" && cfg.format != "json" {
+		log.Fatalf("unknown format %q", cfg.format)
+	}
+	if cfg.readers+cfg.creators+cfg.moderators == 0 {
+		log.Fatal("at least one reader, creator or moderator is required")
+	}
+	for _, s := range strings.Split(houses, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		id, err := strconv.Atoi(s)
+		if err != nil {
+			log.Fatalf("invalid house id %q", s)
+		}
+		cfg.houses = append(cfg.houses, id)
+	}
+
+	rep, err := run(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	w := os.Stdout
+	if cfg.out != "" {
+		f, err := os.Create(cfg.out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if cfg.format == "json" {
+		err = rep.writeJSON(w)
+	} else {
+		err = rep.writeText(w)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(cfg config) (report, error) {
+	workers := cfg.readers + cfg.creators + cfg.moderators
+	c := newClient(strings.TrimRight(cfg.url, "/"), workers, newStats())
+	ctx := context.Background()
+
+	clientToken, err := c.dummyLogin(ctx, "client")
+	if err != nil {
+		return report{}, err
+	}
+	moderatorTokens := make([]string, cfg.moderators)
+	for i := range moderatorTokens {
+		if moderatorTokens[i], err = c.dummyLogin(ctx, "moderator"); err != nil {
+			return report{}, err
+		}
+	}
+
+	if len(cfg.houses) == 0 {
+		token, err := c.dummyLogin(ctx, "moderator")
+		if err != nil {
+			return report{}, err
+		}
+		id, err := c.createHouse(ctx, token)
+		if err != nil {
+			return report{}, fmt.Errorf("creating house: %w", err)
+		}
+		log.Println("Created house", id)
+		cfg.houses = []int{id}
+	}
+	c.stats = newStats()
+
+	log.Printf("Running for %s against %s: %d readers, %d creators, %d moderators",
+		cfg.duration, cfg.url, cfg.readers, cfg.creators, cfg.moderators)
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.duration)
+	defer cancel()
+
+	queue := make(chan int, 1024)
+	var wg sync.WaitGroup
+	start := time.Now()
+	worker := 0
+	spawn := func(fn func(r *rand.Rand)) {
+		r := rand.New(rand.NewSource(cfg.seed + int64(worker)))
+		worker++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(r)
+		}()
+	}
+
+	for i := 0; i < cfg.readers; i++ {
+		spawn(func(r *rand.Rand) { reader(ctx, c, clientToken, cfg.houses, r) })
+	}
+	for i := 0; i < cfg.creators; i++ {
+		spawn(func(r *rand.Rand) { creator(ctx, c, clientToken, cfg.houses, queue, r) })
+	}
+	for _, token := range moderatorTokens {
+		token := token
+		spawn(func(r *rand.Rand) { moderator(ctx, c, token, cfg.houses, queue, r) })
+	}
+
+	wg.Wait()
+	return c.stats.report(time.Since(start), cfg), nil
+}
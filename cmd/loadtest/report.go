@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+type opStats struct {
+	latencies []time.Duration
+	codes     map[int]int
+}
+
+type stats struct {
+	mu  sync.Mutex
+	ops map[string]*opStats
+}
+
+func newStats() *stats {
+	return &stats{ops: make(map[string]*opStats)}
+}
+
+// record stores one request; code 0 means the request failed before a response.
+func (s *stats) record(op string, code int, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.ops[op]
+	if !ok {
+		o = &opStats{codes: make(map[int]int)}
+		s.ops[op] = o
+	}
+	o.latencies = append(o.latencies, latency)
+	o.codes[code]++
+}
+
+type latencyReport struct {
+	Min  float64 `json:"min_ms"`
+	Mean float64 `json:"mean_ms"`
+	P50  float64 `json:"p50_ms"`
+	P90  float64 `json:"p90_ms"`
+	P95  float64 `json:"p95_ms"`
+	P99  float64 `json:"p99_ms"`
+	Max  float64 `json:"max_ms"`
+}
+
+type opReport struct {
+	Operation  string         `json:"operation"`
+	Requests   int            `json:"requests"`
+	Errors     int            `json:"errors"`
+	ErrorRate  float64        `json:"error_rate"`
+	Throughput float64        `json:"throughput_rps"`
+	Latency    latencyReport  `json:"latency"`
+	StatusCode map[string]int `json:"status_codes"`
+}
+
+type report struct {
+	Duration   float64    `json:"duration_s"`
+	Readers    int        `json:"readers"`
+	Creators   int        `json:"creators"`
+	Moderators int        `json:"moderators"`
+	Total      opReport   `json:"total"`
+	Operations []opReport `json:"operations"`
+}
+
+func (s *stats) report(elapsed time.Duration, cfg config) report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := report{
+		Duration:   elapsed.Seconds(),
+		Readers:    cfg.readers,
+		Creators:   cfg.creators,
+		Moderators: cfg.moderators,
+	}
+
+	total := &opStats{codes: make(map[int]int)}
+	names := make([]string, 0, len(s.ops))
+	for name, o := range s.ops {
+		names = append(names, name)
+		total.latencies = append(total.latencies, o.latencies...)
+		for code, n := range o.codes {
+			total.codes[code] += n
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		r.Operations = append(r.Operations, s.ops[name].summarize(name, elapsed))
+	}
+	r.Total = total.summarize("total", elapsed)
+	return r
+}
+
+func (o *opStats) summarize(name string, elapsed time.Duration) opReport {
+	sorted := append([]time.Duration(nil), o.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	r := opReport{
+		Operation:  name,
+		Requests:   len(sorted),
+		StatusCode: make(map[string]int),
+	}
+	for code, n := range o.codes {
+		if code == 0 || code >= 400 {
+			r.Errors += n
+		}
+		r.StatusCode[codeLabel(code)] = n
+	}
+	if r.Requests == 0 {
+		return r
+	}
+
+	var sum time.Duration
+	for _, l := range sorted {
+		sum += l
+	}
+	r.ErrorRate = float64(r.Errors) / float64(r.Requests)
+	r.Throughput = float64(r.Requests) / elapsed.Seconds()
+	r.Latency = latencyReport{
+		Min:  ms(sorted[0]),
+		Mean: ms(sum / time.Duration(len(sorted))),
+		P50:  ms(percentile(sorted, 50)),
+		P90:  ms(percentile(sorted, 90)),
+		P95:  ms(percentile(sorted, 95)),
+		P99:  ms(percentile(sorted, 99)),
+		Max:  ms(sorted[len(sorted)-1]),
+	}
+	return r
+}
+
+// percentile uses the nearest-rank method on an ascending slice.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p/100*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func codeLabel(code int) string {
+	if code == 0 {
+		return "network error"
+	}
+	return strconv.Itoa(code) + " " + http.StatusText(code)
+}
+
+func (r report) writeJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func (r report) writeText(w io.Writer) error {
+	fmt.Fprintf(w, "Duration: %.1fs, readers: %d, creators: %d, moderators: %d\n\n",
+		r.Duration, r.Readers, r.Creators, r.Moderators)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "operation\trequests\trps\terrors\tp50 ms\tp90 ms\tp95 ms\tp99 ms\tmax ms\t")
+	for _, o := range append(r.Operations, r.Total) {
+		fmt.Fprintf(tw, "%s\t%d\t%.1f\t%.2f%%\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\t\n",
+			o.Operation, o.Requests, o.Throughput, o.ErrorRate*100,
+			o.Latency.P50, o.Latency.P90, o.Latency.P95, o.Latency.P99, o.Latency.Max)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "\nStatus codes:")
+	for _, o := range r.Operations {
+		codes := make([]string, 0, len(o.StatusCode))
+		for code := range o.StatusCode {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		fmt.Fprintf(w, "  %s\n", o.Operation)
+		for _, code := range codes {
+			fmt.Fprintf(w, "    %-28s %d\n", code, o.StatusCode[code])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/delapaska/avito-rent/models"
+)
+
+const (
+	opHouseGet   = "GET /house/:id"
+	opFlatCreate = "POST /flat/create"
+	opFlatUpdate = "POST /flat/update"
+)
+
+type client struct {
+	baseURL string
+	http    *http.Client
+	stats   *stats
+}
+
+func newClient(baseURL string, conns int, stats *stats) *client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = conns
+	transport.MaxIdleConnsPerHost = conns
+
+	return &client{
+		baseURL: baseURL,
+		http:    &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		stats:   stats,
+	}
+}
+
+// do sends a request and records it under op. out is decoded only for 2xx responses.
+func (c *client) do(ctx context.Context, op, method, path, token string, body, out any) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	if err != nil {
+		if ctx.Err() == nil {
+			c.stats.record(op, 0, time.Since(start))
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var decodeErr error
+	if out != nil && resp.StatusCode < 300 {
+		decodeErr = json.NewDecoder(resp.Body).Decode(out)
+	} else {
+		_, _ = io.Copy(io.Discard, resp.Body)
+	}
+	c.stats.record(op, resp.StatusCode, time.Since(start))
+
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("%s: unexpected status %d", op, resp.StatusCode)
+	}
+	return resp.StatusCode, decodeErr
+}
+
+func (c *client) dummyLogin(ctx context.Context, userType string) (string, error) {
+	var resp struct {
+		Token string `json:"token"`
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/dummyLogin?userType="+userType, nil)
+	if err != nil {
+		return "", err
+	}
+	r, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("dummyLogin: unexpected status %d", r.StatusCode)
+	}
+	if err := json.NewDecoder(r.Body).Decode(&resp); err != nil {
+		return "", err
+	}
+	return resp.Token, nil
+}
+
+func (c *client) createHouse(ctx context.Context, token string) (int, error) {
+	var house models.House
+	_, err := c.do(ctx, "POST /house/create", http.MethodPost, "/house/create", token, models.HousePayload{
+		Address:   "Лесная улица, 7, Москва, 125196",
+		Year:      2003,
+		Developer: "loadtest",
+	}, &house)
+	return house.Id, err
+}
+
+func (c *client) createFlat(ctx context.Context, token string, houseID int, r *rand.Rand) (models.Flat, error) {
+	var flat models.Flat
+	rooms := r.Intn(4) + 1
+	_, err := c.do(ctx, opFlatCreate, http.MethodPost, "/flat/create", token, models.FlatPayload{
+		House_id: houseID,
+		Price:    rooms * (3_000_000 + r.Intn(5_000_000)),
+		Rooms:    rooms,
+	}, &flat)
+	return flat, err
+}
+
+func (c *client) updateFlatStatus(ctx context.Context, token string, id int, status string) error {
+	_, err := c.do(ctx, opFlatUpdate, http.MethodPost, "/flat/update", token, models.UpdateStatusPayload{
+		Id:     id,
+		Status: status,
+	}, nil)
+	return err
+}
+
+// reader repeatedly fetches flats of random houses.
+func reader(ctx context.Context, c *client, token string, houses []int, r *rand.Rand) {
+	for ctx.Err() == nil {
+		id := houses[r.Intn(len(houses))]
+		c.do(ctx, opHouseGet, http.MethodGet, fmt.Sprintf("/house/%d", id), token, nil, nil)
+	}
+}
+
+// creator repeatedly creates flats and offers them to moderators.
+func creator(ctx context.Context, c *client, token string, houses []int, queue chan<- int, r *rand.Rand) {
+	for ctx.Err() == nil {
+		flat, err := c.createFlat(ctx, token, houses[r.Intn(len(houses))], r)
+		if err != nil || flat.Id == 0 {
+			continue
+		}
+		select {
+		case queue <- flat.Id:
+		default:
+		}
+	}
+}
+
+// moderator takes flats through moderation. The flat must be moderated by the
+// same user that took it, so each moderator keeps its own token; when creators
+// fall behind it creates the flat itself.
+func moderator(ctx context.Context, c *client, token string, houses []int, queue <-chan int, r *rand.Rand) {
+	for ctx.Err() == nil {
+		var id int
+		select {
+		case id = <-queue:
+		default:
+			flat, err := c.createFlat(ctx, token, houses[r.Intn(len(houses))], r)
+			if err != nil || flat.Id == 0 {
+				continue
+			}
+			id = flat.Id
+		}
+
+		if err := c.updateFlatStatus(ctx, token, id, models.StatusOnModeration); err != nil {
+			continue
+		}
+		status := models.StatusApproved
+		if r.Intn(4) == 0 {
+			status = models.StatusDeclined
+		}
+		c.updateFlatStatus(ctx, token, id, status)
+	}
+}
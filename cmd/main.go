@@ -37,8 +37,19 @@ func main() {
 	}
 
 	initStorage(db)
+	if configs.Envs.AutoMigrate {
+		migrateStorage(db)
+	}
+	grpcSrv := api.NewGRPCServer(db)
+	go func() {
+		if err := grpcSrv.Run(); err != nil {
+			log.Fatal(err)
+		}
+	}()
+	log.Println("gRPC server started on port:", configs.Envs.GRPCPort)
+
 	log.Println("server started on port:", configs.Envs.Port)
-	srv := api.NewAPIServer(db)
+	srv := api.NewAPIServer(db, psqlInfo)
 	srv.Run()
 
 }
@@ -51,3 +62,9 @@ func initStorage(db *sql.DB) {
 	}
 	log.Println("DB: Successfully connected")
 }
+
+func migrateStorage(conn *sql.DB) {
+	if err := db.MigrateUp(conn); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -2,57 +2,208 @@ package main
 
 import (
 	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
+	"time"
 
+	"github.com/delapaska/avito-rent/configs"
+	"github.com/delapaska/avito-rent/db"
 	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 )
 
+const usage = `Usage: migrate [-dir path] <command> [arg]
+
+Commands:
+  up [N]        apply all or N pending migrations
+  down [N]      roll back all or N applied migrations
+  goto V        migrate up or down to version V
+  force V       set version V without running migrations and clear the dirty flag
+  version       print the current version
+  create NAME   create a timestamped up/down pair in -dir
+  status        list applied and pending migrations
+`
+
 func main() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Fatal("Error loading .env file")
+	dir := flag.String("dir", "cmd/migrate/migrations", "directory for new migrations (create only)")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
 	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	cmd, args := args[0], args[1:]
+
+	if cmd == "create" {
+		if len(args) != 1 {
+			log.Fatal("create requires a migration name")
+		}
+		if err := createMigration(*dir, args[0]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	psqlInfo := fmt.Sprintf("host=%s port=%s user=%s "+
 		"password=%s dbname=%s sslmode=disable",
-		os.Getenv("DB_HOST"), os.Getenv("DB_PORT"),
-		os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_NAME"))
-	db, err := sql.Open("postgres", psqlInfo)
+		configs.Envs.Host, configs.Envs.DBPort,
+		configs.Envs.DBUser, configs.Envs.DBPassword, configs.Envs.DBName)
+	conn, err := sql.Open("postgres", psqlInfo)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer db.Close()
+	defer conn.Close()
 
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	m, err := db.NewMigrator(conn)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://cmd/migrate/migrations",
-		"postgres", driver)
+	switch cmd {
+	case "up":
+		n, err := optionalCount(args)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if n == 0 {
+			err = m.Up()
+		} else {
+			err = m.Steps(n)
+		}
+		check(err)
+	case "down":
+		n, err := optionalCount(args)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if n == 0 {
+			err = m.Down()
+		} else {
+			err = m.Steps(-n)
+		}
+		check(err)
+	case "goto":
+		v, err := requiredVersion(cmd, args)
+		if err != nil {
+			log.Fatal(err)
+		}
+		check(m.Migrate(uint(v)))
+	case "force":
+		v, err := requiredVersion(cmd, args)
+		if err != nil {
+			log.Fatal(err)
+		}
+		check(m.Force(v))
+	case "version":
+	case "status":
+		if err := printStatus(m); err != nil {
+			log.Fatal(err)
+		}
+		return
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
 
-	if err != nil {
+	printVersion(m)
+}
 
+func check(err error) {
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
 		log.Fatal(err)
 	}
-	v, d, _ := m.Version()
-	log.Printf("Version: %d, dirty: %v", v, d)
-	cmd := os.Args[len(os.Args)-1]
-	if cmd == "up" {
+}
 
-		if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-			log.Fatal(err)
+func optionalCount(args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid step count %q", args[0])
+	}
+	return n, nil
+}
+
+func requiredVersion(cmd string, args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("%s requires a version", cmd)
+	}
+	v, err := strconv.Atoi(args[0])
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid version %q", args[0])
+	}
+	return v, nil
+}
+
+func printVersion(m *migrate.Migrate) {
+	v, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		log.Println("Version: none")
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Version: %d, dirty: %v", v, dirty)
+}
+
+func printStatus(m *migrate.Migrate) error {
+	current, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return err
+	}
+	applied := err == nil
+
+	src, err := db.NewMigrationSource()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	v, err := src.First()
+	for err == nil {
+		r, name, readErr := src.ReadUp(v)
+		if readErr != nil {
+			return readErr
+		}
+		r.Close()
+		state := "pending"
+		if applied && v <= current {
+			state = "applied"
+			if v == current && dirty {
+				state = "dirty"
+			}
 		}
+		fmt.Printf("%-8s %d_%s\n", state, v, name)
+		v, err = src.Next(v)
 	}
-	if cmd == "down" {
-		if err := m.Down(); err != nil && err != migrate.ErrNoChange {
-			log.Fatal(err)
+	if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func createMigration(dir, name string) error {
+	version := time.Now().UTC().Format("20060102150405")
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(dir, fmt.Sprintf("%s_%s.%s.sql", version, name, direction))
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return err
 		}
+		f.Close()
+		log.Println("Created", path)
 	}
+	return nil
 }
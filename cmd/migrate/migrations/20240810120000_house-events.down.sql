@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS flat_house_events ON Flat;
+DROP FUNCTION IF EXISTS record_house_event();
+DROP TABLE IF EXISTS House_events;
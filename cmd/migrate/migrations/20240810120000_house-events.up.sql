@@ -0,0 +1,52 @@
+CREATE TABLE House_events (
+    id BIGSERIAL PRIMARY KEY,
+    house_id INT NOT NULL REFERENCES House(id) ON DELETE CASCADE,
+    type VARCHAR(32) NOT NULL,
+    flat JSONB NOT NULL,
+    old_price INT,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc')
+);
+
+CREATE INDEX idx_house_events_house_id ON House_events(house_id, id);
+
+CREATE OR REPLACE FUNCTION record_house_event() RETURNS trigger AS $$
+DECLARE
+    event_type VARCHAR(32);
+    event_old_price INT;
+    event_id BIGINT;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        event_type := 'flat_created';
+    ELSIF NEW.status = 'approved' AND OLD.status IS DISTINCT FROM 'approved' THEN
+        event_type := 'flat_approved';
+    ELSIF NEW.price IS DISTINCT FROM OLD.price THEN
+        event_type := 'flat_price_changed';
+        event_old_price := OLD.price;
+    ELSE
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO House_events (house_id, type, flat, old_price)
+    VALUES (
+        NEW.house_id,
+        event_type,
+        jsonb_build_object(
+            'id', NEW.id,
+            'house_id', NEW.house_id,
+            'price', NEW.price,
+            'rooms', NEW.rooms,
+            'status', NEW.status
+        ),
+        event_old_price
+    )
+    RETURNING id INTO event_id;
+
+    PERFORM pg_notify('house_events', json_build_object('id', event_id, 'house_id', NEW.house_id)::text);
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER flat_house_events
+AFTER INSERT OR UPDATE ON Flat
+FOR EACH ROW EXECUTE FUNCTION record_house_event();
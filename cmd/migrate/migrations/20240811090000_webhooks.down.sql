@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS house_events_webhooks ON House_events;
+DROP FUNCTION IF EXISTS enqueue_webhook_deliveries();
+DROP TABLE IF EXISTS Webhook_delivery_attempts;
+DROP TABLE IF EXISTS Webhook_deliveries;
+DROP TABLE IF EXISTS Webhooks;
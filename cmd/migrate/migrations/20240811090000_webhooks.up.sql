@@ -0,0 +1,60 @@
+CREATE TABLE Webhooks (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    user_type VARCHAR(50) NOT NULL,
+    url VARCHAR(2048) NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    event_types VARCHAR(32)[] NOT NULL,
+    house_ids INT[] NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    failure_count INT NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_webhooks_user_id ON Webhooks(user_id);
+
+CREATE TABLE Webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    webhook_id INT NOT NULL REFERENCES Webhooks(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL REFERENCES House_events(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON Webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
+CREATE TABLE Webhook_delivery_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    delivery_id BIGINT NOT NULL REFERENCES Webhook_deliveries(id) ON DELETE CASCADE,
+    webhook_id INT NOT NULL REFERENCES Webhooks(id) ON DELETE CASCADE,
+    attempt INT NOT NULL,
+    status_code INT,
+    error TEXT,
+    duration_ms INT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_webhook_delivery_attempts_webhook_id ON Webhook_delivery_attempts(webhook_id, id);
+
+-- Queue a delivery for every active webhook interested in the event. Clients
+-- only hear about approved flats, the same as on the event stream.
+CREATE OR REPLACE FUNCTION enqueue_webhook_deliveries() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO Webhook_deliveries (webhook_id, event_id)
+    SELECT w.id, NEW.id
+    FROM Webhooks w
+    WHERE w.active
+      AND NEW.type = ANY(w.event_types)
+      AND (cardinality(w.house_ids) = 0 OR NEW.house_id = ANY(w.house_ids))
+      AND (w.user_type = 'moderator' OR NEW.flat->>'status' = 'approved');
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER house_events_webhooks
+AFTER INSERT ON House_events
+FOR EACH ROW EXECUTE FUNCTION enqueue_webhook_deliveries();
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_subscriptions_house_id;
+
+ALTER TABLE Subscriptions DROP COLUMN IF EXISTS locale;
@@ -0,0 +1,3 @@
+ALTER TABLE Subscriptions ADD COLUMN locale VARCHAR(2) NOT NULL DEFAULT 'ru';
+
+CREATE INDEX idx_subscriptions_house_id ON Subscriptions(house_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS Subscription_digests;
+
+ALTER TABLE Subscriptions DROP COLUMN IF EXISTS delivery;
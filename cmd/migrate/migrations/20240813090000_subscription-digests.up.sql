@@ -0,0 +1,7 @@
+ALTER TABLE Subscriptions ADD COLUMN delivery VARCHAR(16) NOT NULL DEFAULT 'instant';
+
+-- One row per sent daily digest, so only one instance sends it.
+CREATE TABLE Subscription_digests (
+    day DATE PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL
+);
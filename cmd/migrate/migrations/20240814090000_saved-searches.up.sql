@@ -0,0 +1,19 @@
+CREATE TABLE Saved_searches (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    locale VARCHAR(2) NOT NULL DEFAULT 'ru',
+    rooms_min INT,
+    rooms_max INT,
+    price_min INT,
+    price_max INT,
+    year_min INT,
+    year_max INT,
+    developer VARCHAR(255),
+    address VARCHAR(255),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_saved_searches_user_id ON Saved_searches(user_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS Favorites;
+
+DROP INDEX IF EXISTS idx_flat_owner_id;
+
+ALTER TABLE Flat DROP COLUMN IF EXISTS owner_id;
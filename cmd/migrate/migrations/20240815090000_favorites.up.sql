@@ -0,0 +1,12 @@
+ALTER TABLE Flat ADD COLUMN owner_id UUID;
+
+CREATE INDEX idx_flat_owner_id ON Flat(owner_id);
+
+CREATE TABLE Favorites (
+    user_id UUID NOT NULL,
+    flat_id INT NOT NULL REFERENCES Flat(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, flat_id)
+);
+
+CREATE INDEX idx_favorites_flat_id ON Favorites(flat_id);
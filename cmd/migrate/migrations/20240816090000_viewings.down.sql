@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS Viewings;
+
+DROP TABLE IF EXISTS Viewing_slots;
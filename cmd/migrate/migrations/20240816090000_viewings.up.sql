@@ -0,0 +1,30 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE Viewing_slots (
+    id SERIAL PRIMARY KEY,
+    flat_id INT NOT NULL REFERENCES Flat(id) ON DELETE CASCADE,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    CHECK (ends_at > starts_at),
+    EXCLUDE USING gist (flat_id WITH =, tstzrange(starts_at, ends_at) WITH &&)
+);
+
+CREATE TABLE Viewings (
+    id SERIAL PRIMARY KEY,
+    slot_id INT NOT NULL REFERENCES Viewing_slots(id) ON DELETE CASCADE,
+    flat_id INT NOT NULL REFERENCES Flat(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    locale VARCHAR(2) NOT NULL DEFAULT 'ru',
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'booked',
+    reminder_sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    cancelled_at TIMESTAMPTZ,
+    EXCLUDE USING gist (flat_id WITH =, tstzrange(starts_at, ends_at) WITH &&) WHERE (status = 'booked')
+);
+
+CREATE INDEX idx_viewings_user_id ON Viewings(user_id);
+CREATE INDEX idx_viewings_reminders ON Viewings(starts_at) WHERE status = 'booked' AND reminder_sent_at IS NULL;
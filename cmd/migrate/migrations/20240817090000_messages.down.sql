@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS Messages;
+
+DROP TABLE IF EXISTS Conversations;
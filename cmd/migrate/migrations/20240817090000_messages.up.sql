@@ -0,0 +1,27 @@
+CREATE TABLE Conversations (
+    id SERIAL PRIMARY KEY,
+    flat_id INT NOT NULL REFERENCES Flat(id) ON DELETE CASCADE,
+    client_id UUID NOT NULL,
+    owner_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    last_message_at TIMESTAMP NOT NULL,
+    reported_at TIMESTAMP,
+    report_reason TEXT,
+    UNIQUE (flat_id, client_id)
+);
+
+CREATE INDEX idx_conversations_client_id ON Conversations(client_id);
+CREATE INDEX idx_conversations_owner_id ON Conversations(owner_id);
+CREATE INDEX idx_conversations_reported_at ON Conversations(reported_at) WHERE reported_at IS NOT NULL;
+
+CREATE TABLE Messages (
+    id BIGSERIAL PRIMARY KEY,
+    conversation_id INT NOT NULL REFERENCES Conversations(id) ON DELETE CASCADE,
+    sender_id UUID NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    read_at TIMESTAMP
+);
+
+CREATE INDEX idx_messages_conversation_id ON Messages(conversation_id, id);
+CREATE INDEX idx_messages_unread ON Messages(conversation_id, sender_id) WHERE read_at IS NULL;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_flat_house_id_active;
+
+ALTER TABLE Flat DROP CONSTRAINT IF EXISTS flat_house_id_fkey;
+ALTER TABLE Flat ADD CONSTRAINT flat_house_id_fkey FOREIGN KEY (house_id) REFERENCES House(id) ON DELETE CASCADE;
+
+ALTER TABLE Flat DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE House DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,7 @@
+ALTER TABLE House ADD COLUMN archived_at TIMESTAMP;
+ALTER TABLE Flat ADD COLUMN archived_at TIMESTAMP;
+
+ALTER TABLE Flat DROP CONSTRAINT IF EXISTS flat_house_id_fkey;
+ALTER TABLE Flat ADD CONSTRAINT flat_house_id_fkey FOREIGN KEY (house_id) REFERENCES House(id) ON DELETE RESTRICT;
+
+CREATE INDEX idx_flat_house_id_active ON Flat(house_id) WHERE archived_at IS NULL;
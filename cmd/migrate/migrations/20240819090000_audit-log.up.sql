@@ -0,0 +1,18 @@
+CREATE TABLE Audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id UUID,
+    actor_role VARCHAR(50) NOT NULL DEFAULT '',
+    action VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL,
+    before JSONB,
+    after JSONB,
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    client_ip VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_audit_log_entity ON Audit_log(entity_type, entity_id, id);
+CREATE INDEX idx_audit_log_actor_id ON Audit_log(actor_id, id);
+CREATE INDEX idx_audit_log_action ON Audit_log(action, id);
+CREATE INDEX idx_audit_log_created_at ON Audit_log(created_at);
@@ -0,0 +1,10 @@
+ALTER TABLE House ADD COLUMN developer VARCHAR(255);
+
+UPDATE House h
+SET developer = d.name
+FROM Developers d
+WHERE d.id = h.developer_id;
+
+ALTER TABLE House DROP COLUMN developer_id;
+
+DROP TABLE IF EXISTS Developers;
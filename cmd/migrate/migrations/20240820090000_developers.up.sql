@@ -0,0 +1,25 @@
+CREATE TABLE Developers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_developers_name ON Developers(lower(name));
+
+INSERT INTO Developers (name, created_at, updated_at)
+SELECT DISTINCT ON (lower(trim(developer))) trim(developer), now() AT TIME ZONE 'UTC', now() AT TIME ZONE 'UTC'
+FROM House
+WHERE trim(COALESCE(developer, '')) <> ''
+ORDER BY lower(trim(developer)), created_at;
+
+ALTER TABLE House ADD COLUMN developer_id INT REFERENCES Developers(id) ON DELETE RESTRICT;
+
+UPDATE House h
+SET developer_id = d.id
+FROM Developers d
+WHERE lower(trim(h.developer)) = lower(d.name);
+
+CREATE INDEX idx_house_developer_id ON House(developer_id);
+
+ALTER TABLE House DROP COLUMN developer;
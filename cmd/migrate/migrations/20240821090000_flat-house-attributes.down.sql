@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_flat_house_id_number;
+
+ALTER TABLE Flat DROP COLUMN IF EXISTS balcony;
+ALTER TABLE Flat DROP COLUMN IF EXISTS living_area;
+ALTER TABLE Flat DROP COLUMN IF EXISTS total_area;
+ALTER TABLE Flat DROP COLUMN IF EXISTS floor;
+ALTER TABLE Flat DROP COLUMN IF EXISTS number;
+
+ALTER TABLE House DROP COLUMN IF EXISTS parking;
+ALTER TABLE House DROP COLUMN IF EXISTS elevator;
+ALTER TABLE House DROP COLUMN IF EXISTS material;
+ALTER TABLE House DROP COLUMN IF EXISTS floors;
@@ -0,0 +1,14 @@
+ALTER TABLE House ADD COLUMN floors INT CHECK (floors > 0);
+ALTER TABLE House ADD COLUMN material VARCHAR(32) CHECK (material IN ('brick', 'panel', 'monolith', 'monolith_brick', 'block', 'wood'));
+ALTER TABLE House ADD COLUMN elevator BOOLEAN;
+ALTER TABLE House ADD COLUMN parking BOOLEAN;
+
+ALTER TABLE Flat ADD COLUMN number INT CHECK (number > 0);
+ALTER TABLE Flat ADD COLUMN floor INT CHECK (floor > 0);
+ALTER TABLE Flat ADD COLUMN total_area NUMERIC(7, 2) CHECK (total_area > 0);
+ALTER TABLE Flat ADD COLUMN living_area NUMERIC(7, 2) CHECK (living_area > 0);
+ALTER TABLE Flat ADD COLUMN balcony BOOLEAN;
+
+ALTER TABLE Flat ADD CONSTRAINT flat_living_area_total_area_check CHECK (living_area <= total_area);
+
+CREATE UNIQUE INDEX idx_flat_house_id_number ON Flat(house_id, number);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_flat_house_id_listing_type;
+
+ALTER TABLE Flat DROP CONSTRAINT IF EXISTS flat_rental_terms_check;
+
+ALTER TABLE Flat DROP COLUMN IF EXISTS utilities_included;
+ALTER TABLE Flat DROP COLUMN IF EXISTS commission;
+ALTER TABLE Flat DROP COLUMN IF EXISTS deposit;
+ALTER TABLE Flat DROP COLUMN IF EXISTS rent_period;
+ALTER TABLE Flat DROP COLUMN IF EXISTS listing_type;
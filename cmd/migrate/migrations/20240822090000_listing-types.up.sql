@@ -0,0 +1,12 @@
+ALTER TABLE Flat ADD COLUMN listing_type VARCHAR(16) NOT NULL DEFAULT 'sale' CHECK (listing_type IN ('sale', 'rent'));
+ALTER TABLE Flat ADD COLUMN rent_period VARCHAR(16) CHECK (rent_period IN ('monthly', 'daily'));
+ALTER TABLE Flat ADD COLUMN deposit INT CHECK (deposit >= 0);
+ALTER TABLE Flat ADD COLUMN commission INT CHECK (commission BETWEEN 0 AND 100);
+ALTER TABLE Flat ADD COLUMN utilities_included BOOLEAN;
+
+ALTER TABLE Flat ADD CONSTRAINT flat_rental_terms_check CHECK (
+    (listing_type = 'rent' AND rent_period IS NOT NULL)
+    OR (listing_type = 'sale' AND rent_period IS NULL AND deposit IS NULL AND commission IS NULL AND utilities_included IS NULL)
+);
+
+CREATE INDEX idx_flat_house_id_listing_type ON Flat(house_id, listing_type) WHERE archived_at IS NULL;
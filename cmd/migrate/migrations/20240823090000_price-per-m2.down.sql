@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_flat_house_id_price_per_m2;
+
+ALTER TABLE Flat DROP COLUMN IF EXISTS price_per_m2;
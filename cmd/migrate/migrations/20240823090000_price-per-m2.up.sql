@@ -0,0 +1,4 @@
+ALTER TABLE Flat ADD COLUMN price_per_m2 NUMERIC(12, 2)
+    GENERATED ALWAYS AS (CASE WHEN total_area > 0 THEN round(price / total_area, 2) END) STORED;
+
+CREATE INDEX idx_flat_house_id_price_per_m2 ON Flat(house_id, price_per_m2) WHERE archived_at IS NULL;
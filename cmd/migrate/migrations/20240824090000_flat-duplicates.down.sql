@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_flat_house_id_rooms_price;
+
+DROP TABLE IF EXISTS Flat_duplicates;
@@ -0,0 +1,7 @@
+CREATE TABLE Flat_duplicates (
+    flat_id INT NOT NULL REFERENCES Flat(id) ON DELETE CASCADE,
+    duplicate_id INT NOT NULL REFERENCES Flat(id) ON DELETE CASCADE,
+    PRIMARY KEY (flat_id, duplicate_id)
+);
+
+CREATE INDEX idx_flat_house_id_rooms_price ON Flat(house_id, rooms, price) WHERE archived_at IS NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE Flat DROP COLUMN IF EXISTS decline_reason;
+ALTER TABLE Flat DROP COLUMN IF EXISTS rule_hits;
+ALTER TABLE Flat DROP COLUMN IF EXISTS high_risk;
+ALTER TABLE Flat DROP COLUMN IF EXISTS description;
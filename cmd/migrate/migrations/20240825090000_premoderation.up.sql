@@ -0,0 +1,4 @@
+ALTER TABLE Flat ADD COLUMN description TEXT;
+ALTER TABLE Flat ADD COLUMN high_risk BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE Flat ADD COLUMN rule_hits JSONB;
+ALTER TABLE Flat ADD COLUMN decline_reason TEXT;
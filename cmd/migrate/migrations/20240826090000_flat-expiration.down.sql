@@ -0,0 +1,4 @@
+UPDATE Flat SET status = 'approved' WHERE status = 'expired';
+DROP INDEX IF EXISTS idx_flat_expires_at;
+ALTER TABLE Flat DROP COLUMN IF EXISTS expiry_reminder_sent_at;
+ALTER TABLE Flat DROP COLUMN IF EXISTS expires_at;
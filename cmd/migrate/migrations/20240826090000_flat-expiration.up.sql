@@ -0,0 +1,7 @@
+ALTER TABLE Flat ADD COLUMN expires_at TIMESTAMPTZ;
+ALTER TABLE Flat ADD COLUMN expiry_reminder_sent_at TIMESTAMPTZ;
+
+-- Flats approved before listings expired get the default 30 days from now.
+UPDATE Flat SET expires_at = now() + INTERVAL '30 days' WHERE status = 'approved';
+
+CREATE INDEX idx_flat_expires_at ON Flat(expires_at) WHERE status = 'approved';
@@ -0,0 +1,6 @@
+-- Closed deals become declined rather than expired: rolling back the
+-- flat-expiration migration next turns expired flats back into approved ones
+-- and would list rented and sold flats again.
+UPDATE Flat SET status = 'declined' WHERE status IN ('rented', 'sold');
+ALTER TABLE Flat DROP COLUMN IF EXISTS closed_at;
+ALTER TABLE Flat DROP COLUMN IF EXISTS final_price;
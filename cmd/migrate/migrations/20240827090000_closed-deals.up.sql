@@ -0,0 +1,2 @@
+ALTER TABLE Flat ADD COLUMN final_price INT CHECK (final_price > 0);
+ALTER TABLE Flat ADD COLUMN closed_at TIMESTAMPTZ;
@@ -0,0 +1,11 @@
+-- One row per digest email. A row is claimed before sending and only counts
+-- as delivered once sent_at is set, so a digest that failed to send is sent
+-- again. Subscription_digests now records the days whose digests were all
+-- delivered.
+CREATE TABLE Digest_deliveries (
+    day DATE NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    claimed_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP,
+    PRIMARY KEY (day, email)
+);
@@ -0,0 +1,3 @@
+-- The replaced emails and the dropped searches are not kept, so there is
+-- nothing to restore.
+SELECT 1;
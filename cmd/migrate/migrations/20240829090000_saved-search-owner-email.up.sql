@@ -0,0 +1,8 @@
+-- Saved searches used to accept any email. Send matches to the registered
+-- email of the owner instead, and drop the searches of users without one.
+UPDATE Saved_searches s SET email = u.email
+FROM Users u
+WHERE u.user_id = s.user_id AND s.email <> u.email;
+
+DELETE FROM Saved_searches s
+WHERE NOT EXISTS (SELECT 1 FROM Users u WHERE u.user_id = s.user_id);
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/delapaska/avito-rent/models"
+)
+
+var cities = []struct {
+	name   string
+	prefix int
+}{
+	{"Москва", 125},
+	{"Санкт-Петербург", 190},
+	{"Казань", 420},
+	{"Екатеринбург", 620},
+	{"Новосибирск", 630},
+	{"Нижний Новгород", 603},
+	{"Краснодар", 350},
+	{"Самара", 443},
+}
+
+var streets = []string{
+	"Лесная улица",
+	"Тверская улица",
+	"Садовая улица",
+	"Советская улица",
+	"Центральная улица",
+	"Молодёжная улица",
+	"Школьная улица",
+	"улица Ленина",
+	"улица Гагарина",
+	"улица Пушкина",
+	"проспект Мира",
+	"Ленинский проспект",
+	"Невский проспект",
+	"Кутузовский проспект",
+	"Московское шоссе",
+	"Набережная улица",
+	"Заречная улица",
+	"Полевая улица",
+	"Берёзовая аллея",
+	"переулок Строителей",
+}
+
+var developers = []string{
+	"ПИК",
+	"Самолёт",
+	"Группа ЛСР",
+	"Эталон",
+	"Донстрой",
+	"ФСК",
+	"Инград",
+	"А101",
+	"Level Group",
+	"Брусника",
+	"Унистрой",
+	"Setl Group",
+}
+
+var materials = []string{
+	models.MaterialBrick,
+	models.MaterialPanel,
+	models.MaterialMonolith,
+	models.MaterialMonolithBrick,
+	models.MaterialBlock,
+}
+
+type houseSeed struct {
+	address   string
+	year      int
+	developer string
+	floors    int
+	material  string
+	elevator  bool
+	parking   bool
+}
+
+type flatSeed struct {
+	price      int
+	rooms      int
+	status     string
+	number     int
+	floor      int
+	totalArea  float64
+	livingArea float64
+	balcony    bool
+
+	listingType string
+	rentPeriod  *string
+	deposit     *int
+	finalPrice  *int
+}
+
+func randomAddress(r *rand.Rand) string {
+	city := cities[r.Intn(len(cities))]
+	street := streets[r.Intn(len(streets))]
+	number := r.Intn(150) + 1
+	address := fmt.Sprintf("%s, %d", street, number)
+	if r.Intn(4) == 0 {
+		address += fmt.Sprintf(" к%d", r.Intn(5)+1)
+	}
+	return fmt.Sprintf("%s, %s, %d%03d", address, city.name, city.prefix, r.Intn(1000))
+}
+
+func randomHouse(r *rand.Rand, used map[string]bool) houseSeed {
+	address := randomAddress(r)
+	for used[address] {
+		address = randomAddress(r)
+	}
+	used[address] = true
+
+	developer := ""
+	if r.Intn(10) != 0 {
+		developer = developers[r.Intn(len(developers))]
+	}
+
+	floors := 5 + r.Intn(21)
+	return houseSeed{
+		address:   address,
+		year:      1955 + r.Intn(70),
+		developer: developer,
+		floors:    floors,
+		material:  materials[r.Intn(len(materials))],
+		elevator:  floors > 5,
+		parking:   r.Intn(2) == 0,
+	}
+}
+
+// randomFlat returns the flat with the given number, four flats per floor of
+// the house.
+func randomFlat(r *rand.Rand, house houseSeed, number int, status string) flatSeed {
+	rooms := r.Intn(5) + 1
+	pricePerRoom := 2_500_000 + r.Intn(6_000_000)
+	price := (rooms*pricePerRoom + r.Intn(1_000_000)) / 10_000 * 10_000
+	totalArea := float64(20+rooms*15+r.Intn(15)) + float64(r.Intn(10))/10
+
+	// Every third flat is let monthly for about half a percent of its price.
+	// Rented flats are always rentals and sold ones always for sale.
+	rent := r.Intn(3) == 0
+	switch status {
+	case models.StatusRented:
+		rent = true
+	case models.StatusSold:
+		rent = false
+	}
+	listingType := models.ListingSale
+	var rentPeriod *string
+	var deposit *int
+	if rent {
+		listingType = models.ListingRent
+		monthly := models.RentMonthly
+		rentPeriod = &monthly
+		price = price / 200 / 1000 * 1000
+		deposit = &price
+	}
+
+	// Deals close at up to 5% below the listed price.
+	var finalPrice *int
+	if status == models.StatusRented || status == models.StatusSold {
+		p := (price - price*r.Intn(6)/100) / 1000 * 1000
+		finalPrice = &p
+	}
+
+	return flatSeed{
+		price:      price,
+		rooms:      rooms,
+		status:     status,
+		number:     number,
+		floor:      min((number-1)/4+1, house.floors),
+		totalArea:  totalArea,
+		livingArea: float64(int(totalArea*6)) / 10,
+		balcony:    r.Intn(3) != 0,
+
+		listingType: listingType,
+		rentPeriod:  rentPeriod,
+		deposit:     deposit,
+		finalPrice:  finalPrice,
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/delapaska/avito-rent/configs"
+	"github.com/delapaska/avito-rent/db"
+	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/google/uuid"
+)
+
+// seedNamespace makes user ids a pure function of the seed and index.
+var seedNamespace = uuid.MustParse("5d0c3b5e-7f1a-4c63-9a57-3f7c2f0d9b11")
+
+var statuses = []string{
+	models.StatusCreated,
+	models.StatusOnModeration,
+	models.StatusApproved,
+	models.StatusDeclined,
+	models.StatusExpired,
+	models.StatusRented,
+	models.StatusSold,
+}
+
+type userSeed struct {
+	id       uuid.UUID
+	email    string
+	userType string
+}
+
+func main() {
+	seed := flag.Int64("seed", 1, "random seed; the same seed always produces the same data")
+	houses := flag.Int("houses", 50, "number of houses")
+	flats := flag.Int("flats", 20, "number of flats per house")
+	users := flag.Int("users", 5, "number of users of each type")
+	subs := flag.Int("subs", 3, "number of house subscriptions per client")
+	password := flag.String("password", "password", "password for every generated user")
+	flag.Parse()
+
+	psqlInfo := fmt.Sprintf("host=%s port=%s user=%s "+
+		"password=%s dbname=%s sslmode=disable",
+		configs.Envs.Host, configs.Envs.DBPort,
+		configs.Envs.DBUser, configs.Envs.DBPassword, configs.Envs.DBName)
+
+	conn, err := db.NewPostgresSQLStorage(psqlInfo)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	r := rand.New(rand.NewSource(*seed))
+
+	used := make(map[string]bool)
+	houseSeeds := make([]houseSeed, *houses)
+	flatSeeds := make([][]flatSeed, *houses)
+	for i := range houseSeeds {
+		houseSeeds[i] = randomHouse(r, used)
+		flatSeeds[i] = make([]flatSeed, *flats)
+		for j := range flatSeeds[i] {
+			flatSeeds[i][j] = randomFlat(r, houseSeeds[i], j+1, statuses[(i+j)%len(statuses)])
+		}
+	}
+
+	var clients, moderators []userSeed
+	for i := 1; i <= *users; i++ {
+		clients = append(clients, newUserSeed(*seed, "client", i))
+		moderators = append(moderators, newUserSeed(*seed, "moderator", i))
+	}
+
+	subscriptions := make(map[string][]int)
+	for _, u := range clients {
+		for _, i := range r.Perm(*houses)[:min(*subs, *houses)] {
+			subscriptions[u.email] = append(subscriptions[u.email], i)
+		}
+	}
+
+	tx, err := conn.Begin()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	hash, err := middleware.HashPassword(*password)
+	if err != nil {
+		log.Fatal(err)
+	}
+	createdUsers := 0
+	for _, u := range append(clients, moderators...) {
+		n, err := insertUser(tx, u, hash)
+		if err != nil {
+			log.Fatal(err)
+		}
+		createdUsers += n
+	}
+
+	houseIDs := make([]int, len(houseSeeds))
+	createdHouses, createdFlats := 0, 0
+	for i, h := range houseSeeds {
+		id, created, err := upsertHouse(tx, h)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if created {
+			createdHouses++
+		}
+		houseIDs[i] = id
+
+		n, err := insertFlats(tx, id, flatSeeds[i], clients, moderators)
+		if err != nil {
+			log.Fatal(err)
+		}
+		createdFlats += n
+	}
+
+	createdSubs := 0
+	for _, u := range clients {
+		for _, i := range subscriptions[u.email] {
+			n, err := insertSubscription(tx, houseIDs[i], u.email)
+			if err != nil {
+				log.Fatal(err)
+			}
+			createdSubs += n
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Seed %d: created %d users, %d houses, %d flats, %d subscriptions",
+		*seed, createdUsers, createdHouses, createdFlats, createdSubs)
+	log.Printf("All users have password %q", *password)
+	for _, u := range append(clients, moderators...) {
+		log.Printf("%-9s %s %s", u.userType, u.id, u.email)
+	}
+}
+
+func newUserSeed(seed int64, userType string, i int) userSeed {
+	return userSeed{
+		id:       uuid.NewSHA1(seedNamespace, []byte(fmt.Sprintf("%d:%s:%d", seed, userType, i))),
+		email:    fmt.Sprintf("%s%d.seed%d@example.com", userType, i, seed),
+		userType: userType,
+	}
+}
+
+func insertUser(tx *sql.Tx, u userSeed, hash string) (int, error) {
+	res, err := tx.Exec(`
+		INSERT INTO users (user_id, email, password, user_type)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING`,
+		u.id, u.email, hash, u.userType)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// upsertDeveloper returns the id of the developer with the name, ignoring
+// case, inserting it first if it does not exist yet. Houses without a
+// developer get a NULL id.
+func upsertDeveloper(tx *sql.Tx, name string) (sql.NullInt64, error) {
+	var id sql.NullInt64
+	if name == "" {
+		return id, nil
+	}
+
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	err := tx.QueryRow(`
+		INSERT INTO developers (name, created_at, updated_at)
+		VALUES ($1, $2, $2)
+		ON CONFLICT ((lower(name))) DO UPDATE SET name = developers.name
+		RETURNING id`,
+		name, currentTime).Scan(&id)
+	return id, err
+}
+
+// upsertHouse returns the id of the house with the same address, year and
+// developer, inserting it first if it does not exist yet.
+func upsertHouse(tx *sql.Tx, h houseSeed) (int, bool, error) {
+	developerID, err := upsertDeveloper(tx, h.developer)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var id int
+	err = tx.QueryRow(`
+		SELECT id
+		FROM house
+		WHERE address = $1 AND year = $2 AND developer_id IS NOT DISTINCT FROM $3
+		ORDER BY id
+		LIMIT 1`,
+		h.address, h.year, developerID).Scan(&id)
+	if err == nil {
+		return id, false, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, false, err
+	}
+
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	err = tx.QueryRow(`
+		INSERT INTO house (address, year, developer_id, floors, material, elevator, parking, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		RETURNING id`,
+		h.address, h.year, developerID, h.floors, h.material, h.elevator, h.parking, currentTime).Scan(&id)
+	return id, err == nil, err
+}
+
+// insertFlats tops the house up to len(flats) flats, so a repeated run with
+// the same seed leaves already seeded houses untouched. Flats are owned by
+// clients in turn. Approved flats expire after the listing TTL, expired ones
+// expired a day ago and deals are closed now.
+func insertFlats(tx *sql.Tx, houseID int, flats []flatSeed, owners, moderators []userSeed) (int, error) {
+	var existing int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM flat WHERE house_id = $1`, houseID).Scan(&existing); err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	created := 0
+	for j := existing; j < len(flats); j++ {
+		f := flats[j]
+		var expiresAt, closedAt *time.Time
+		switch f.status {
+		case models.StatusApproved:
+			if configs.Envs.ListingTTLDays > 0 {
+				t := now.AddDate(0, 0, configs.Envs.ListingTTLDays)
+				expiresAt = &t
+			}
+		case models.StatusExpired:
+			t := now.AddDate(0, 0, -1)
+			expiresAt = &t
+		case models.StatusRented, models.StatusSold:
+			closedAt = &now
+		}
+		var ownerID, moderatorID *uuid.UUID
+		if len(owners) > 0 {
+			ownerID = &owners[j%len(owners)].id
+		}
+		if f.status != models.StatusCreated && len(moderators) > 0 {
+			moderatorID = &moderators[j%len(moderators)].id
+		}
+		_, err := tx.Exec(`
+			INSERT INTO flat (house_id, price, rooms, status, owner_id, moderator_id, number, floor, total_area, living_area, balcony,
+				listing_type, rent_period, deposit, expires_at, final_price, closed_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`,
+			houseID, f.price, f.rooms, f.status, ownerID, moderatorID, f.number, f.floor, f.totalArea, f.livingArea, f.balcony,
+			f.listingType, f.rentPeriod, f.deposit, expiresAt, f.finalPrice, closedAt)
+		if err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
+
+func insertSubscription(tx *sql.Tx, houseID int, email string) (int, error) {
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	res, err := tx.Exec(`
+		INSERT INTO subscriptions (house_id, email, created_at)
+		SELECT $1::varchar, $2::varchar, $3::timestamp
+		WHERE NOT EXISTS (
+			SELECT 1 FROM subscriptions WHERE house_id = $1::varchar AND email = $2::varchar
+		)`,
+		fmt.Sprint(houseID), email, currentTime)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
@@ -2,18 +2,47 @@ package configs
 
 import (
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
 	Port       string
+	GRPCPort   string
 	Host       string
 	DBPort     string
 	DBUser     string
 	DBPassword string
 	DBName     string
 	JWTSecret  string
+
+	AutoMigrate bool
+	DigestHour  int
+
+	// ListingTTLDays is how long an approved flat stays listed; zero keeps it
+	// listed forever. Owners are reminded ExpiryReminderDays before. The
+	// flat-expiration migration backfilled the default of 30 days, so keep
+	// them in sync.
+	ListingTTLDays     int
+	ExpiryReminderDays int
+
+	Moderation Moderation
+}
+
+// Moderation configures the rules new flats are checked against. Actions are
+// decline, high_risk or pass; a zero ratio or bound turns its rule off.
+type Moderation struct {
+	PriceMinRatio     float64
+	PriceMaxRatio     float64
+	MinComparables    int
+	PriceAction       string
+	RoomsMin          int
+	RoomsMax          int
+	RoomsAction       string
+	BannedWords       []string
+	BannedWordsAction string
 }
 
 var Envs = initConfig()
@@ -23,12 +52,31 @@ func initConfig() Config {
 
 	return Config{
 		Port:       getEnv("PORT", "8080"),
+		GRPCPort:   getEnv("GRPC_PORT", "9090"),
 		Host:       getEnv("DB_HOST", "localhost"),
 		DBPort:     getEnv("DB_PORT", "5436"),
 		DBUser:     getEnv("DB_USER", "postgres"),
 		DBPassword: getEnv("DB_PASSWORD", ""),
 		DBName:     getEnv("DB_NAME", "avitorent"),
 		JWTSecret:  getEnv("JWT_SECRET", "some-secret"),
+
+		AutoMigrate: getEnvAsBool("AUTO_MIGRATE", false),
+		DigestHour:  getEnvAsInt("DIGEST_HOUR", 6),
+
+		ListingTTLDays:     getEnvAsInt("LISTING_TTL_DAYS", 30),
+		ExpiryReminderDays: getEnvAsInt("EXPIRY_REMINDER_DAYS", 3),
+
+		Moderation: Moderation{
+			PriceMinRatio:     getEnvAsFloat("MODERATION_PRICE_MIN_RATIO", 0.1),
+			PriceMaxRatio:     getEnvAsFloat("MODERATION_PRICE_MAX_RATIO", 10),
+			MinComparables:    getEnvAsInt("MODERATION_MIN_COMPARABLES", 3),
+			PriceAction:       getEnv("MODERATION_PRICE_ACTION", "decline"),
+			RoomsMin:          getEnvAsInt("MODERATION_ROOMS_MIN", 1),
+			RoomsMax:          getEnvAsInt("MODERATION_ROOMS_MAX", 10),
+			RoomsAction:       getEnv("MODERATION_ROOMS_ACTION", "decline"),
+			BannedWords:       getEnvAsList("MODERATION_BANNED_WORDS", nil),
+			BannedWordsAction: getEnv("MODERATION_BANNED_WORDS_ACTION", "high_risk"),
+		},
 	}
 }
 
@@ -39,3 +87,54 @@ func getEnv(key, fallback string) string {
 
 	return fallback
 }
+
+func getEnvAsBool(key string, fallback bool) bool {
+	if value, ok := os.LookupEnv(key); ok {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fallback
+		}
+		return b
+	}
+
+	return fallback
+}
+
+func getEnvAsInt(key string, fallback int) int {
+	if value, ok := os.LookupEnv(key); ok {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fallback
+		}
+		return i
+	}
+
+	return fallback
+}
+
+func getEnvAsFloat(key string, fallback float64) float64 {
+	if value, ok := os.LookupEnv(key); ok {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fallback
+		}
+		return f
+	}
+
+	return fallback
+}
+
+// getEnvAsList splits a comma separated value, dropping empty items.
+func getEnvAsList(key string, fallback []string) []string {
+	if value, ok := os.LookupEnv(key); ok {
+		var list []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		return list
+	}
+
+	return fallback
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/delapaska/avito-rent/cmd/migrate/migrations"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// migrationLockID is the pg_advisory_lock key held by an instance applying
+// migrations on startup, so concurrent replicas wait instead of racing.
+const migrationLockID int64 = 7245120384
+
+func NewMigrationSource() (source.Driver, error) {
+	return iofs.New(migrations.FS, ".")
+}
+
+func NewMigrator(db *sql.DB) (*migrate.Migrate, error) {
+	src, err := NewMigrationSource()
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.NewWithInstance("iofs", src, "postgres", driver)
+}
+
+// MigrateUp applies all pending embedded migrations without closing db.
+// It refuses to run when the previous migration left the schema dirty.
+func MigrateUp(db *sql.DB) error {
+	ctx := context.Background()
+
+	lockConn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer lockConn.Close()
+
+	log.Println("Migrations: waiting for advisory lock")
+	if _, err := lockConn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return err
+	}
+	defer lockConn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockID)
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	src, err := NewMigrationSource()
+	if err != nil {
+		driver.Close()
+		return err
+	}
+	m, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		src.Close()
+		driver.Close()
+		return err
+	}
+	defer m.Close()
+
+	version, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("database is dirty at migration version %d, fix it with 'migrate force'", version)
+	}
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	version, _, err = m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return err
+	}
+	log.Println("Migrations: database is at version", version)
+	return nil
+}
@@ -15,38 +15,76 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/dummyLogin": {
+        "/audit": {
             "get": {
-                "description": "Получение JWT токена для dummy пользователя",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "Changes made through the API, newest first. Every filter is optional. Pass the id of the oldest received entry as before to get the previous page. Requires moderator access.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Audit"
                 ],
-                "summary": "Dummy login",
+                "summary": "Get Audit Log",
                 "parameters": [
                     {
-                        "enum": [
-                            "client",
-                            "moderator"
-                        ],
                         "type": "string",
-                        "example": "client",
-                        "description": "Type of the user",
-                        "name": "userType",
-                        "in": "query",
-                        "required": true
+                        "description": "User who made the change",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action, for example flat.moderate",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Kind of the changed entity, for example flat",
+                        "name": "entity_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identifier of the changed entity",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return changes made at or after this time, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return changes made before this time, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries, 100 by default and at most 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return entries older than this entry id",
+                        "name": "before",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successful login",
+                        "description": "Audit log retrieved",
                         "schema": {
-                            "$ref": "#/definitions/utils.LoginResponse"
+                            "$ref": "#/definitions/utils.AuditResponse"
                         }
                     },
                     "400": {
@@ -55,6 +93,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -64,46 +114,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/flat/create": {
-            "post": {
+        "/conversations": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new flat with provided details. Requires authorization for both moderator and client.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Conversations the current user takes part in as a client or as the owner of the flat, latest activity first, with the number of unread messages in each and in total. Requires authorization for both moderator and client.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Flat"
-                ],
-                "summary": "Create Flat",
-                "parameters": [
-                    {
-                        "description": "Flat details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.FlatPayload"
-                        }
-                    }
+                    "Messages"
                 ],
+                "summary": "Get Conversations",
                 "responses": {
-                    "201": {
-                        "description": "Flat created",
-                        "schema": {
-                            "$ref": "#/definitions/models.Flat"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
+                    "200": {
+                        "description": "Conversations retrieved",
                         "schema": {
-                            "$ref": "#/definitions/utils.ErrorResponse"
+                            "$ref": "#/definitions/utils.ConversationsResponse"
                         }
                     },
                     "401": {
@@ -121,46 +151,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/flat/update": {
-            "post": {
+        "/conversations/reported": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update the status of a flat. Requires moderator access.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Conversations reported for abuse, latest report first. Unread counts the messages their recipient has not read yet. Requires moderator access.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Flat"
-                ],
-                "summary": "Update Flat Status",
-                "parameters": [
-                    {
-                        "description": "Update status details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateStatusPayload"
-                        }
-                    }
+                    "Messages"
                 ],
+                "summary": "Get Reported Conversations",
                 "responses": {
                     "200": {
-                        "description": "Flat status updated",
-                        "schema": {
-                            "$ref": "#/definitions/models.Flat"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
+                        "description": "Conversations retrieved",
                         "schema": {
-                            "$ref": "#/definitions/utils.ErrorResponse"
+                            "$ref": "#/definitions/utils.ConversationsResponse"
                         }
                     },
                     "401": {
@@ -178,40 +188,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/house/create": {
-            "post": {
+        "/conversations/{id}/messages": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Create a new house. Requires moderator access.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Messages of the conversation, newest first. Pass the id of the oldest received message as before to get the previous page. Reading marks the messages sent to the current user as read. Moderators can read conversations reported for abuse.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "House"
+                    "Messages"
                 ],
-                "summary": "Create House",
+                "summary": "Get Messages",
                 "parameters": [
                     {
-                        "description": "House details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.HousePayload"
-                        }
+                        "type": "integer",
+                        "description": "Conversation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of messages, 50 by default and at most 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return messages older than this message id",
+                        "name": "before",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "House created",
+                    "200": {
+                        "description": "Messages retrieved",
                         "schema": {
-                            "$ref": "#/definitions/models.House"
+                            "$ref": "#/definitions/utils.MessagesResponse"
                         }
                     },
                     "400": {
@@ -226,6 +243,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Conversation not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -233,16 +256,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/house/{id}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieve flats for a specific house. Requires authorization for both moderator and client.",
+                "description": "Reply in a conversation. Only its client and the owner of the flat can write to it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -250,23 +271,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "House"
+                    "Messages"
                 ],
-                "summary": "Get House Flats",
+                "summary": "Send Message",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "House ID",
+                        "type": "integer",
+                        "description": "Conversation ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Message",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MessagePayload"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Flats retrieved",
+                    "201": {
+                        "description": "Message sent",
                         "schema": {
-                            "$ref": "#/definitions/utils.FlatsResponse"
+                            "$ref": "#/definitions/models.Message"
                         }
                     },
                     "400": {
@@ -281,6 +311,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Conversation not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -290,14 +326,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/house/{id}/subscribe": {
+        "/conversations/{id}/report": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Subscribe to updates for a specific house. Requires authorization for both moderator and client.",
+                "description": "Report abuse in a conversation. Reported conversations become readable for moderators. Only the first report of a conversation is kept.",
                 "consumes": [
                     "application/json"
                 ],
@@ -305,30 +341,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "House"
+                    "Messages"
                 ],
-                "summary": "Subscribe to House",
+                "summary": "Report Conversation",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "House ID",
+                        "type": "integer",
+                        "description": "Conversation ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Subscription details",
+                        "description": "Report",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SubscribePayload"
+                            "$ref": "#/definitions/models.ReportPayload"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Subscription successful",
+                    "200": {
+                        "description": "Conversation reported",
                         "schema": {
                             "$ref": "#/definitions/utils.SubscriptionResponse"
                         }
@@ -345,6 +381,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Conversation not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -354,9 +396,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/login": {
+        "/developers": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "All developers, ordered by name. Requires authorization for both moderator and client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Developers"
+                ],
+                "summary": "Get Developers",
+                "responses": {
+                    "200": {
+                        "description": "Developers retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/utils.DevelopersResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Login with user credentials",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Add a developer. Names are unique regardless of case. Requires moderator access.",
                 "consumes": [
                     "application/json"
                 ],
@@ -364,25 +446,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Developers"
                 ],
-                "summary": "Login",
+                "summary": "Create Developer",
                 "parameters": [
                     {
-                        "description": "Login request payload",
+                        "description": "Developer name",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.LoginUserPayload"
+                            "$ref": "#/definitions/models.DeveloperPayload"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Successful login",
+                    "201": {
+                        "description": "Developer created",
                         "schema": {
-                            "$ref": "#/definitions/utils.LoginResponse"
+                            "$ref": "#/definitions/models.Developer"
                         }
                     },
                     "400": {
@@ -397,8 +479,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Name is already taken",
                         "schema": {
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
@@ -412,35 +500,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/register": {
-            "post": {
-                "description": "Register a new user",
-                "consumes": [
-                    "application/json"
+        "/developers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
                 ],
+                "description": "Requires authorization for both moderator and client.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Developers"
                 ],
-                "summary": "Register",
+                "summary": "Get Developer",
                 "parameters": [
                     {
-                        "description": "Register request payload",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.RegisterUserPayload"
-                        }
+                        "type": "integer",
+                        "description": "Developer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User created successfully",
+                    "200": {
+                        "description": "Developer retrieved",
                         "schema": {
-                            "$ref": "#/definitions/utils.RegisterResponse"
+                            "$ref": "#/definitions/models.Developer"
                         }
                     },
                     "400": {
@@ -449,8 +537,300 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "User already exists",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Developer not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Rename the developer. Its houses show the new name. Requires moderator access.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Developers"
+                ],
+                "summary": "Update Developer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Developer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Developer name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DeveloperPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Developer updated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Developer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Developer not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Name is already taken",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Remove a developer. Developers that still have houses, archived ones included, cannot be removed. Requires moderator access.",
+                "tags": [
+                    "Developers"
+                ],
+                "summary": "Delete Developer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Developer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Developer deleted"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Developer not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Developer has houses",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/developers/{id}/houses": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Houses built by the developer. Archived houses are only returned to moderators. Requires authorization for both moderator and client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Developers"
+                ],
+                "summary": "Get Developer Houses",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Developer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Houses retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/utils.HousesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Developer not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/dummyLogin": {
+            "get": {
+                "description": "Получение JWT токена для dummy пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Dummy login",
+                "parameters": [
+                    {
+                        "enum": [
+                            "client",
+                            "moderator"
+                        ],
+                        "type": "string",
+                        "example": "client",
+                        "description": "Type of the user",
+                        "name": "userType",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful login",
+                        "schema": {
+                            "$ref": "#/definitions/utils.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/favorites": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Flats bookmarked by the current user, newest first. Flats that are no longer approved stay bookmarked but are only shown to moderators. Requires authorization for both moderator and client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Favorites"
+                ],
+                "summary": "Get Favorites",
+                "responses": {
+                    "200": {
+                        "description": "Favorites retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/utils.FavoritesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/utils.ErrorResponse"
                         }
@@ -463,180 +843,3350 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Flat": {
+        },
+        "/flat/create": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a new flat with provided details. Number, floor, areas and balcony are optional; the number has to be unique within the house, the floor cannot be higher than the floors of the house and the living area cannot exceed the total area. Flats are for sale unless listing_type is rent; rentals need a rent_period and may have a deposit, a commission and utilities_included, which sales cannot have. Active flats of the house that look like the new one are recorded as its likely duplicates for moderators. New flats are checked against the pre-moderation rules: a flat breaking a declining rule is created declined with a decline_reason, one breaking a high risk rule is shown to moderators first. Requires authorization for both moderator and client.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Flat"
+                ],
+                "summary": "Create Flat",
+                "parameters": [
+                    {
+                        "description": "Flat details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FlatPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Flat created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Flat"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "House not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Flat number is already taken in the house or the house is archived",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flat/update": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update the status of a flat. Taking a flat into moderation also returns duplicate_of, the flats of the same house that looked like the same flat when it was created. Approving a flat sets expires_at, after which the flat gets the expired status until its owner renews it. Moderation statuses require moderator access. The owner of an approved or expired flat marks it as rented, for rentals, or sold, for sales, with an optional final_price that defaults to the listed price; such flats are hidden from clients.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Flat"
+                ],
+                "summary": "Update Flat Status",
+                "parameters": [
+                    {
+                        "description": "Update status details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateStatusPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Flat status updated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Flat"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not a moderator, not the assigned moderator or not the owner of the flat",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Flat not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Flat is archived or neither approved nor expired",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flat/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a flat with the summary of its house. Clients see approved flats that are not archived and their own flats in any status; moderators see every flat. Flats the user cannot see are reported as not found. Requires authorization for both moderator and client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Flat"
+                ],
+                "summary": "Get Flat",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Flat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Flat with its house",
+                        "schema": {
+                            "$ref": "#/definitions/models.FlatWithHouse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Flat not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flat/{id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Withdraw a flat from listings. Archived flats are hidden from GET /house/{id} and cannot be moderated. Available to the client who created the flat and to moderators.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Flat"
+                ],
+                "summary": "Archive Flat",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Flat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Flat archived",
+                        "schema": {
+                            "$ref": "#/definitions/models.Flat"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the flat",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Flat not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Flat is already archived",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flat/{id}/favorite": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Bookmark a flat. Clients can only bookmark approved flats. Adding a flat twice has no effect. Requires authorization for both moderator and client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Favorites"
+                ],
+                "summary": "Add Favorite",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Flat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Flat added to favorites",
+                        "schema": {
+                            "$ref": "#/definitions/utils.SubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Flat not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Remove a flat from the favorites of the current user. Requires authorization for both moderator and client.",
+                "tags": [
+                    "Favorites"
+                ],
+                "summary": "Remove Favorite",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Flat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Flat removed from favorites"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Flat is not in favorites",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flat/{id}/favorites": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Number of users who bookmarked the flat. Available to moderators and to the client who created the flat.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Favorites"
+                ],
+                "summary": "Get Flat Favorites",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Flat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Favorite count retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/models.FlatFavorites"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the flat",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Flat not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flat/{id}/messages": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Send a message to the client who created the flat. The first message starts a conversation about the flat, later ones are added to it. Clients can only contact owners of approved flats. Requires authorization for both moderator and client.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Contact Owner",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Flat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MessagePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Message sent",
+                        "schema": {
+                            "$ref": "#/definitions/models.Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Flat not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flat/{id}/renew": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Send a flat whose listing expired back to moderation. The flat gets the created status and is listed again once a moderator approves it. Available to the client who created the flat.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Flat"
+                ],
+                "summary": "Renew Flat",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Flat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Flat sent to moderation",
+                        "schema": {
+                            "$ref": "#/definitions/models.Flat"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the flat",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Flat not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Listing of the flat has not expired or the flat is archived",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flat/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Return an archived flat to listings with the status it had. Flats of an archived house can only be restored together with the house. Available to the client who created the flat and to moderators.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Flat"
+                ],
+                "summary": "Restore Flat",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Flat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Flat restored",
+                        "schema": {
+                            "$ref": "#/definitions/models.Flat"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the flat",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Flat not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Flat is not archived or its house is archived",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flat/{id}/slots": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Upcoming viewing slots of the flat, in time order. Clients see the slots of approved flats and of their own ones. Requires authorization for both moderator and client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Viewings"
+                ],
+                "summary": "Get Viewing Slots",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Flat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Slots retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ViewingSlotsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Flat not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Add a time when the flat can be shown. Slots are up to 4 hours long and cannot overlap other slots of the flat. Only the client who created the flat can add slots.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Viewings"
+                ],
+                "summary": "Create Viewing Slot",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Flat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Slot time",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ViewingSlotPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Slot created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ViewingSlot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the flat",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Flat not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Slot overlaps another slot",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flat/{id}/slots/{slot_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Remove a viewing slot nobody has booked. Only the client who created the flat can remove slots.",
+                "tags": [
+                    "Viewings"
+                ],
+                "summary": "Delete Viewing Slot",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Flat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Slot ID",
+                        "name": "slot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Slot deleted"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the flat",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Flat or slot not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Slot is booked",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flat/{id}/slots/{slot_id}/book": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Book a viewing slot of the flat. A slot can be booked by one client at a time. The confirmation and a reminder a day before are emailed to the given address, which defaults to the one the user registered with. Requires authorization for both moderator and client.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Viewings"
+                ],
+                "summary": "Book Viewing",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Flat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Slot ID",
+                        "name": "slot_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Notification settings",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.BookViewingPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Viewing booked",
+                        "schema": {
+                            "$ref": "#/definitions/models.Viewing"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Flat or slot not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Slot is already booked",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/flat/{id}/viewings": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Upcoming booked viewings of the flat, in time order. Only the client who created the flat can list them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Viewings"
+                ],
+                "summary": "Get Flat Viewings",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Flat ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Viewings retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ViewingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the owner of the flat",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Flat not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Run a GraphQL query or mutation over houses, flats and subscriptions. Requires authorization for both moderator and client; clients only see approved flats.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GraphQL"
+                ],
+                "summary": "GraphQL",
+                "parameters": [
+                    {
+                        "description": "GraphQL request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GraphQLPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GraphQL response with data and errors",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/house/create": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create a new house. The developer is given by developer_id or by name; a name that is not in /developers yet adds a developer. Floors, material, elevator and parking are optional. Requires moderator access.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "House"
+                ],
+                "summary": "Create House",
+                "parameters": [
+                    {
+                        "description": "House details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.HousePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "House created",
+                        "schema": {
+                            "$ref": "#/definitions/models.House"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/house/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieve flats for a specific house, optionally only those for sale or for rent or within a price per square meter range, and optionally sorted by price per square meter. Flats without an area are left out of the range and come last in the order. Archived flats are left out unless a moderator asks for them with include_archived. Requires authorization for both moderator and client.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "House"
+                ],
+                "summary": "Get House Flats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "House ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also return archived flats, moderators only",
+                        "name": "include_archived",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sale",
+                            "rent"
+                        ],
+                        "type": "string",
+                        "description": "Only return flats for sale or for rent",
+                        "name": "listing_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only return flats with at least this price per square meter",
+                        "name": "price_per_m2_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only return flats with at most this price per square meter",
+                        "name": "price_per_m2_max",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "price_per_m2",
+                            "-price_per_m2"
+                        ],
+                        "type": "string",
+                        "description": "Sort by price per square meter, ascending or, with a minus, descending",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Flats retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/utils.FlatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Archived flats requested by a client",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/house/{id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Archive a house together with its active flats. Archived flats are hidden from GET /house/{id} and cannot be moderated. Requires moderator access.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "House"
+                ],
+                "summary": "Archive House",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "House ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "House archived",
+                        "schema": {
+                            "$ref": "#/definitions/models.House"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "House not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "House is already archived",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/house/{id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Server-Sent Events stream of flat changes in a house: flat_created, flat_approved and flat_price_changed. Clients only receive events about approved flats. Send Last-Event-ID (or the lastEventId query parameter) to replay events missed since that id. Events created up to a minute before it are replayed too, because they may have committed later, so a resumed stream can repeat events; deduplicate them by id. Requires authorization for both moderator and client.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "House"
+                ],
+                "summary": "House Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "House ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id of the last received event",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id of the last received event, for clients that cannot set headers",
+                        "name": "lastEventId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of events",
+                        "schema": {
+                            "$ref": "#/definitions/models.HouseEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/house/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Restore an archived house and the flats archived together with it. Flats archived by their owners before stay archived. Requires moderator access.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "House"
+                ],
+                "summary": "Restore House",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "House ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "House restored",
+                        "schema": {
+                            "$ref": "#/definitions/models.House"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "House not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "House is not archived",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/house/{id}/subscribe": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Subscribe to updates for a specific house. Requires authorization for both moderator and client.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "House"
+                ],
+                "summary": "Subscribe to House",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "House ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscription details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SubscribePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Subscription successful",
+                        "schema": {
+                            "$ref": "#/definitions/utils.SubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Login with user credentials",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Login",
+                "parameters": [
+                    {
+                        "description": "Login request payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginUserPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful login",
+                        "schema": {
+                            "$ref": "#/definitions/utils.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Register a new user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Register",
+                "parameters": [
+                    {
+                        "description": "Register request payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RegisterUserPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/utils.RegisterResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "User already exists",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/searches": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "List saved searches of the current user. Requires authorization for both moderator and client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Searches"
+                ],
+                "summary": "Get Saved Searches",
+                "responses": {
+                    "200": {
+                        "description": "Saved searches retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/utils.SavedSearchesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Save search criteria. Every newly approved flat matching all set criteria is emailed to the user. Matches are sent to the email the user registered with, so users from /dummyLogin cannot save searches. Requires authorization for both moderator and client.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Searches"
+                ],
+                "summary": "Create Saved Search",
+                "parameters": [
+                    {
+                        "description": "Search criteria",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedSearchPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Saved search created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedSearch"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/searches/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a saved search of the current user. Requires authorization for both moderator and client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Searches"
+                ],
+                "summary": "Get Saved Search",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Saved search ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Saved search retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedSearch"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Saved search not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Replace the criteria of a saved search of the current user. Requires authorization for both moderator and client.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Searches"
+                ],
+                "summary": "Update Saved Search",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Saved search ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Search criteria",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedSearchPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Saved search updated",
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedSearch"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Saved search not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a saved search of the current user. Requires authorization for both moderator and client.",
+                "tags": [
+                    "Searches"
+                ],
+                "summary": "Delete Saved Search",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Saved search ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Saved search deleted"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Saved search not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/viewings": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Viewings booked by the current user, latest first, including cancelled ones. Requires authorization for both moderator and client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Viewings"
+                ],
+                "summary": "Get Viewings",
+                "responses": {
+                    "200": {
+                        "description": "Viewings retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ViewingsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/viewings/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Cancel a booked viewing, freeing its slot for other clients. Both the client who booked it and the owner of the flat can cancel; the client is notified by email.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Viewings"
+                ],
+                "summary": "Cancel Viewing",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Viewing ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Viewing cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/models.Viewing"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Viewing not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Viewing is already cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "List webhooks registered by the current user. Requires authorization for both moderator and client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get Webhooks",
+                "responses": {
+                    "200": {
+                        "description": "Webhooks retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/utils.WebhooksResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Register an endpoint that receives flat events as signed JSON POST requests. Every request carries the X-Avito-Event, X-Avito-Delivery, X-Avito-Timestamp and X-Avito-Signature headers; the signature is \"sha256=\" followed by the hex HMAC-SHA256 of \"\u003ctimestamp\u003e.\u003cbody\u003e\" keyed with the secret. Failed deliveries are retried with exponential backoff, and the webhook is disabled after repeated failures. The URL has to use https and its host has to resolve to public addresses; loopback, private, link-local and other special-purpose addresses, including carrier-grade NAT and IPv4-mapped or NAT64 forms of them, are rejected here and again when delivering. Redirects are not followed. The secret is generated when omitted and is only returned in this response. Clients only receive events about approved flats. Requires authorization for both moderator and client.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Create Webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Webhook created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete a webhook of the current user together with its delivery log. Requires authorization for both moderator and client.",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Delete Webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Webhook deleted"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delivery attempts of a webhook, newest first. Requires authorization for both moderator and client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get Webhook Deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of attempts, 50 by default and at most 200",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery attempts retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/utils.WebhookDeliveriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/enable": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Re-enable a webhook that was disabled after repeated delivery failures and reset its failure counter. Events that happen while the webhook is disabled are not delivered. Requires authorization for both moderator and client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Enable Webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook enabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "@Description What was done\n@Example \"flat.moderate\"",
+                    "type": "string"
+                },
+                "actor_id": {
+                    "description": "@Description User who made the change, absent for registration\n@Example \"b540a379-94ac-4eee-8c4e-83faf2f2d508\"",
+                    "type": "string"
+                },
+                "actor_role": {
+                    "description": "@Description Role of the user\n@Example \"moderator\"",
+                    "type": "string"
+                },
+                "after": {
+                    "description": "@Description Entity after the change, absent for deleted entities",
+                    "type": "object"
+                },
+                "before": {
+                    "description": "@Description Entity before the change, absent for created entities",
+                    "type": "object"
+                },
+                "client_ip": {
+                    "description": "@Description Address of the client\n@Example \"203.0.113.7\"",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "@Description Date and time of the change\n@Example \"2024-08-19T09:00:00Z\"",
+                    "type": "string"
+                },
+                "entity_id": {
+                    "description": "@Description Identifier of the changed entity\n@Example \"10\"",
+                    "type": "string"
+                },
+                "entity_type": {
+                    "description": "@Description Kind of the changed entity\n@Example \"flat\"",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the entry, used as the pagination cursor\n@Example 1001",
+                    "type": "integer"
+                },
+                "request_id": {
+                    "description": "@Description Identifier of the request that made the change\n@Example \"8f14e45fceea167a5a36dedd4bea2543\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BookViewingPayload": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "@Description Email the confirmation and the reminder are sent to\n@Example \"user@example.com\"",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "locale": {
+                    "description": "@Description Language of the notifications, ru by default\n@Example \"ru\"",
+                    "type": "string",
+                    "enum": [
+                        "ru",
+                        "en"
+                    ]
+                }
+            }
+        },
+        "models.Conversation": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "description": "@Description Client who started the conversation\n@Example \"a4b4a122-11c1-4b52-bd95-4a5d3c4be616\"",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "@Description Date and time when the conversation was started\n@Example \"2024-08-17T09:00:00Z\"",
+                    "type": "string"
+                },
+                "flat": {
+                    "description": "@Description Flat the conversation is about",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Flat"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the conversation\n@Example 1",
+                    "type": "integer"
+                },
+                "last_message_at": {
+                    "description": "@Description Date and time of the last message\n@Example \"2024-08-17T09:30:00Z\"",
+                    "type": "string"
+                },
+                "owner_id": {
+                    "description": "@Description Owner of the flat\n@Example \"b540a379-94ac-4eee-8c4e-83faf2f2d508\"",
+                    "type": "string"
+                },
+                "report_reason": {
+                    "description": "@Description Reason given in the abuse report\n@Example \"Spam\"",
+                    "type": "string"
+                },
+                "reported_at": {
+                    "description": "@Description Date and time when a participant reported abuse\n@Example \"2024-08-17T10:00:00Z\"",
+                    "type": "string"
+                },
+                "unread": {
+                    "description": "@Description Number of messages the current user has not read yet\n@Example 2",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Developer": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "@Description Date and time when the developer was added\n@Example \"2024-08-20T09:00:00Z\"",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the developer\n@Example 3",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "@Description Name of the developer, unique regardless of case\n@Example \"ПИК\"",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "@Description Date and time when the developer was last renamed\n@Example \"2024-08-20T09:00:00Z\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.DeveloperPayload": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "@Description Name of the developer\n@Example \"ПИК\"",
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "models.Favorite": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "@Description Date and time when the flat was bookmarked\n@Example \"2024-08-15T09:00:00Z\"",
+                    "type": "string"
+                },
+                "flat": {
+                    "description": "@Description Bookmarked flat",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Flat"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.Flat": {
+            "type": "object",
+            "properties": {
+                "archived_at": {
+                    "description": "@Description Date and time when the flat was archived, absent for active flats\n@Example \"2024-08-18T09:00:00Z\"",
+                    "type": "string"
+                },
+                "balcony": {
+                    "description": "@Description Whether the flat has a balcony; absent when not given\n@Example true",
+                    "type": "boolean"
+                },
+                "closed_at": {
+                    "description": "@Description Date and time when the owner marked the flat as rented or sold\n@Example \"2024-09-10T09:00:00Z\"",
+                    "type": "string"
+                },
+                "commission": {
+                    "description": "@Description Agent commission in percent of the rent, only for rentals; absent when not given\n@Example 50",
+                    "type": "integer"
+                },
+                "decline_reason": {
+                    "description": "@Description Why pre-moderation declined the flat\n@Example \"price 1 is below 10% of the median price 12500000 of the house\"",
+                    "type": "string"
+                },
+                "deposit": {
+                    "description": "@Description Deposit, only for rentals; absent when not given\n@Example 1200",
+                    "type": "integer"
+                },
+                "description": {
+                    "description": "@Description Description of the flat; absent when not given\n@Example \"Светлая квартира с видом на парк\"",
+                    "type": "string"
+                },
+                "duplicate_of": {
+                    "description": "@Description Flats of the same house that look like the same flat, found when it was created; only shown to the moderator taking the flat into moderation\n@Example [7, 12]",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "expires_at": {
+                    "description": "@Description Date and time when the listing of an approved flat expires; absent when it does not\n@Example \"2024-09-25T09:00:00Z\"",
+                    "type": "string"
+                },
+                "final_price": {
+                    "description": "@Description Price the flat was rented out or sold for; absent until then\n@Example 1150",
+                    "type": "integer"
+                },
+                "floor": {
+                    "description": "@Description Floor of the flat; absent when not given\n@Example 5",
+                    "type": "integer"
+                },
+                "high_risk": {
+                    "description": "@Description Whether pre-moderation asked to review the flat first; only shown to moderators\n@Example true",
+                    "type": "boolean"
+                },
+                "house_id": {
+                    "description": "@Description Unique identifier for the house to which the flat belongs\n@Example 101",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "@Description Unique identifier for the flat\n@Example 1",
+                    "type": "integer"
+                },
+                "listing_type": {
+                    "description": "@Description Whether the flat is for sale or for rent\n@Enum sale,rent\n@Example \"rent\"",
+                    "type": "string"
+                },
+                "living_area": {
+                    "description": "@Description Living area in square meters; absent when not given\n@Example 41.2",
+                    "type": "number"
+                },
+                "number": {
+                    "description": "@Description Number of the flat, unique within the house; absent when not given\n@Example 42",
+                    "type": "integer"
+                },
+                "price": {
+                    "description": "@Description Price of the flat; for rentals, the rent per rent_period\n@Example 1200",
+                    "type": "integer"
+                },
+                "price_per_m2": {
+                    "description": "@Description Price per square meter of the total area, computed by the database; absent when the area is not given\n@Example 18.6",
+                    "type": "number"
+                },
+                "rent_period": {
+                    "description": "@Description Period the rent is charged for, only for rentals\n@Enum monthly,daily\n@Example \"monthly\"",
+                    "type": "string"
+                },
+                "rooms": {
+                    "description": "@Description Number of rooms in the flat\n@Example 3",
+                    "type": "integer"
+                },
+                "rule_hits": {
+                    "description": "@Description Rules the flat broke when it was created; only shown to moderators",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RuleHit"
+                    }
+                },
+                "status": {
+                    "description": "@Description Status of the flat\n@Example \"created\"",
+                    "type": "string"
+                },
+                "total_area": {
+                    "description": "@Description Total area in square meters; absent when not given\n@Example 64.5",
+                    "type": "number"
+                },
+                "utilities_included": {
+                    "description": "@Description Whether utilities are included in the rent, only for rentals; absent when not given\n@Example false",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.FlatFavorites": {
+            "type": "object",
+            "properties": {
+                "favorites": {
+                    "description": "@Description Number of users who bookmarked the flat\n@Example 12",
+                    "type": "integer"
+                },
+                "flat_id": {
+                    "description": "@Description Unique identifier of the flat\n@Example 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FlatPayload": {
+            "type": "object",
+            "required": [
+                "house_id",
+                "price",
+                "rooms"
+            ],
+            "properties": {
+                "balcony": {
+                    "description": "@Description Whether the flat has a balcony\n@Example true",
+                    "type": "boolean"
+                },
+                "commission": {
+                    "description": "@Description Agent commission in percent of the rent, only for rentals\n@Example 50",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "deposit": {
+                    "description": "@Description Deposit, only for rentals\n@Example 1200",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "description": {
+                    "description": "@Description Description of the flat\n@Example \"Светлая квартира с видом на парк\"",
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "floor": {
+                    "description": "@Description Floor of the flat, not higher than the number of floors of the house\n@Example 5",
+                    "type": "integer",
+                    "maximum": 200,
+                    "minimum": 1
+                },
+                "house_id": {
+                    "description": "@Description Unique identifier of the house to which the flat belongs\n@Example 101",
+                    "type": "integer"
+                },
+                "listing_type": {
+                    "description": "@Description Whether the flat is for sale or for rent, sale by default\n@Enum sale,rent\n@Example \"rent\"",
+                    "type": "string",
+                    "enum": [
+                        "sale",
+                        "rent"
+                    ]
+                },
+                "living_area": {
+                    "description": "@Description Living area in square meters, not larger than the total area\n@Example 41.2",
+                    "type": "number",
+                    "maximum": 10000
+                },
+                "number": {
+                    "description": "@Description Number of the flat, unique within the house\n@Example 42",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "price": {
+                    "description": "@Description Price of the flat; for rentals, the rent per rent_period\n@Example 1200",
+                    "type": "integer"
+                },
+                "rent_period": {
+                    "description": "@Description Period the rent is charged for, required for rentals and not allowed for sales\n@Enum monthly,daily\n@Example \"monthly\"",
+                    "type": "string",
+                    "enum": [
+                        "monthly",
+                        "daily"
+                    ]
+                },
+                "rooms": {
+                    "description": "@Description Number of rooms in the flat\n@Example 3",
+                    "type": "integer"
+                },
+                "total_area": {
+                    "description": "@Description Total area in square meters\n@Example 64.5",
+                    "type": "number",
+                    "maximum": 10000
+                },
+                "utilities_included": {
+                    "description": "@Description Whether utilities are included in the rent, only for rentals\n@Example false",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.FlatWithHouse": {
+            "type": "object",
+            "properties": {
+                "flat": {
+                    "description": "@Description The flat",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Flat"
+                        }
+                    ]
+                },
+                "house": {
+                    "description": "@Description Summary of the house of the flat",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.HouseSummary"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.GraphQLPayload": {
+            "type": "object",
+            "required": [
+                "query"
+            ],
+            "properties": {
+                "operationName": {
+                    "description": "@Description Operation to run when the query contains several",
+                    "type": "string"
+                },
+                "query": {
+                    "description": "@Description GraphQL query or mutation",
+                    "type": "string"
+                },
+                "variables": {
+                    "description": "@Description Values of the query variables",
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "models.House": {
+            "description": "House представляет собой структуру данных для хранения информации о доме.",
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "@description Адрес дома\n@example \"123 Elm Street\"",
+                    "type": "string"
+                },
+                "archived_at": {
+                    "description": "@description Дата архивации дома, отсутствует у действующих домов\n@example \"2024-08-18T00:00:00Z\"",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "@description Дата создания записи\n@example \"2024-08-04T00:00:00Z\"",
+                    "type": "string"
+                },
+                "developer": {
+                    "description": "@description Разработчик или строитель дома\n@example \"XYZ Construction\"",
+                    "type": "string"
+                },
+                "developer_id": {
+                    "description": "@description Идентификатор застройщика, отсутствует у домов без застройщика\n@example 3",
+                    "type": "integer"
+                },
+                "elevator": {
+                    "description": "@description Есть ли в доме лифт, отсутствует, если не указано\n@example true",
+                    "type": "boolean"
+                },
+                "floors": {
+                    "description": "@description Количество этажей, отсутствует, если не указано\n@example 17",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "@description Идентификатор дома\n@example 1",
+                    "type": "integer"
+                },
+                "material": {
+                    "description": "@description Материал стен, отсутствует, если не указан\n@enum brick,panel,monolith,monolith_brick,block,wood\n@example \"panel\"",
+                    "type": "string"
+                },
+                "parking": {
+                    "description": "@description Есть ли у дома парковка, отсутствует, если не указано\n@example false",
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "description": "@description Дата последнего обновления записи\n@example \"2024-08-04T00:00:00Z\"",
+                    "type": "string"
+                },
+                "year": {
+                    "description": "@description Год постройки\n@example 2020",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HouseEvent": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "@Description Date and time of the change\n@Example \"2024-08-10T12:00:00Z\"",
+                    "type": "string"
+                },
+                "flat": {
+                    "description": "@Description State of the flat after the change",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Flat"
+                        }
+                    ]
+                },
+                "house_id": {
+                    "description": "@Description Identifier of the house\n@Example 1",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "@Description Event identifier, used as the SSE event id\n@Example 42",
+                    "type": "integer"
+                },
+                "old_price": {
+                    "description": "@Description Previous price for flat_price_changed events\n@Example 10000000",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "@Description Kind of the event\n@Enum flat_created,flat_approved,flat_price_changed\n@Example \"flat_price_changed\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.HousePayload": {
+            "description": "HousePayload представляет собой структуру данных для создания или обновления информации о доме.",
+            "type": "object",
+            "required": [
+                "address",
+                "year"
+            ],
+            "properties": {
+                "address": {
+                    "description": "@description Адрес дома\n@example \"123 Elm Street\"",
+                    "type": "string"
+                },
+                "developer": {
+                    "description": "@description Название застройщика; если такого ещё нет, он будет создан. Нельзя передавать вместе с developer_id\n@example \"XYZ Construction\"",
+                    "type": "string"
+                },
+                "developer_id": {
+                    "description": "@description Идентификатор застройщика из /developers\n@example 3",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "elevator": {
+                    "description": "@description Есть ли в доме лифт\n@example true",
+                    "type": "boolean"
+                },
+                "floors": {
+                    "description": "@description Количество этажей\n@example 17",
+                    "type": "integer",
+                    "maximum": 200,
+                    "minimum": 1
+                },
+                "material": {
+                    "description": "@description Материал стен\n@enum brick,panel,monolith,monolith_brick,block,wood\n@example \"panel\"",
+                    "type": "string",
+                    "enum": [
+                        "brick",
+                        "panel",
+                        "monolith",
+                        "monolith_brick",
+                        "block",
+                        "wood"
+                    ]
+                },
+                "parking": {
+                    "description": "@description Есть ли у дома парковка\n@example false",
+                    "type": "boolean"
+                },
+                "year": {
+                    "description": "@description Год постройки\n@example 2020",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HouseSummary": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "@Description Address of the house\n@Example \"Лесная улица, 7, Москва\"",
+                    "type": "string"
+                },
+                "developer": {
+                    "description": "@Description Name of the developer of the house; absent when not known\n@Example \"ПИК\"",
+                    "type": "string"
+                },
+                "floors": {
+                    "description": "@Description Number of floors; absent when not given\n@Example 17",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the house\n@Example 101",
+                    "type": "integer"
+                },
+                "material": {
+                    "description": "@Description Material of the walls; absent when not given\n@Example \"panel\"",
+                    "type": "string"
+                },
+                "year": {
+                    "description": "@Description Year the house was built\n@Example 2003",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LoginUserPayload": {
+            "description": "Payload for user login",
+            "type": "object",
+            "required": [
+                "id",
+                "password"
+            ],
+            "properties": {
+                "id": {
+                    "description": "@example f47ac10b-58cc-4372-a567-0e02b2c3d479",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "@example securePassword123",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Message": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "description": "@Description Text of the message\n@Example \"Здравствуйте! Квартира ещё сдаётся?\"",
+                    "type": "string"
+                },
+                "conversation_id": {
+                    "description": "@Description Conversation the message belongs to\n@Example 1",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "@Description Date and time when the message was sent\n@Example \"2024-08-17T09:00:00Z\"",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the message, used as the pagination cursor\n@Example 42",
+                    "type": "integer"
+                },
+                "read_at": {
+                    "description": "@Description Date and time when the recipient read the message\n@Example \"2024-08-17T09:05:00Z\"",
+                    "type": "string"
+                },
+                "sender_id": {
+                    "description": "@Description Author of the message\n@Example \"a4b4a122-11c1-4b52-bd95-4a5d3c4be616\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.MessagePayload": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "description": "@Description Text of the message\n@Example \"Здравствуйте! Квартира ещё сдаётся?\"",
+                    "type": "string",
+                    "maxLength": 4000
+                }
+            }
+        },
+        "models.RegisterUserPayload": {
+            "description": "Payload for user registration",
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "userType"
+            ],
+            "properties": {
+                "email": {
+                    "description": "@example user@example.com",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "@example securePassword123",
+                    "type": "string",
+                    "maxLength": 16,
+                    "minLength": 3
+                },
+                "userType": {
+                    "description": "@example client",
+                    "type": "string",
+                    "enum": [
+                        "client",
+                        "moderator"
+                    ]
+                }
+            }
+        },
+        "models.ReportPayload": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "@Description What is wrong with the conversation\n@Example \"Spam\"",
+                    "type": "string",
+                    "maxLength": 1000
+                }
+            }
+        },
+        "models.RuleHit": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "@Description Action the rule is configured with\n@Enum decline,high_risk,pass\n@Example \"decline\"",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "@Description Why the flat broke the rule\n@Example \"50 rooms, at most 10 allowed\"",
+                    "type": "string"
+                },
+                "rule": {
+                    "description": "@Description Name of the rule: price, rooms or banned_words\n@Example \"rooms\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.SavedSearch": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "@Description Text the house address must contain, case-insensitive\n@Example \"Москва\"",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "@Description Date and time when the search was created\n@Example \"2024-08-14T09:00:00Z\"",
+                    "type": "string"
+                },
+                "developer": {
+                    "description": "@Description Developer of the house, case-insensitive\n@Example \"Мосстрой\"",
+                    "type": "string"
+                },
+                "email": {
+                    "description": "@Description Email the matches are sent to\n@Example \"user@example.com\"",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the saved search\n@Example 1",
+                    "type": "integer"
+                },
+                "locale": {
+                    "description": "@Description Language of the notifications\n@Example \"ru\"",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "@Description Name of the search shown in notifications\n@Example \"Двушка до 10 млн\"",
+                    "type": "string"
+                },
+                "price_max": {
+                    "description": "@Description Maximum price\n@Example 10000000",
+                    "type": "integer"
+                },
+                "price_min": {
+                    "description": "@Description Minimum price\n@Example 5000000",
+                    "type": "integer"
+                },
+                "rooms_max": {
+                    "description": "@Description Maximum number of rooms\n@Example 2",
+                    "type": "integer"
+                },
+                "rooms_min": {
+                    "description": "@Description Minimum number of rooms\n@Example 2",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "description": "@Description Date and time when the search was last changed\n@Example \"2024-08-14T09:00:00Z\"",
+                    "type": "string"
+                },
+                "year_max": {
+                    "description": "@Description Maximum year the house was built\n@Example 2024",
+                    "type": "integer"
+                },
+                "year_min": {
+                    "description": "@Description Minimum year the house was built\n@Example 2000",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SavedSearchPayload": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "address": {
+                    "description": "@Description Text the house address must contain, case-insensitive\n@Example \"Москва\"",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "developer": {
+                    "description": "@Description Developer of the house, case-insensitive\n@Example \"Мосстрой\"",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "locale": {
+                    "description": "@Description Language of the notifications, ru by default\n@Example \"ru\"",
+                    "type": "string",
+                    "enum": [
+                        "ru",
+                        "en"
+                    ]
+                },
+                "name": {
+                    "description": "@Description Name of the search shown in notifications\n@Example \"Двушка до 10 млн\"",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "price_max": {
+                    "description": "@Description Maximum price\n@Example 10000000",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "price_min": {
+                    "description": "@Description Minimum price\n@Example 5000000",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "rooms_max": {
+                    "description": "@Description Maximum number of rooms\n@Example 2",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "rooms_min": {
+                    "description": "@Description Minimum number of rooms\n@Example 2",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "year_max": {
+                    "description": "@Description Maximum year the house was built\n@Example 2024",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "year_min": {
+                    "description": "@Description Minimum year the house was built\n@Example 2000",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "models.SubscribePayload": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "delivery": {
+                    "description": "@Description instant for an email per approved flat (default), daily for one digest a day\n@Example \"daily\"",
+                    "type": "string",
+                    "enum": [
+                        "instant",
+                        "daily"
+                    ]
+                },
+                "email": {
+                    "description": "@Description Email address to subscribe to house updates\n@Example \"user@example.com\"",
+                    "type": "string"
+                },
+                "locale": {
+                    "description": "@Description Language of the notifications, ru by default\n@Example \"en\"",
+                    "type": "string",
+                    "enum": [
+                        "ru",
+                        "en"
+                    ]
+                }
+            }
+        },
+        "models.UpdateStatusPayload": {
             "type": "object",
+            "required": [
+                "id"
+            ],
             "properties": {
-                "house_id": {
-                    "description": "@Description Unique identifier for the house to which the flat belongs\n@Example 101",
+                "final_price": {
+                    "description": "@Description Price the flat was rented out or sold for, only with rented and sold; the listed price when omitted\n@Example 1150",
                     "type": "integer"
                 },
                 "id": {
-                    "description": "@Description Unique identifier for the flat\n@Example 1",
-                    "type": "integer"
-                },
-                "price": {
-                    "description": "@Description Price of the flat\n@Example 1200",
-                    "type": "integer"
-                },
-                "rooms": {
-                    "description": "@Description Number of rooms in the flat\n@Example 3",
+                    "description": "@Description Unique identifier of the flat to update\n@Example 1",
                     "type": "integer"
                 },
                 "status": {
-                    "description": "@Description Status of the flat\n@Example \"created\"",
+                    "description": "@Description Status to update the flat to; rented and sold are set by the owner of the flat\n@Enum created,approved,declined,on moderation,rented,sold\n@Example \"approved\"",
                     "type": "string"
                 }
             }
         },
-        "models.FlatPayload": {
+        "models.Viewing": {
             "type": "object",
-            "required": [
-                "house_id",
-                "price",
-                "rooms"
-            ],
             "properties": {
-                "house_id": {
-                    "description": "@Description Unique identifier of the house to which the flat belongs\n@Example 101",
-                    "type": "integer"
+                "cancelled_at": {
+                    "description": "@Description Date and time when the viewing was cancelled\n@Example \"2024-08-17T10:00:00Z\"",
+                    "type": "string"
                 },
-                "price": {
-                    "description": "@Description Price of the flat\n@Example 1200",
+                "created_at": {
+                    "description": "@Description Date and time when the viewing was booked\n@Example \"2024-08-16T10:00:00Z\"",
+                    "type": "string"
+                },
+                "email": {
+                    "description": "@Description Email the confirmation and the reminder are sent to\n@Example \"user@example.com\"",
+                    "type": "string"
+                },
+                "ends_at": {
+                    "description": "@Description End of the viewing\n@Example \"2024-08-20T15:30:00Z\"",
+                    "type": "string"
+                },
+                "flat": {
+                    "description": "@Description Flat to be shown",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Flat"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the viewing\n@Example 1",
                     "type": "integer"
                 },
-                "rooms": {
-                    "description": "@Description Number of rooms in the flat\n@Example 3",
+                "locale": {
+                    "description": "@Description Language of the notifications\n@Example \"ru\"",
+                    "type": "string"
+                },
+                "slot_id": {
+                    "description": "@Description Booked slot\n@Example 1",
                     "type": "integer"
+                },
+                "starts_at": {
+                    "description": "@Description Start of the viewing\n@Example \"2024-08-20T15:00:00Z\"",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "@Description Status of the viewing\n@Example \"booked\"",
+                    "type": "string"
                 }
             }
         },
-        "models.House": {
-            "description": "House представляет собой структуру данных для хранения информации о доме.",
+        "models.ViewingSlot": {
             "type": "object",
             "properties": {
-                "address": {
-                    "description": "@description Адрес дома\n@example \"123 Elm Street\"",
-                    "type": "string"
+                "booked": {
+                    "description": "@Description Whether a client has booked the slot\n@Example false",
+                    "type": "boolean"
                 },
                 "created_at": {
-                    "description": "@description Дата создания записи\n@example \"2024-08-04T00:00:00Z\"",
+                    "description": "@Description Date and time when the slot was added\n@Example \"2024-08-16T09:00:00Z\"",
                     "type": "string"
                 },
-                "developer": {
-                    "description": "@description Разработчик или строитель дома\n@example \"XYZ Construction\"",
+                "ends_at": {
+                    "description": "@Description End of the slot\n@Example \"2024-08-20T15:30:00Z\"",
                     "type": "string"
                 },
+                "flat_id": {
+                    "description": "@Description Flat shown during the slot\n@Example 10",
+                    "type": "integer"
+                },
                 "id": {
-                    "description": "@description Идентификатор дома\n@example 1",
+                    "description": "@Description Unique identifier of the slot\n@Example 1",
                     "type": "integer"
                 },
-                "updated_at": {
-                    "description": "@description Дата последнего обновления записи\n@example \"2024-08-04T00:00:00Z\"",
+                "starts_at": {
+                    "description": "@Description Start of the slot\n@Example \"2024-08-20T15:00:00Z\"",
                     "type": "string"
-                },
-                "year": {
-                    "description": "@description Год постройки\n@example 2020",
-                    "type": "integer"
                 }
             }
         },
-        "models.HousePayload": {
-            "description": "HousePayload представляет собой структуру данных для создания или обновления информации о доме.",
+        "models.ViewingSlotPayload": {
             "type": "object",
             "required": [
-                "address",
-                "year"
+                "ends_at",
+                "starts_at"
             ],
             "properties": {
-                "address": {
-                    "description": "@description Адрес дома\n@example \"123 Elm Street\"",
+                "ends_at": {
+                    "description": "@Description End of the slot, at most 4 hours after the start\n@Example \"2024-08-20T15:30:00Z\"",
                     "type": "string"
                 },
-                "developer": {
-                    "description": "@description Разработчик или строитель дома\n@example \"XYZ Construction\"",
+                "starts_at": {
+                    "description": "@Description Start of the slot, must be in the future\n@Example \"2024-08-20T15:00:00Z\"",
                     "type": "string"
-                },
-                "year": {
-                    "description": "@description Год постройки\n@example 2020",
-                    "type": "integer"
                 }
             }
         },
-        "models.LoginUserPayload": {
-            "description": "Payload for user login",
+        "models.Webhook": {
             "type": "object",
-            "required": [
-                "id",
-                "password"
-            ],
             "properties": {
+                "active": {
+                    "description": "@Description Whether events are delivered\n@Example true",
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "description": "@Description Date and time when the webhook was created\n@Example \"2024-08-11T09:00:00Z\"",
+                    "type": "string"
+                },
+                "disabled_at": {
+                    "description": "@Description When the webhook was disabled after repeated failures",
+                    "type": "string"
+                },
+                "event_types": {
+                    "description": "@Description Events to deliver\n@Example [\"flat_approved\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "failure_count": {
+                    "description": "@Description Consecutive failed attempts\n@Example 0",
+                    "type": "integer"
+                },
+                "house_ids": {
+                    "description": "@Description Houses to deliver events for, empty for all houses\n@Example [1, 2]",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "id": {
-                    "description": "@example f47ac10b-58cc-4372-a567-0e02b2c3d479",
+                    "description": "@Description Unique identifier of the webhook\n@Example 1",
+                    "type": "integer"
+                },
+                "secret": {
+                    "description": "@Description HMAC-SHA256 key, only returned when the webhook is created\n@Example \"5f1d7c9a...\"",
                     "type": "string"
                 },
-                "password": {
-                    "description": "@example securePassword123",
+                "url": {
+                    "description": "@Description Endpoint the events are POSTed to\n@Example \"https://partner.example.com/hooks/avito\"",
                     "type": "string"
                 }
             }
         },
-        "models.RegisterUserPayload": {
-            "description": "Payload for user registration",
+        "models.WebhookDeliveryAttempt": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "userType"
-            ],
             "properties": {
-                "email": {
-                    "description": "@example user@example.com",
+                "attempt": {
+                    "description": "@Description Attempt number, starting from 1\n@Example 2",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "@Description Date and time of the attempt\n@Example \"2024-08-11T09:00:10Z\"",
                     "type": "string"
                 },
-                "password": {
-                    "description": "@example securePassword123",
-                    "type": "string",
-                    "maxLength": 16,
-                    "minLength": 3
+                "delivery_id": {
+                    "description": "@Description Delivery the attempt belongs to, the same across retries\n@Example 5",
+                    "type": "integer"
                 },
-                "userType": {
-                    "description": "@example client",
-                    "type": "string",
-                    "enum": [
-                        "client",
-                        "moderator"
-                    ]
+                "duration_ms": {
+                    "description": "@Description Request duration in milliseconds\n@Example 120",
+                    "type": "integer"
+                },
+                "error": {
+                    "description": "@Description Reason of the failure\n@Example \"unexpected status 500\"",
+                    "type": "string"
+                },
+                "event_id": {
+                    "description": "@Description Delivered event\n@Example 42",
+                    "type": "integer"
+                },
+                "event_type": {
+                    "description": "@Description Kind of the delivered event\n@Example \"flat_approved\"",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@Description Unique identifier of the attempt\n@Example 10",
+                    "type": "integer"
+                },
+                "status_code": {
+                    "description": "@Description HTTP status returned by the endpoint, absent on network errors\n@Example 500",
+                    "type": "integer"
                 }
             }
         },
-        "models.SubscribePayload": {
+        "models.WebhookPayload": {
             "type": "object",
             "required": [
-                "email"
+                "event_types",
+                "url"
             ],
             "properties": {
-                "email": {
-                    "description": "@Description Email address to subscribe to house updates\n@Example \"user@example.com\"",
+                "event_types": {
+                    "description": "@Description Events to deliver\n@Example [\"flat_created\", \"flat_approved\"]",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "house_ids": {
+                    "description": "@Description Houses to deliver events for, empty for all houses\n@Example [1]",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "secret": {
+                    "description": "@Description HMAC-SHA256 key, generated when omitted\n@Example \"my-signing-secret\"",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 16
+                },
+                "url": {
+                    "description": "@Description Endpoint the events are POSTed to\n@Example \"https://partner.example.com/hooks/avito\"",
                     "type": "string"
                 }
             }
         },
-        "models.UpdateStatusPayload": {
+        "utils.AuditResponse": {
+            "description": "Response model for a page of the audit log",
             "type": "object",
-            "required": [
-                "id"
-            ],
             "properties": {
-                "id": {
-                    "description": "@Description Unique identifier of the flat to update\n@Example 1",
-                    "type": "integer"
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AuditEntry"
+                    }
+                }
+            }
+        },
+        "utils.ConversationsResponse": {
+            "description": "Response model for listing conversations",
+            "type": "object",
+            "properties": {
+                "conversations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Conversation"
+                    }
                 },
-                "status": {
-                    "description": "@Description Status to update the flat to\n@Enum created,approved,declined,on moderation\n@Example \"approved\"",
-                    "type": "string"
+                "unread": {
+                    "description": "Total number of unread messages, not set for reported conversations",
+                    "type": "integer"
+                }
+            }
+        },
+        "utils.DevelopersResponse": {
+            "description": "Response model for listing developers",
+            "type": "object",
+            "properties": {
+                "developers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Developer"
+                    }
                 }
             }
         },
@@ -656,6 +4206,18 @@ const docTemplate = `{
                 }
             }
         },
+        "utils.FavoritesResponse": {
+            "description": "Response model for the favorites of a user",
+            "type": "object",
+            "properties": {
+                "favorites": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Favorite"
+                    }
+                }
+            }
+        },
         "utils.FlatsResponse": {
             "description": "Response model for retrieving flats in a house",
             "type": "object",
@@ -668,6 +4230,18 @@ const docTemplate = `{
                 }
             }
         },
+        "utils.HousesResponse": {
+            "description": "Response model for listing houses",
+            "type": "object",
+            "properties": {
+                "houses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.House"
+                    }
+                }
+            }
+        },
         "utils.LoginResponse": {
             "description": "Successful login response structure",
             "type": "object",
@@ -678,6 +4252,18 @@ const docTemplate = `{
                 }
             }
         },
+        "utils.MessagesResponse": {
+            "description": "Response model for a page of messages",
+            "type": "object",
+            "properties": {
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Message"
+                    }
+                }
+            }
+        },
         "utils.RegisterResponse": {
             "description": "Successful registration response structure",
             "type": "object",
@@ -688,6 +4274,18 @@ const docTemplate = `{
                 }
             }
         },
+        "utils.SavedSearchesResponse": {
+            "description": "Response model for listing saved searches",
+            "type": "object",
+            "properties": {
+                "searches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SavedSearch"
+                    }
+                }
+            }
+        },
         "utils.SubscriptionResponse": {
             "description": "Response model for subscription confirmation",
             "type": "object",
@@ -702,6 +4300,54 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "utils.ViewingSlotsResponse": {
+            "description": "Response model for the viewing slots of a flat",
+            "type": "object",
+            "properties": {
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ViewingSlot"
+                    }
+                }
+            }
+        },
+        "utils.ViewingsResponse": {
+            "description": "Response model for listing viewings",
+            "type": "object",
+            "properties": {
+                "viewings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Viewing"
+                    }
+                }
+            }
+        },
+        "utils.WebhookDeliveriesResponse": {
+            "description": "Response model for the delivery log of a webhook",
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookDeliveryAttempt"
+                    }
+                }
+            }
+        },
+        "utils.WebhooksResponse": {
+            "description": "Response model for listing webhooks",
+            "type": "object",
+            "properties": {
+                "webhooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Webhook"
+                    }
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,97 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/delapaska/avito-rent/grpcapi/pb"
+	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/utils"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AuthServer struct {
+	pb.UnimplementedAuthServiceServer
+	store models.UserStore
+}
+
+func NewAuthServer(store models.UserStore) *AuthServer {
+	return &AuthServer{store: store}
+}
+
+func (s *AuthServer) DummyLogin(ctx context.Context, req *pb.DummyLoginRequest) (*pb.DummyLoginResponse, error) {
+	userType := userTypeFromPB(req.GetUserType())
+	if userType == "" {
+		return nil, status.Error(codes.InvalidArgument, "invalid userType")
+	}
+
+	token, err := middleware.GenerateJWT(uuid.New(), userType)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "could not generate token")
+	}
+
+	return &pb.DummyLoginResponse{Token: token}, nil
+}
+
+func (s *AuthServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+	if req.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+
+	u, err := s.store.GetUserById(id)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	if !middleware.ComparePasswords(u.Password, []byte(req.GetPassword())) {
+		return nil, status.Error(codes.Unauthenticated, "invalid credentials")
+	}
+
+	token, err := middleware.GenerateJWT(u.User_id, u.UserType)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "could not generate token")
+	}
+
+	return &pb.LoginResponse{Token: token}, nil
+}
+
+func (s *AuthServer) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
+	payload := models.RegisterUserPayload{
+		Email:    req.GetEmail(),
+		Password: req.GetPassword(),
+		UserType: userTypeFromPB(req.GetUserType()),
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if u, err := s.store.GetUserByEmail(payload.Email); err == nil && u.Email != "" {
+		return nil, status.Error(codes.AlreadyExists, "user already exists")
+	}
+
+	hashedPassword, err := middleware.HashPassword(payload.Password)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "could not hash password")
+	}
+
+	newUser := models.User{
+		User_id:  uuid.New(),
+		Email:    payload.Email,
+		Password: hashedPassword,
+		UserType: payload.UserType,
+	}
+	if err := s.store.CreateUser(newUser); err != nil {
+		return nil, status.Error(codes.Internal, "could not create user")
+	}
+
+	return &pb.RegisterResponse{User: &pb.User{
+		UserId:   newUser.User_id.String(),
+		Email:    newUser.Email,
+		UserType: userTypeToPB(newUser.UserType),
+	}}, nil
+}
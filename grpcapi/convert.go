@@ -0,0 +1,117 @@
+package grpcapi
+
+import (
+	"cmp"
+
+	"github.com/delapaska/avito-rent/grpcapi/pb"
+	"github.com/delapaska/avito-rent/models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var flatStatuses = map[string]pb.FlatStatus{
+	models.StatusCreated:      pb.FlatStatus_FLAT_STATUS_CREATED,
+	models.StatusOnModeration: pb.FlatStatus_FLAT_STATUS_ON_MODERATION,
+	models.StatusApproved:     pb.FlatStatus_FLAT_STATUS_APPROVED,
+	models.StatusDeclined:     pb.FlatStatus_FLAT_STATUS_DECLINED,
+	models.StatusExpired:      pb.FlatStatus_FLAT_STATUS_EXPIRED,
+	models.StatusRented:       pb.FlatStatus_FLAT_STATUS_RENTED,
+	models.StatusSold:         pb.FlatStatus_FLAT_STATUS_SOLD,
+}
+
+var userTypes = map[string]pb.UserType{
+	"client":    pb.UserType_USER_TYPE_CLIENT,
+	"moderator": pb.UserType_USER_TYPE_MODERATOR,
+}
+
+func flatStatusToPB(s string) pb.FlatStatus {
+	return flatStatuses[s]
+}
+
+func flatStatusFromPB(s pb.FlatStatus) string {
+	for name, value := range flatStatuses {
+		if value == s {
+			return name
+		}
+	}
+	return ""
+}
+
+func userTypeToPB(t string) pb.UserType {
+	return userTypes[t]
+}
+
+func userTypeFromPB(t pb.UserType) string {
+	for name, value := range userTypes {
+		if value == t {
+			return name
+		}
+	}
+	return ""
+}
+
+func intFromPB(n *int32) *int {
+	if n == nil {
+		return nil
+	}
+	v := int(*n)
+	return &v
+}
+
+func int64FromPB(n *int64) *int {
+	if n == nil {
+		return nil
+	}
+	v := int(*n)
+	return &v
+}
+
+func int64ToPB(n *int) *int64 {
+	if n == nil {
+		return nil
+	}
+	v := int64(*n)
+	return &v
+}
+
+func int32ToPB(n *int) *int32 {
+	if n == nil {
+		return nil
+	}
+	v := int32(*n)
+	return &v
+}
+
+func houseToPB(h models.House) *pb.House {
+	return &pb.House{
+		Id:        int64(h.Id),
+		Address:   h.Address,
+		Year:      int32(h.Year),
+		Developer: h.Developer,
+		CreatedAt: timestamppb.New(h.Created_at),
+		UpdatedAt: timestamppb.New(h.Updated_at),
+		Floors:    int32ToPB(h.Floors),
+		Material:  h.Material,
+		Elevator:  h.Elevator,
+		Parking:   h.Parking,
+	}
+}
+
+func flatToPB(f models.Flat) *pb.Flat {
+	return &pb.Flat{
+		Id:                int64(f.Id),
+		HouseId:           int64(f.House_id),
+		Price:             int64(f.Price),
+		Rooms:             int32(f.Rooms),
+		Status:            flatStatusToPB(f.Status),
+		Number:            int32ToPB(f.Number),
+		Floor:             int32ToPB(f.Floor),
+		TotalArea:         f.TotalArea,
+		LivingArea:        f.LivingArea,
+		Balcony:           f.Balcony,
+		ListingType:       cmp.Or(f.ListingType, models.ListingSale),
+		RentPeriod:        f.RentPeriod,
+		Deposit:           int64ToPB(f.Deposit),
+		Commission:        int32ToPB(f.Commission),
+		UtilitiesIncluded: f.UtilitiesIncluded,
+	}
+}
@@ -0,0 +1,135 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/delapaska/avito-rent/grpcapi/pb"
+	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/notification"
+	"github.com/delapaska/avito-rent/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type FlatServer struct {
+	pb.UnimplementedFlatServiceServer
+	store    models.FlatStore
+	notifier *notification.Notifier
+}
+
+func NewFlatServer(store models.FlatStore, notifier *notification.Notifier) *FlatServer {
+	return &FlatServer{store: store, notifier: notifier}
+}
+
+func (s *FlatServer) CreateFlat(ctx context.Context, req *pb.CreateFlatRequest) (*pb.CreateFlatResponse, error) {
+	payload := models.FlatPayload{
+		House_id:          int(req.GetHouseId()),
+		Price:             int(req.GetPrice()),
+		Rooms:             int(req.GetRooms()),
+		Number:            intFromPB(req.Number),
+		Floor:             intFromPB(req.Floor),
+		TotalArea:         req.TotalArea,
+		LivingArea:        req.LivingArea,
+		Balcony:           req.Balcony,
+		ListingType:       req.GetListingType(),
+		RentPeriod:        req.GetRentPeriod(),
+		Deposit:           int64FromPB(req.Deposit),
+		Commission:        intFromPB(req.Commission),
+		UtilitiesIncluded: req.UtilitiesIncluded,
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := payload.Check(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ownerID, _ := userFromContext(ctx)
+	flat, err := s.store.CreateFlat(models.Flat{
+		House_id:          payload.House_id,
+		Price:             payload.Price,
+		Rooms:             payload.Rooms,
+		Number:            payload.Number,
+		Floor:             payload.Floor,
+		TotalArea:         payload.TotalArea,
+		LivingArea:        payload.LivingArea,
+		Balcony:           payload.Balcony,
+		ListingType:       payload.ListingType,
+		RentPeriod:        payload.RentPeriod,
+		Deposit:           payload.Deposit,
+		Commission:        payload.Commission,
+		UtilitiesIncluded: payload.UtilitiesIncluded,
+		OwnerID:           &ownerID,
+	})
+	switch {
+	case errors.Is(err, models.ErrHouseNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, models.ErrHouseArchived):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, models.ErrFlatNumberTaken):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, models.ErrFloorAboveHouse):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	middleware.RecordAudit(ctx, models.AuditFlatCreate, "flat", flat.Id, nil, flat)
+	return &pb.CreateFlatResponse{Flat: flatToPB(flat)}, nil
+}
+
+func (s *FlatServer) UpdateFlatStatus(ctx context.Context, req *pb.UpdateFlatStatusRequest) (*pb.UpdateFlatStatusResponse, error) {
+	userID, userType := userFromContext(ctx)
+
+	newStatus := flatStatusFromPB(req.GetStatus())
+	if newStatus == "" {
+		return nil, status.Error(codes.InvalidArgument, "status is required")
+	}
+	closing := newStatus == models.StatusRented || newStatus == models.StatusSold
+	if !closing && userType != "moderator" {
+		return nil, status.Error(codes.PermissionDenied, "only moderators can moderate flats")
+	}
+
+	before, err := s.store.GetFlat(int(req.GetId()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "flat not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	payload := models.UpdateStatusPayload{Id: int(req.GetId()), Status: newStatus}
+	if req.FinalPrice != nil {
+		finalPrice := int(req.GetFinalPrice())
+		payload.FinalPrice = &finalPrice
+	}
+	flat, err := s.store.UpdateFlatStatus(userID, payload)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, status.Error(codes.NotFound, "flat not found")
+	case errors.Is(err, models.ErrNotFlatOwner), errors.Is(err, models.ErrNotAssignedModerator):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, models.ErrArchived):
+		return nil, status.Error(codes.FailedPrecondition, "cannot change the status of an archived flat")
+	case errors.Is(err, models.ErrNotListed):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, models.ErrDealStatus), errors.Is(err, models.ErrInvalidFinalPrice), errors.Is(err, models.ErrInvalidTransition):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	action := models.AuditFlatModerate
+	if closing {
+		action = models.AuditFlatClose
+	}
+	middleware.RecordAudit(ctx, action, "flat", flat.Id, before, flat)
+	if flat.Status == models.StatusApproved {
+		go s.notifier.NotifyFlatApproved(flat)
+	}
+
+	return &pb.UpdateFlatStatusResponse{Flat: flatToPB(flat)}, nil
+}
@@ -0,0 +1,116 @@
+package grpcapi
+
+import (
+	"cmp"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/delapaska/avito-rent/grpcapi/pb"
+	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/notification"
+	"github.com/delapaska/avito-rent/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type HouseServer struct {
+	pb.UnimplementedHouseServiceServer
+	store    models.HouseStore
+	notifier *notification.Notifier
+}
+
+func NewHouseServer(store models.HouseStore, notifier *notification.Notifier) *HouseServer {
+	return &HouseServer{store: store, notifier: notifier}
+}
+
+func (s *HouseServer) CreateHouse(ctx context.Context, req *pb.CreateHouseRequest) (*pb.CreateHouseResponse, error) {
+	payload := models.HousePayload{
+		Address:   req.GetAddress(),
+		Year:      int(req.GetYear()),
+		Developer: req.GetDeveloper(),
+		Floors:    intFromPB(req.Floors),
+		Material:  req.GetMaterial(),
+		Elevator:  req.Elevator,
+		Parking:   req.Parking,
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if strings.TrimSpace(payload.Address) == "" {
+		return nil, status.Error(codes.InvalidArgument, "address cannot be empty")
+	}
+	if payload.Developer != "" && strings.TrimSpace(payload.Developer) == "" {
+		return nil, status.Error(codes.InvalidArgument, "developer cannot be empty")
+	}
+	if payload.Year < 0 {
+		return nil, status.Error(codes.InvalidArgument, "year must be more or equal than 0")
+	}
+
+	h, err := s.store.CreateHouse(models.House{
+		Address:   payload.Address,
+		Year:      payload.Year,
+		Developer: payload.Developer,
+		Floors:    payload.Floors,
+		Material:  payload.Material,
+		Elevator:  payload.Elevator,
+		Parking:   payload.Parking,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	middleware.RecordAudit(ctx, models.AuditHouseCreate, "house", h.Id, nil, h)
+	return &pb.CreateHouseResponse{House: houseToPB(h)}, nil
+}
+
+func (s *HouseServer) GetHouseFlats(ctx context.Context, req *pb.GetHouseFlatsRequest) (*pb.GetHouseFlatsResponse, error) {
+	_, userType := userFromContext(ctx)
+
+	filter := models.FlatFilter{ListingType: req.GetListingType()}
+	if filter.ListingType != "" && filter.ListingType != models.ListingSale && filter.ListingType != models.ListingRent {
+		return nil, status.Error(codes.InvalidArgument, "listing_type must be sale or rent")
+	}
+
+	flats, err := s.store.GetHouseFlats(strconv.FormatInt(req.GetHouseId(), 10), userType, filter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &pb.GetHouseFlatsResponse{Flats: make([]*pb.Flat, 0, len(flats))}
+	for _, f := range flats {
+		resp.Flats = append(resp.Flats, flatToPB(f))
+	}
+	return resp, nil
+}
+
+func (s *HouseServer) Subscribe(ctx context.Context, req *pb.SubscribeRequest) (*pb.SubscribeResponse, error) {
+	payload := models.SubscribePayload{Email: req.GetEmail(), Locale: req.GetLocale(), Delivery: req.GetDelivery()}
+	if err := utils.Validate.Struct(payload); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	subscription := models.Subscription{
+		HouseID:  strconv.FormatInt(req.GetHouseId(), 10),
+		Email:    payload.Email,
+		Locale:   cmp.Or(payload.Locale, models.LocaleRU),
+		Delivery: cmp.Or(payload.Delivery, models.DeliveryInstant),
+	}
+	if err := s.store.AddSubscription(subscription); err != nil {
+		return nil, status.Error(codes.Internal, "failed to save subscription")
+	}
+	middleware.RecordAudit(ctx, models.AuditHouseSubscribe, "house", req.GetHouseId(), nil, subscription)
+
+	go s.notifier.NotifySubscribed(subscription)
+
+	return &pb.SubscribeResponse{Subscription: &pb.Subscription{
+		HouseId:   req.GetHouseId(),
+		Email:     payload.Email,
+		Locale:    subscription.Locale,
+		Delivery:  subscription.Delivery,
+		CreatedAt: timestamppb.New(time.Now().UTC()),
+	}}, nil
+}
@@ -0,0 +1,111 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/delapaska/avito-rent/grpcapi/pb"
+	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type contextKey int
+
+const (
+	userIDKey contextKey = iota
+	userTypeKey
+)
+
+// methodRoles lists the roles allowed to call each protected method, the
+// same way RegisterRoutes groups REST endpoints behind AuthMiddleware.
+// Methods that are not listed do not require a token.
+var methodRoles = map[string][]string{
+	pb.HouseService_CreateHouse_FullMethodName:     {"moderator"},
+	pb.HouseService_GetHouseFlats_FullMethodName:   {"moderator", "client"},
+	pb.HouseService_Subscribe_FullMethodName:       {"moderator", "client"},
+	pb.FlatService_CreateFlat_FullMethodName:       {"moderator", "client"},
+	pb.FlatService_UpdateFlatStatus_FullMethodName: {"moderator", "client"},
+}
+
+func AuthInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		allowedRoles, ok := methodRoles[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authorize(ctx, allowedRoles)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuditInterceptor saves the changes methods record with
+// middleware.RecordAudit, the way middleware.Audit does for REST endpoints.
+// It has to run after AuthInterceptor to know the caller.
+func AuditInterceptor(store models.AuditStore) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, save := middleware.StartAudit(ctx, store)
+		resp, err := handler(ctx, req)
+
+		request := models.AuditEntry{}
+		if userID, userType := userFromContext(ctx); userType != "" {
+			request.ActorID = &userID
+			request.ActorRole = userType
+		}
+		if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("x-request-id")) > 0 {
+			request.RequestID = md.Get("x-request-id")[0]
+		}
+		if p, ok := peer.FromContext(ctx); ok {
+			request.ClientIP = p.Addr.String()
+			if host, _, err := net.SplitHostPort(request.ClientIP); err == nil {
+				request.ClientIP = host
+			}
+		}
+		save(request)
+
+		return resp, err
+	}
+}
+
+func authorize(ctx context.Context, allowedRoles []string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 || values[0] == "" {
+		return nil, status.Error(codes.Unauthenticated, "authorization metadata is required")
+	}
+
+	claims, err := middleware.ParseJWT(strings.TrimPrefix(values[0], "Bearer "))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	for _, role := range allowedRoles {
+		if claims.UserType == role {
+			userID, err := uuid.Parse(claims.UserID)
+			if err != nil {
+				return nil, status.Error(codes.Unauthenticated, "invalid user ID in token")
+			}
+			ctx = context.WithValue(ctx, userIDKey, userID)
+			ctx = context.WithValue(ctx, userTypeKey, claims.UserType)
+			return ctx, nil
+		}
+	}
+
+	return nil, status.Error(codes.PermissionDenied, "you have not enough rights to use this method")
+}
+
+func userFromContext(ctx context.Context) (uuid.UUID, string) {
+	userID, _ := ctx.Value(userIDKey).(uuid.UUID)
+	userType, _ := ctx.Value(userTypeKey).(string)
+	return userID, userType
+}
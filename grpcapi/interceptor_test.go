@@ -0,0 +1,109 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/delapaska/avito-rent/grpcapi/pb"
+	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthInterceptor(t *testing.T) {
+	interceptor := AuthInterceptor()
+
+	call := func(ctx context.Context, method string) (context.Context, error) {
+		var handlerCtx context.Context
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+			handlerCtx = ctx
+			return nil, nil
+		})
+		return handlerCtx, err
+	}
+
+	withToken := func(t *testing.T, userID uuid.UUID, userType string) context.Context {
+		token, err := middleware.GenerateJWT(userID, userType)
+		if err != nil {
+			t.Fatalf("failed to generate token: %v", err)
+		}
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+
+	t.Run("should allow public methods without token", func(t *testing.T) {
+		_, err := call(context.Background(), pb.AuthService_DummyLogin_FullMethodName)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject protected methods without token", func(t *testing.T) {
+		_, err := call(context.Background(), pb.HouseService_GetHouseFlats_FullMethodName)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("should reject invalid token", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer invalid"))
+		_, err := call(ctx, pb.HouseService_GetHouseFlats_FullMethodName)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("should reject client on moderator only method", func(t *testing.T) {
+		_, err := call(withToken(t, uuid.New(), "client"), pb.HouseService_CreateHouse_FullMethodName)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("should pass user to handler", func(t *testing.T) {
+		userID := uuid.New()
+		ctx, err := call(withToken(t, userID, "moderator"), pb.FlatService_UpdateFlatStatus_FullMethodName)
+		assert.NoError(t, err)
+
+		gotID, gotType := userFromContext(ctx)
+		assert.Equal(t, userID, gotID)
+		assert.Equal(t, "moderator", gotType)
+	})
+}
+
+type auditStore struct {
+	models.AuditStore
+	entries []models.AuditEntry
+}
+
+func (s *auditStore) AddEntries(entries []models.AuditEntry) error {
+	s.entries = append(s.entries, entries...)
+	return nil
+}
+
+func TestAuditInterceptor(t *testing.T) {
+	store := &auditStore{}
+	interceptor := AuditInterceptor(store)
+
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), userIDKey, userID)
+	ctx = context.WithValue(ctx, userTypeKey, "moderator")
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-request-id", "req-1"))
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 50051}})
+
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: pb.HouseService_CreateHouse_FullMethodName}, func(ctx context.Context, req any) (any, error) {
+		middleware.RecordAudit(ctx, models.AuditHouseCreate, "house", 7, nil, models.House{Id: 7, Address: "Лесная улица, 7"})
+		return nil, nil
+	})
+
+	assert.NoError(t, err)
+	if assert.Len(t, store.entries, 1) {
+		entry := store.entries[0]
+		assert.Equal(t, &userID, entry.ActorID)
+		assert.Equal(t, "moderator", entry.ActorRole)
+		assert.Equal(t, models.AuditHouseCreate, entry.Action)
+		assert.Equal(t, "7", entry.EntityID)
+		assert.Nil(t, entry.Before)
+		assert.Contains(t, string(entry.After), `"address":"Лесная улица, 7"`)
+		assert.Equal(t, "req-1", entry.RequestID)
+		assert.Equal(t, "203.0.113.7", entry.ClientIP)
+	}
+}
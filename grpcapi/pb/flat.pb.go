@@ -0,0 +1,521 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: avitorent/v1/flat.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateFlatRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HouseId int64 `protobuf:"varint,1,opt,name=house_id,json=houseId,proto3" json:"house_id,omitempty"`
+	Price   int64 `protobuf:"varint,2,opt,name=price,proto3" json:"price,omitempty"`
+	Rooms   int32 `protobuf:"varint,3,opt,name=rooms,proto3" json:"rooms,omitempty"`
+	// Unique within the house.
+	Number *int32 `protobuf:"varint,4,opt,name=number,proto3,oneof" json:"number,omitempty"`
+	// Not higher than the floors of the house.
+	Floor     *int32   `protobuf:"varint,5,opt,name=floor,proto3,oneof" json:"floor,omitempty"`
+	TotalArea *float64 `protobuf:"fixed64,6,opt,name=total_area,json=totalArea,proto3,oneof" json:"total_area,omitempty"`
+	// Not larger than the total area.
+	LivingArea *float64 `protobuf:"fixed64,7,opt,name=living_area,json=livingArea,proto3,oneof" json:"living_area,omitempty"`
+	Balcony    *bool    `protobuf:"varint,8,opt,name=balcony,proto3,oneof" json:"balcony,omitempty"`
+	// "sale" (default) or "rent".
+	ListingType string `protobuf:"bytes,9,opt,name=listing_type,json=listingType,proto3" json:"listing_type,omitempty"`
+	// "monthly" or "daily", required for rentals. Rental terms are not allowed
+	// for sales.
+	RentPeriod        string `protobuf:"bytes,10,opt,name=rent_period,json=rentPeriod,proto3" json:"rent_period,omitempty"`
+	Deposit           *int64 `protobuf:"varint,11,opt,name=deposit,proto3,oneof" json:"deposit,omitempty"`
+	Commission        *int32 `protobuf:"varint,12,opt,name=commission,proto3,oneof" json:"commission,omitempty"`
+	UtilitiesIncluded *bool  `protobuf:"varint,13,opt,name=utilities_included,json=utilitiesIncluded,proto3,oneof" json:"utilities_included,omitempty"`
+}
+
+func (x *CreateFlatRequest) Reset() {
+	*x = CreateFlatRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_avitorent_v1_flat_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateFlatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFlatRequest) ProtoMessage() {}
+
+func (x *CreateFlatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_avitorent_v1_flat_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFlatRequest.ProtoReflect.Descriptor instead.
+func (*CreateFlatRequest) Descriptor() ([]byte, []int) {
+	return file_avitorent_v1_flat_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateFlatRequest) GetHouseId() int64 {
+	if x != nil {
+		return x.HouseId
+	}
+	return 0
+}
+
+func (x *CreateFlatRequest) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *CreateFlatRequest) GetRooms() int32 {
+	if x != nil {
+		return x.Rooms
+	}
+	return 0
+}
+
+func (x *CreateFlatRequest) GetNumber() int32 {
+	if x != nil && x.Number != nil {
+		return *x.Number
+	}
+	return 0
+}
+
+func (x *CreateFlatRequest) GetFloor() int32 {
+	if x != nil && x.Floor != nil {
+		return *x.Floor
+	}
+	return 0
+}
+
+func (x *CreateFlatRequest) GetTotalArea() float64 {
+	if x != nil && x.TotalArea != nil {
+		return *x.TotalArea
+	}
+	return 0
+}
+
+func (x *CreateFlatRequest) GetLivingArea() float64 {
+	if x != nil && x.LivingArea != nil {
+		return *x.LivingArea
+	}
+	return 0
+}
+
+func (x *CreateFlatRequest) GetBalcony() bool {
+	if x != nil && x.Balcony != nil {
+		return *x.Balcony
+	}
+	return false
+}
+
+func (x *CreateFlatRequest) GetListingType() string {
+	if x != nil {
+		return x.ListingType
+	}
+	return ""
+}
+
+func (x *CreateFlatRequest) GetRentPeriod() string {
+	if x != nil {
+		return x.RentPeriod
+	}
+	return ""
+}
+
+func (x *CreateFlatRequest) GetDeposit() int64 {
+	if x != nil && x.Deposit != nil {
+		return *x.Deposit
+	}
+	return 0
+}
+
+func (x *CreateFlatRequest) GetCommission() int32 {
+	if x != nil && x.Commission != nil {
+		return *x.Commission
+	}
+	return 0
+}
+
+func (x *CreateFlatRequest) GetUtilitiesIncluded() bool {
+	if x != nil && x.UtilitiesIncluded != nil {
+		return *x.UtilitiesIncluded
+	}
+	return false
+}
+
+type CreateFlatResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Flat *Flat `protobuf:"bytes,1,opt,name=flat,proto3" json:"flat,omitempty"`
+}
+
+func (x *CreateFlatResponse) Reset() {
+	*x = CreateFlatResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_avitorent_v1_flat_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateFlatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFlatResponse) ProtoMessage() {}
+
+func (x *CreateFlatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_avitorent_v1_flat_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFlatResponse.ProtoReflect.Descriptor instead.
+func (*CreateFlatResponse) Descriptor() ([]byte, []int) {
+	return file_avitorent_v1_flat_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateFlatResponse) GetFlat() *Flat {
+	if x != nil {
+		return x.Flat
+	}
+	return nil
+}
+
+type UpdateFlatStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id     int64      `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status FlatStatus `protobuf:"varint,2,opt,name=status,proto3,enum=avitorent.v1.FlatStatus" json:"status,omitempty"`
+	// Only for FLAT_STATUS_RENTED and FLAT_STATUS_SOLD, which the owner of the
+	// flat sets; the listed price when unset.
+	FinalPrice *int64 `protobuf:"varint,3,opt,name=final_price,json=finalPrice,proto3,oneof" json:"final_price,omitempty"`
+}
+
+func (x *UpdateFlatStatusRequest) Reset() {
+	*x = UpdateFlatStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_avitorent_v1_flat_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateFlatStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFlatStatusRequest) ProtoMessage() {}
+
+func (x *UpdateFlatStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_avitorent_v1_flat_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFlatStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFlatStatusRequest) Descriptor() ([]byte, []int) {
+	return file_avitorent_v1_flat_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UpdateFlatStatusRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateFlatStatusRequest) GetStatus() FlatStatus {
+	if x != nil {
+		return x.Status
+	}
+	return FlatStatus_FLAT_STATUS_UNSPECIFIED
+}
+
+func (x *UpdateFlatStatusRequest) GetFinalPrice() int64 {
+	if x != nil && x.FinalPrice != nil {
+		return *x.FinalPrice
+	}
+	return 0
+}
+
+type UpdateFlatStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Flat *Flat `protobuf:"bytes,1,opt,name=flat,proto3" json:"flat,omitempty"`
+}
+
+func (x *UpdateFlatStatusResponse) Reset() {
+	*x = UpdateFlatStatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_avitorent_v1_flat_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateFlatStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFlatStatusResponse) ProtoMessage() {}
+
+func (x *UpdateFlatStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_avitorent_v1_flat_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFlatStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateFlatStatusResponse) Descriptor() ([]byte, []int) {
+	return file_avitorent_v1_flat_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpdateFlatStatusResponse) GetFlat() *Flat {
+	if x != nil {
+		return x.Flat
+	}
+	return nil
+}
+
+var File_avitorent_v1_flat_proto protoreflect.FileDescriptor
+
+var file_avitorent_v1_flat_proto_rawDesc = []byte{
+	0x0a, 0x17, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2f, 0x76, 0x31, 0x2f, 0x66,
+	0x6c, 0x61, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x61, 0x76, 0x69, 0x74, 0x6f,
+	0x72, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x1a, 0x19, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x72, 0x65,
+	0x6e, 0x74, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0xa9, 0x04, 0x0a, 0x11, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x68, 0x6f, 0x75, 0x73,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x68, 0x6f, 0x75, 0x73,
+	0x65, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x6f, 0x6f,
+	0x6d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x12,
+	0x1b, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x00, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05,
+	0x66, 0x6c, 0x6f, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x05, 0x66,
+	0x6c, 0x6f, 0x6f, 0x72, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x5f, 0x61, 0x72, 0x65, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x48, 0x02, 0x52, 0x09, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x41, 0x72, 0x65, 0x61, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x6c,
+	0x69, 0x76, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x72, 0x65, 0x61, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01,
+	0x48, 0x03, 0x52, 0x0a, 0x6c, 0x69, 0x76, 0x69, 0x6e, 0x67, 0x41, 0x72, 0x65, 0x61, 0x88, 0x01,
+	0x01, 0x12, 0x1d, 0x0a, 0x07, 0x62, 0x61, 0x6c, 0x63, 0x6f, 0x6e, 0x79, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x08, 0x48, 0x04, 0x52, 0x07, 0x62, 0x61, 0x6c, 0x63, 0x6f, 0x6e, 0x79, 0x88, 0x01, 0x01,
+	0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x70, 0x65, 0x72, 0x69,
+	0x6f, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x6e, 0x74, 0x50, 0x65,
+	0x72, 0x69, 0x6f, 0x64, 0x12, 0x1d, 0x0a, 0x07, 0x64, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x18,
+	0x0b, 0x20, 0x01, 0x28, 0x03, 0x48, 0x05, 0x52, 0x07, 0x64, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x05, 0x48, 0x06, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x6d, 0x69,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x32, 0x0a, 0x12, 0x75, 0x74, 0x69, 0x6c,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x18, 0x0d,
+	0x20, 0x01, 0x28, 0x08, 0x48, 0x07, 0x52, 0x11, 0x75, 0x74, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x88, 0x01, 0x01, 0x42, 0x09, 0x0a, 0x07,
+	0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x66, 0x6c, 0x6f, 0x6f,
+	0x72, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x61, 0x72, 0x65, 0x61,
+	0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6c, 0x69, 0x76, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x72, 0x65, 0x61,
+	0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x62, 0x61, 0x6c, 0x63, 0x6f, 0x6e, 0x79, 0x42, 0x0a, 0x0a, 0x08,
+	0x5f, 0x64, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x63, 0x6f, 0x6d,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x75, 0x74, 0x69, 0x6c,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x22, 0x3c,
+	0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x04, 0x66, 0x6c, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x46, 0x6c, 0x61, 0x74, 0x52, 0x04, 0x66, 0x6c, 0x61, 0x74, 0x22, 0x91, 0x01, 0x0a,
+	0x17, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x30, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x61, 0x76, 0x69, 0x74, 0x6f,
+	0x72, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x6c, 0x61, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x24, 0x0a, 0x0b, 0x66, 0x69,
+	0x6e, 0x61, 0x6c, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x48,
+	0x00, 0x52, 0x0a, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x88, 0x01, 0x01,
+	0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x70, 0x72, 0x69, 0x63, 0x65,
+	0x22, 0x42, 0x0a, 0x18, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x04,
+	0x66, 0x6c, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61, 0x76, 0x69,
+	0x74, 0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x6c, 0x61, 0x74, 0x52, 0x04,
+	0x66, 0x6c, 0x61, 0x74, 0x32, 0xc1, 0x01, 0x0a, 0x0b, 0x46, 0x6c, 0x61, 0x74, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x4f, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x6c,
+	0x61, 0x74, 0x12, 0x1f, 0x2e, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x61, 0x0a, 0x10, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46,
+	0x6c, 0x61, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x25, 0x2e, 0x61, 0x76, 0x69, 0x74,
+	0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46,
+	0x6c, 0x61, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x26, 0x2e, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x6c, 0x61, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2f, 0x5a, 0x2d, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x65, 0x6c, 0x61, 0x70, 0x61, 0x73, 0x6b, 0x61,
+	0x2f, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x2d, 0x72, 0x65, 0x6e, 0x74, 0x2f, 0x67, 0x72, 0x70, 0x63,
+	0x61, 0x70, 0x69, 0x2f, 0x70, 0x62, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_avitorent_v1_flat_proto_rawDescOnce sync.Once
+	file_avitorent_v1_flat_proto_rawDescData = file_avitorent_v1_flat_proto_rawDesc
+)
+
+func file_avitorent_v1_flat_proto_rawDescGZIP() []byte {
+	file_avitorent_v1_flat_proto_rawDescOnce.Do(func() {
+		file_avitorent_v1_flat_proto_rawDescData = protoimpl.X.CompressGZIP(file_avitorent_v1_flat_proto_rawDescData)
+	})
+	return file_avitorent_v1_flat_proto_rawDescData
+}
+
+var file_avitorent_v1_flat_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_avitorent_v1_flat_proto_goTypes = []any{
+	(*CreateFlatRequest)(nil),        // 0: avitorent.v1.CreateFlatRequest
+	(*CreateFlatResponse)(nil),       // 1: avitorent.v1.CreateFlatResponse
+	(*UpdateFlatStatusRequest)(nil),  // 2: avitorent.v1.UpdateFlatStatusRequest
+	(*UpdateFlatStatusResponse)(nil), // 3: avitorent.v1.UpdateFlatStatusResponse
+	(*Flat)(nil),                     // 4: avitorent.v1.Flat
+	(FlatStatus)(0),                  // 5: avitorent.v1.FlatStatus
+}
+var file_avitorent_v1_flat_proto_depIdxs = []int32{
+	4, // 0: avitorent.v1.CreateFlatResponse.flat:type_name -> avitorent.v1.Flat
+	5, // 1: avitorent.v1.UpdateFlatStatusRequest.status:type_name -> avitorent.v1.FlatStatus
+	4, // 2: avitorent.v1.UpdateFlatStatusResponse.flat:type_name -> avitorent.v1.Flat
+	0, // 3: avitorent.v1.FlatService.CreateFlat:input_type -> avitorent.v1.CreateFlatRequest
+	2, // 4: avitorent.v1.FlatService.UpdateFlatStatus:input_type -> avitorent.v1.UpdateFlatStatusRequest
+	1, // 5: avitorent.v1.FlatService.CreateFlat:output_type -> avitorent.v1.CreateFlatResponse
+	3, // 6: avitorent.v1.FlatService.UpdateFlatStatus:output_type -> avitorent.v1.UpdateFlatStatusResponse
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_avitorent_v1_flat_proto_init() }
+func file_avitorent_v1_flat_proto_init() {
+	if File_avitorent_v1_flat_proto != nil {
+		return
+	}
+	file_avitorent_v1_models_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_avitorent_v1_flat_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateFlatRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_avitorent_v1_flat_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateFlatResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_avitorent_v1_flat_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateFlatStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_avitorent_v1_flat_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateFlatStatusResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_avitorent_v1_flat_proto_msgTypes[0].OneofWrappers = []any{}
+	file_avitorent_v1_flat_proto_msgTypes[2].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_avitorent_v1_flat_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_avitorent_v1_flat_proto_goTypes,
+		DependencyIndexes: file_avitorent_v1_flat_proto_depIdxs,
+		MessageInfos:      file_avitorent_v1_flat_proto_msgTypes,
+	}.Build()
+	File_avitorent_v1_flat_proto = out.File
+	file_avitorent_v1_flat_proto_rawDesc = nil
+	file_avitorent_v1_flat_proto_goTypes = nil
+	file_avitorent_v1_flat_proto_depIdxs = nil
+}
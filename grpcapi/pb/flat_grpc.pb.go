@@ -0,0 +1,167 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: avitorent/v1/flat.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FlatService_CreateFlat_FullMethodName       = "/avitorent.v1.FlatService/CreateFlat"
+	FlatService_UpdateFlatStatus_FullMethodName = "/avitorent.v1.FlatService/UpdateFlatStatus"
+)
+
+// FlatServiceClient is the client API for FlatService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FlatServiceClient interface {
+	// CreateFlat fails with ALREADY_EXISTS when the house already has a flat
+	// with the number and with INVALID_ARGUMENT when the floor is above the
+	// top floor of the house.
+	CreateFlat(ctx context.Context, in *CreateFlatRequest, opts ...grpc.CallOption) (*CreateFlatResponse, error)
+	// UpdateFlatStatus requires a moderator token.
+	UpdateFlatStatus(ctx context.Context, in *UpdateFlatStatusRequest, opts ...grpc.CallOption) (*UpdateFlatStatusResponse, error)
+}
+
+type flatServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFlatServiceClient(cc grpc.ClientConnInterface) FlatServiceClient {
+	return &flatServiceClient{cc}
+}
+
+func (c *flatServiceClient) CreateFlat(ctx context.Context, in *CreateFlatRequest, opts ...grpc.CallOption) (*CreateFlatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateFlatResponse)
+	err := c.cc.Invoke(ctx, FlatService_CreateFlat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flatServiceClient) UpdateFlatStatus(ctx context.Context, in *UpdateFlatStatusRequest, opts ...grpc.CallOption) (*UpdateFlatStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateFlatStatusResponse)
+	err := c.cc.Invoke(ctx, FlatService_UpdateFlatStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FlatServiceServer is the server API for FlatService service.
+// All implementations must embed UnimplementedFlatServiceServer
+// for forward compatibility.
+type FlatServiceServer interface {
+	// CreateFlat fails with ALREADY_EXISTS when the house already has a flat
+	// with the number and with INVALID_ARGUMENT when the floor is above the
+	// top floor of the house.
+	CreateFlat(context.Context, *CreateFlatRequest) (*CreateFlatResponse, error)
+	// UpdateFlatStatus requires a moderator token.
+	UpdateFlatStatus(context.Context, *UpdateFlatStatusRequest) (*UpdateFlatStatusResponse, error)
+	mustEmbedUnimplementedFlatServiceServer()
+}
+
+// UnimplementedFlatServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFlatServiceServer struct{}
+
+func (UnimplementedFlatServiceServer) CreateFlat(context.Context, *CreateFlatRequest) (*CreateFlatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFlat not implemented")
+}
+func (UnimplementedFlatServiceServer) UpdateFlatStatus(context.Context, *UpdateFlatStatusRequest) (*UpdateFlatStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFlatStatus not implemented")
+}
+func (UnimplementedFlatServiceServer) mustEmbedUnimplementedFlatServiceServer() {}
+func (UnimplementedFlatServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeFlatServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FlatServiceServer will
+// result in compilation errors.
+type UnsafeFlatServiceServer interface {
+	mustEmbedUnimplementedFlatServiceServer()
+}
+
+func RegisterFlatServiceServer(s grpc.ServiceRegistrar, srv FlatServiceServer) {
+	// If the following call pancis, it indicates UnimplementedFlatServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FlatService_ServiceDesc, srv)
+}
+
+func _FlatService_CreateFlat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFlatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlatServiceServer).CreateFlat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlatService_CreateFlat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlatServiceServer).CreateFlat(ctx, req.(*CreateFlatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlatService_UpdateFlatStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFlatStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlatServiceServer).UpdateFlatStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlatService_UpdateFlatStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlatServiceServer).UpdateFlatStatus(ctx, req.(*UpdateFlatStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FlatService_ServiceDesc is the grpc.ServiceDesc for FlatService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FlatService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "avitorent.v1.FlatService",
+	HandlerType: (*FlatServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateFlat",
+			Handler:    _FlatService_CreateFlat_Handler,
+		},
+		{
+			MethodName: "UpdateFlatStatus",
+			Handler:    _FlatService_UpdateFlatStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "avitorent/v1/flat.proto",
+}
@@ -0,0 +1,602 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: avitorent/v1/house.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateHouseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address   string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Year      int32  `protobuf:"varint,2,opt,name=year,proto3" json:"year,omitempty"`
+	Developer string `protobuf:"bytes,3,opt,name=developer,proto3" json:"developer,omitempty"`
+	Floors    *int32 `protobuf:"varint,4,opt,name=floors,proto3,oneof" json:"floors,omitempty"`
+	// One of "brick", "panel", "monolith", "monolith_brick", "block", "wood".
+	Material string `protobuf:"bytes,5,opt,name=material,proto3" json:"material,omitempty"`
+	Elevator *bool  `protobuf:"varint,6,opt,name=elevator,proto3,oneof" json:"elevator,omitempty"`
+	Parking  *bool  `protobuf:"varint,7,opt,name=parking,proto3,oneof" json:"parking,omitempty"`
+}
+
+func (x *CreateHouseRequest) Reset() {
+	*x = CreateHouseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_avitorent_v1_house_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateHouseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateHouseRequest) ProtoMessage() {}
+
+func (x *CreateHouseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_avitorent_v1_house_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateHouseRequest.ProtoReflect.Descriptor instead.
+func (*CreateHouseRequest) Descriptor() ([]byte, []int) {
+	return file_avitorent_v1_house_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateHouseRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *CreateHouseRequest) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *CreateHouseRequest) GetDeveloper() string {
+	if x != nil {
+		return x.Developer
+	}
+	return ""
+}
+
+func (x *CreateHouseRequest) GetFloors() int32 {
+	if x != nil && x.Floors != nil {
+		return *x.Floors
+	}
+	return 0
+}
+
+func (x *CreateHouseRequest) GetMaterial() string {
+	if x != nil {
+		return x.Material
+	}
+	return ""
+}
+
+func (x *CreateHouseRequest) GetElevator() bool {
+	if x != nil && x.Elevator != nil {
+		return *x.Elevator
+	}
+	return false
+}
+
+func (x *CreateHouseRequest) GetParking() bool {
+	if x != nil && x.Parking != nil {
+		return *x.Parking
+	}
+	return false
+}
+
+type CreateHouseResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	House *House `protobuf:"bytes,1,opt,name=house,proto3" json:"house,omitempty"`
+}
+
+func (x *CreateHouseResponse) Reset() {
+	*x = CreateHouseResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_avitorent_v1_house_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateHouseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateHouseResponse) ProtoMessage() {}
+
+func (x *CreateHouseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_avitorent_v1_house_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateHouseResponse.ProtoReflect.Descriptor instead.
+func (*CreateHouseResponse) Descriptor() ([]byte, []int) {
+	return file_avitorent_v1_house_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateHouseResponse) GetHouse() *House {
+	if x != nil {
+		return x.House
+	}
+	return nil
+}
+
+type GetHouseFlatsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HouseId int64 `protobuf:"varint,1,opt,name=house_id,json=houseId,proto3" json:"house_id,omitempty"`
+	// Only flats for "sale" or for "rent"; all flats when empty.
+	ListingType string `protobuf:"bytes,2,opt,name=listing_type,json=listingType,proto3" json:"listing_type,omitempty"`
+}
+
+func (x *GetHouseFlatsRequest) Reset() {
+	*x = GetHouseFlatsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_avitorent_v1_house_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetHouseFlatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHouseFlatsRequest) ProtoMessage() {}
+
+func (x *GetHouseFlatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_avitorent_v1_house_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHouseFlatsRequest.ProtoReflect.Descriptor instead.
+func (*GetHouseFlatsRequest) Descriptor() ([]byte, []int) {
+	return file_avitorent_v1_house_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetHouseFlatsRequest) GetHouseId() int64 {
+	if x != nil {
+		return x.HouseId
+	}
+	return 0
+}
+
+func (x *GetHouseFlatsRequest) GetListingType() string {
+	if x != nil {
+		return x.ListingType
+	}
+	return ""
+}
+
+type GetHouseFlatsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Flats []*Flat `protobuf:"bytes,1,rep,name=flats,proto3" json:"flats,omitempty"`
+}
+
+func (x *GetHouseFlatsResponse) Reset() {
+	*x = GetHouseFlatsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_avitorent_v1_house_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetHouseFlatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHouseFlatsResponse) ProtoMessage() {}
+
+func (x *GetHouseFlatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_avitorent_v1_house_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHouseFlatsResponse.ProtoReflect.Descriptor instead.
+func (*GetHouseFlatsResponse) Descriptor() ([]byte, []int) {
+	return file_avitorent_v1_house_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetHouseFlatsResponse) GetFlats() []*Flat {
+	if x != nil {
+		return x.Flats
+	}
+	return nil
+}
+
+type SubscribeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HouseId int64  `protobuf:"varint,1,opt,name=house_id,json=houseId,proto3" json:"house_id,omitempty"`
+	Email   string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	// Language of the notifications: "ru" (default) or "en".
+	Locale string `protobuf:"bytes,3,opt,name=locale,proto3" json:"locale,omitempty"`
+	// How often to notify: "instant" (default) or "daily" for one digest a day.
+	Delivery string `protobuf:"bytes,4,opt,name=delivery,proto3" json:"delivery,omitempty"`
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_avitorent_v1_house_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_avitorent_v1_house_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_avitorent_v1_house_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SubscribeRequest) GetHouseId() int64 {
+	if x != nil {
+		return x.HouseId
+	}
+	return 0
+}
+
+func (x *SubscribeRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *SubscribeRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *SubscribeRequest) GetDelivery() string {
+	if x != nil {
+		return x.Delivery
+	}
+	return ""
+}
+
+type SubscribeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Subscription *Subscription `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+}
+
+func (x *SubscribeResponse) Reset() {
+	*x = SubscribeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_avitorent_v1_house_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeResponse) ProtoMessage() {}
+
+func (x *SubscribeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_avitorent_v1_house_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeResponse.ProtoReflect.Descriptor instead.
+func (*SubscribeResponse) Descriptor() ([]byte, []int) {
+	return file_avitorent_v1_house_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SubscribeResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+var File_avitorent_v1_house_proto protoreflect.FileDescriptor
+
+var file_avitorent_v1_house_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2f, 0x76, 0x31, 0x2f, 0x68,
+	0x6f, 0x75, 0x73, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x61, 0x76, 0x69, 0x74,
+	0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x1a, 0x19, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x72,
+	0x65, 0x6e, 0x74, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0xfd, 0x01, 0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x48, 0x6f,
+	0x75, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x79, 0x65, 0x61, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x04, 0x79, 0x65, 0x61, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x65,
+	0x6c, 0x6f, 0x70, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x65, 0x76,
+	0x65, 0x6c, 0x6f, 0x70, 0x65, 0x72, 0x12, 0x1b, 0x0a, 0x06, 0x66, 0x6c, 0x6f, 0x6f, 0x72, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x06, 0x66, 0x6c, 0x6f, 0x6f, 0x72, 0x73,
+	0x88, 0x01, 0x01, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x61, 0x74, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x61, 0x74, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x12,
+	0x1f, 0x0a, 0x08, 0x65, 0x6c, 0x65, 0x76, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x08, 0x48, 0x01, 0x52, 0x08, 0x65, 0x6c, 0x65, 0x76, 0x61, 0x74, 0x6f, 0x72, 0x88, 0x01, 0x01,
+	0x12, 0x1d, 0x0a, 0x07, 0x70, 0x61, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x08, 0x48, 0x02, 0x52, 0x07, 0x70, 0x61, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x88, 0x01, 0x01, 0x42,
+	0x09, 0x0a, 0x07, 0x5f, 0x66, 0x6c, 0x6f, 0x6f, 0x72, 0x73, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x65,
+	0x6c, 0x65, 0x76, 0x61, 0x74, 0x6f, 0x72, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x70, 0x61, 0x72, 0x6b,
+	0x69, 0x6e, 0x67, 0x22, 0x40, 0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x48, 0x6f, 0x75,
+	0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x68, 0x6f,
+	0x75, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x61, 0x76, 0x69, 0x74,
+	0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x6f, 0x75, 0x73, 0x65, 0x52, 0x05,
+	0x68, 0x6f, 0x75, 0x73, 0x65, 0x22, 0x54, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x75, 0x73,
+	0x65, 0x46, 0x6c, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a,
+	0x08, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x07, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x69, 0x73, 0x74,
+	0x69, 0x6e, 0x67, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x6c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x54, 0x79, 0x70, 0x65, 0x22, 0x41, 0x0a, 0x15, 0x47,
+	0x65, 0x74, 0x48, 0x6f, 0x75, 0x73, 0x65, 0x46, 0x6c, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x66, 0x6c, 0x61, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x46, 0x6c, 0x61, 0x74, 0x52, 0x05, 0x66, 0x6c, 0x61, 0x74, 0x73, 0x22, 0x77,
+	0x0a, 0x10, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x49, 0x64, 0x12, 0x14, 0x0a,
+	0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d,
+	0x61, 0x69, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64,
+	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64,
+	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x22, 0x53, 0x0a, 0x11, 0x53, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0c,
+	0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c,
+	0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x32, 0x8a, 0x02, 0x0a,
+	0x0c, 0x48, 0x6f, 0x75, 0x73, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x52, 0x0a,
+	0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x48, 0x6f, 0x75, 0x73, 0x65, 0x12, 0x20, 0x2e, 0x61,
+	0x76, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x48, 0x6f, 0x75, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21,
+	0x2e, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x48, 0x6f, 0x75, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x58, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x75, 0x73, 0x65, 0x46, 0x6c, 0x61,
+	0x74, 0x73, 0x12, 0x22, 0x2e, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x75, 0x73, 0x65, 0x46, 0x6c, 0x61, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x72, 0x65,
+	0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x48, 0x6f, 0x75, 0x73, 0x65, 0x46, 0x6c,
+	0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4c, 0x0a, 0x09, 0x53,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x1e, 0x2e, 0x61, 0x76, 0x69, 0x74, 0x6f,
+	0x72, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x61, 0x76, 0x69, 0x74, 0x6f,
+	0x72, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2f, 0x5a, 0x2d, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x65, 0x6c, 0x61, 0x70, 0x61, 0x73, 0x6b,
+	0x61, 0x2f, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x2d, 0x72, 0x65, 0x6e, 0x74, 0x2f, 0x67, 0x72, 0x70,
+	0x63, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x62, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_avitorent_v1_house_proto_rawDescOnce sync.Once
+	file_avitorent_v1_house_proto_rawDescData = file_avitorent_v1_house_proto_rawDesc
+)
+
+func file_avitorent_v1_house_proto_rawDescGZIP() []byte {
+	file_avitorent_v1_house_proto_rawDescOnce.Do(func() {
+		file_avitorent_v1_house_proto_rawDescData = protoimpl.X.CompressGZIP(file_avitorent_v1_house_proto_rawDescData)
+	})
+	return file_avitorent_v1_house_proto_rawDescData
+}
+
+var file_avitorent_v1_house_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_avitorent_v1_house_proto_goTypes = []any{
+	(*CreateHouseRequest)(nil),    // 0: avitorent.v1.CreateHouseRequest
+	(*CreateHouseResponse)(nil),   // 1: avitorent.v1.CreateHouseResponse
+	(*GetHouseFlatsRequest)(nil),  // 2: avitorent.v1.GetHouseFlatsRequest
+	(*GetHouseFlatsResponse)(nil), // 3: avitorent.v1.GetHouseFlatsResponse
+	(*SubscribeRequest)(nil),      // 4: avitorent.v1.SubscribeRequest
+	(*SubscribeResponse)(nil),     // 5: avitorent.v1.SubscribeResponse
+	(*House)(nil),                 // 6: avitorent.v1.House
+	(*Flat)(nil),                  // 7: avitorent.v1.Flat
+	(*Subscription)(nil),          // 8: avitorent.v1.Subscription
+}
+var file_avitorent_v1_house_proto_depIdxs = []int32{
+	6, // 0: avitorent.v1.CreateHouseResponse.house:type_name -> avitorent.v1.House
+	7, // 1: avitorent.v1.GetHouseFlatsResponse.flats:type_name -> avitorent.v1.Flat
+	8, // 2: avitorent.v1.SubscribeResponse.subscription:type_name -> avitorent.v1.Subscription
+	0, // 3: avitorent.v1.HouseService.CreateHouse:input_type -> avitorent.v1.CreateHouseRequest
+	2, // 4: avitorent.v1.HouseService.GetHouseFlats:input_type -> avitorent.v1.GetHouseFlatsRequest
+	4, // 5: avitorent.v1.HouseService.Subscribe:input_type -> avitorent.v1.SubscribeRequest
+	1, // 6: avitorent.v1.HouseService.CreateHouse:output_type -> avitorent.v1.CreateHouseResponse
+	3, // 7: avitorent.v1.HouseService.GetHouseFlats:output_type -> avitorent.v1.GetHouseFlatsResponse
+	5, // 8: avitorent.v1.HouseService.Subscribe:output_type -> avitorent.v1.SubscribeResponse
+	6, // [6:9] is the sub-list for method output_type
+	3, // [3:6] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_avitorent_v1_house_proto_init() }
+func file_avitorent_v1_house_proto_init() {
+	if File_avitorent_v1_house_proto != nil {
+		return
+	}
+	file_avitorent_v1_models_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_avitorent_v1_house_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateHouseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_avitorent_v1_house_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateHouseResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_avitorent_v1_house_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*GetHouseFlatsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_avitorent_v1_house_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*GetHouseFlatsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_avitorent_v1_house_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*SubscribeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_avitorent_v1_house_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*SubscribeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_avitorent_v1_house_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_avitorent_v1_house_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_avitorent_v1_house_proto_goTypes,
+		DependencyIndexes: file_avitorent_v1_house_proto_depIdxs,
+		MessageInfos:      file_avitorent_v1_house_proto_msgTypes,
+	}.Build()
+	File_avitorent_v1_house_proto = out.File
+	file_avitorent_v1_house_proto_rawDesc = nil
+	file_avitorent_v1_house_proto_goTypes = nil
+	file_avitorent_v1_house_proto_depIdxs = nil
+}
@@ -0,0 +1,201 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: avitorent/v1/house.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	HouseService_CreateHouse_FullMethodName   = "/avitorent.v1.HouseService/CreateHouse"
+	HouseService_GetHouseFlats_FullMethodName = "/avitorent.v1.HouseService/GetHouseFlats"
+	HouseService_Subscribe_FullMethodName     = "/avitorent.v1.HouseService/Subscribe"
+)
+
+// HouseServiceClient is the client API for HouseService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type HouseServiceClient interface {
+	// CreateHouse requires a moderator token.
+	CreateHouse(ctx context.Context, in *CreateHouseRequest, opts ...grpc.CallOption) (*CreateHouseResponse, error)
+	// GetHouseFlats returns only approved flats to clients.
+	GetHouseFlats(ctx context.Context, in *GetHouseFlatsRequest, opts ...grpc.CallOption) (*GetHouseFlatsResponse, error)
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (*SubscribeResponse, error)
+}
+
+type houseServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHouseServiceClient(cc grpc.ClientConnInterface) HouseServiceClient {
+	return &houseServiceClient{cc}
+}
+
+func (c *houseServiceClient) CreateHouse(ctx context.Context, in *CreateHouseRequest, opts ...grpc.CallOption) (*CreateHouseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateHouseResponse)
+	err := c.cc.Invoke(ctx, HouseService_CreateHouse_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *houseServiceClient) GetHouseFlats(ctx context.Context, in *GetHouseFlatsRequest, opts ...grpc.CallOption) (*GetHouseFlatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetHouseFlatsResponse)
+	err := c.cc.Invoke(ctx, HouseService_GetHouseFlats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *houseServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (*SubscribeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubscribeResponse)
+	err := c.cc.Invoke(ctx, HouseService_Subscribe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HouseServiceServer is the server API for HouseService service.
+// All implementations must embed UnimplementedHouseServiceServer
+// for forward compatibility.
+type HouseServiceServer interface {
+	// CreateHouse requires a moderator token.
+	CreateHouse(context.Context, *CreateHouseRequest) (*CreateHouseResponse, error)
+	// GetHouseFlats returns only approved flats to clients.
+	GetHouseFlats(context.Context, *GetHouseFlatsRequest) (*GetHouseFlatsResponse, error)
+	Subscribe(context.Context, *SubscribeRequest) (*SubscribeResponse, error)
+	mustEmbedUnimplementedHouseServiceServer()
+}
+
+// UnimplementedHouseServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedHouseServiceServer struct{}
+
+func (UnimplementedHouseServiceServer) CreateHouse(context.Context, *CreateHouseRequest) (*CreateHouseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateHouse not implemented")
+}
+func (UnimplementedHouseServiceServer) GetHouseFlats(context.Context, *GetHouseFlatsRequest) (*GetHouseFlatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHouseFlats not implemented")
+}
+func (UnimplementedHouseServiceServer) Subscribe(context.Context, *SubscribeRequest) (*SubscribeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedHouseServiceServer) mustEmbedUnimplementedHouseServiceServer() {}
+func (UnimplementedHouseServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeHouseServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HouseServiceServer will
+// result in compilation errors.
+type UnsafeHouseServiceServer interface {
+	mustEmbedUnimplementedHouseServiceServer()
+}
+
+func RegisterHouseServiceServer(s grpc.ServiceRegistrar, srv HouseServiceServer) {
+	// If the following call pancis, it indicates UnimplementedHouseServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&HouseService_ServiceDesc, srv)
+}
+
+func _HouseService_CreateHouse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateHouseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HouseServiceServer).CreateHouse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HouseService_CreateHouse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HouseServiceServer).CreateHouse(ctx, req.(*CreateHouseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HouseService_GetHouseFlats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHouseFlatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HouseServiceServer).GetHouseFlats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HouseService_GetHouseFlats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HouseServiceServer).GetHouseFlats(ctx, req.(*GetHouseFlatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HouseService_Subscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubscribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HouseServiceServer).Subscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HouseService_Subscribe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HouseServiceServer).Subscribe(ctx, req.(*SubscribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// HouseService_ServiceDesc is the grpc.ServiceDesc for HouseService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var HouseService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "avitorent.v1.HouseService",
+	HandlerType: (*HouseServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateHouse",
+			Handler:    _HouseService_CreateHouse_Handler,
+		},
+		{
+			MethodName: "GetHouseFlats",
+			Handler:    _HouseService_GetHouseFlats_Handler,
+		},
+		{
+			MethodName: "Subscribe",
+			Handler:    _HouseService_Subscribe_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "avitorent/v1/house.proto",
+}
@@ -0,0 +1,789 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: avitorent/v1/models.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// FlatStatus mirrors the models.Status* constants.
+type FlatStatus int32
+
+const (
+	FlatStatus_FLAT_STATUS_UNSPECIFIED   FlatStatus = 0
+	FlatStatus_FLAT_STATUS_CREATED       FlatStatus = 1
+	FlatStatus_FLAT_STATUS_ON_MODERATION FlatStatus = 2
+	FlatStatus_FLAT_STATUS_APPROVED      FlatStatus = 3
+	FlatStatus_FLAT_STATUS_DECLINED      FlatStatus = 4
+	FlatStatus_FLAT_STATUS_EXPIRED       FlatStatus = 5
+	FlatStatus_FLAT_STATUS_RENTED        FlatStatus = 6
+	FlatStatus_FLAT_STATUS_SOLD          FlatStatus = 7
+)
+
+// Enum value maps for FlatStatus.
+var (
+	FlatStatus_name = map[int32]string{
+		0: "FLAT_STATUS_UNSPECIFIED",
+		1: "FLAT_STATUS_CREATED",
+		2: "FLAT_STATUS_ON_MODERATION",
+		3: "FLAT_STATUS_APPROVED",
+		4: "FLAT_STATUS_DECLINED",
+		5: "FLAT_STATUS_EXPIRED",
+		6: "FLAT_STATUS_RENTED",
+		7: "FLAT_STATUS_SOLD",
+	}
+	FlatStatus_value = map[string]int32{
+		"FLAT_STATUS_UNSPECIFIED":   0,
+		"FLAT_STATUS_CREATED":       1,
+		"FLAT_STATUS_ON_MODERATION": 2,
+		"FLAT_STATUS_APPROVED":      3,
+		"FLAT_STATUS_DECLINED":      4,
+		"FLAT_STATUS_EXPIRED":       5,
+		"FLAT_STATUS_RENTED":        6,
+		"FLAT_STATUS_SOLD":          7,
+	}
+)
+
+func (x FlatStatus) Enum() *FlatStatus {
+	p := new(FlatStatus)
+	*p = x
+	return p
+}
+
+func (x FlatStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FlatStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_avitorent_v1_models_proto_enumTypes[0].Descriptor()
+}
+
+func (FlatStatus) Type() protoreflect.EnumType {
+	return &file_avitorent_v1_models_proto_enumTypes[0]
+}
+
+func (x FlatStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FlatStatus.Descriptor instead.
+func (FlatStatus) EnumDescriptor() ([]byte, []int) {
+	return file_avitorent_v1_models_proto_rawDescGZIP(), []int{0}
+}
+
+type UserType int32
+
+const (
+	UserType_USER_TYPE_UNSPECIFIED UserType = 0
+	UserType_USER_TYPE_CLIENT      UserType = 1
+	UserType_USER_TYPE_MODERATOR   UserType = 2
+)
+
+// Enum value maps for UserType.
+var (
+	UserType_name = map[int32]string{
+		0: "USER_TYPE_UNSPECIFIED",
+		1: "USER_TYPE_CLIENT",
+		2: "USER_TYPE_MODERATOR",
+	}
+	UserType_value = map[string]int32{
+		"USER_TYPE_UNSPECIFIED": 0,
+		"USER_TYPE_CLIENT":      1,
+		"USER_TYPE_MODERATOR":   2,
+	}
+)
+
+func (x UserType) Enum() *UserType {
+	p := new(UserType)
+	*p = x
+	return p
+}
+
+func (x UserType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UserType) Descriptor() protoreflect.EnumDescriptor {
+	return file_avitorent_v1_models_proto_enumTypes[1].Descriptor()
+}
+
+func (UserType) Type() protoreflect.EnumType {
+	return &file_avitorent_v1_models_proto_enumTypes[1]
+}
+
+func (x UserType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UserType.Descriptor instead.
+func (UserType) EnumDescriptor() ([]byte, []int) {
+	return file_avitorent_v1_models_proto_rawDescGZIP(), []int{1}
+}
+
+type House struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Address   string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Year      int32                  `protobuf:"varint,3,opt,name=year,proto3" json:"year,omitempty"`
+	Developer string                 `protobuf:"bytes,4,opt,name=developer,proto3" json:"developer,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Unset when not given, as are elevator and parking; material is empty.
+	Floors *int32 `protobuf:"varint,7,opt,name=floors,proto3,oneof" json:"floors,omitempty"`
+	// One of "brick", "panel", "monolith", "monolith_brick", "block", "wood".
+	Material string `protobuf:"bytes,8,opt,name=material,proto3" json:"material,omitempty"`
+	Elevator *bool  `protobuf:"varint,9,opt,name=elevator,proto3,oneof" json:"elevator,omitempty"`
+	Parking  *bool  `protobuf:"varint,10,opt,name=parking,proto3,oneof" json:"parking,omitempty"`
+}
+
+func (x *House) Reset() {
+	*x = House{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_avitorent_v1_models_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *House) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*House) ProtoMessage() {}
+
+func (x *House) ProtoReflect() protoreflect.Message {
+	mi := &file_avitorent_v1_models_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use House.ProtoReflect.Descriptor instead.
+func (*House) Descriptor() ([]byte, []int) {
+	return file_avitorent_v1_models_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *House) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *House) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *House) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *House) GetDeveloper() string {
+	if x != nil {
+		return x.Developer
+	}
+	return ""
+}
+
+func (x *House) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *House) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *House) GetFloors() int32 {
+	if x != nil && x.Floors != nil {
+		return *x.Floors
+	}
+	return 0
+}
+
+func (x *House) GetMaterial() string {
+	if x != nil {
+		return x.Material
+	}
+	return ""
+}
+
+func (x *House) GetElevator() bool {
+	if x != nil && x.Elevator != nil {
+		return *x.Elevator
+	}
+	return false
+}
+
+func (x *House) GetParking() bool {
+	if x != nil && x.Parking != nil {
+		return *x.Parking
+	}
+	return false
+}
+
+type Flat struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      int64      `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	HouseId int64      `protobuf:"varint,2,opt,name=house_id,json=houseId,proto3" json:"house_id,omitempty"`
+	Price   int64      `protobuf:"varint,3,opt,name=price,proto3" json:"price,omitempty"`
+	Rooms   int32      `protobuf:"varint,4,opt,name=rooms,proto3" json:"rooms,omitempty"`
+	Status  FlatStatus `protobuf:"varint,5,opt,name=status,proto3,enum=avitorent.v1.FlatStatus" json:"status,omitempty"`
+	// Unset when not given, as are floor, the areas and balcony.
+	Number     *int32   `protobuf:"varint,6,opt,name=number,proto3,oneof" json:"number,omitempty"`
+	Floor      *int32   `protobuf:"varint,7,opt,name=floor,proto3,oneof" json:"floor,omitempty"`
+	TotalArea  *float64 `protobuf:"fixed64,8,opt,name=total_area,json=totalArea,proto3,oneof" json:"total_area,omitempty"`
+	LivingArea *float64 `protobuf:"fixed64,9,opt,name=living_area,json=livingArea,proto3,oneof" json:"living_area,omitempty"`
+	Balcony    *bool    `protobuf:"varint,10,opt,name=balcony,proto3,oneof" json:"balcony,omitempty"`
+	// "sale" or "rent".
+	ListingType string `protobuf:"bytes,11,opt,name=listing_type,json=listingType,proto3" json:"listing_type,omitempty"`
+	// "monthly" or "daily", only set for rentals whose price is the rent per
+	// this period; as are the fields below.
+	RentPeriod string `protobuf:"bytes,12,opt,name=rent_period,json=rentPeriod,proto3" json:"rent_period,omitempty"`
+	Deposit    *int64 `protobuf:"varint,13,opt,name=deposit,proto3,oneof" json:"deposit,omitempty"`
+	// Agent commission in percent of the rent.
+	Commission        *int32 `protobuf:"varint,14,opt,name=commission,proto3,oneof" json:"commission,omitempty"`
+	UtilitiesIncluded *bool  `protobuf:"varint,15,opt,name=utilities_included,json=utilitiesIncluded,proto3,oneof" json:"utilities_included,omitempty"`
+}
+
+func (x *Flat) Reset() {
+	*x = Flat{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_avitorent_v1_models_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Flat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Flat) ProtoMessage() {}
+
+func (x *Flat) ProtoReflect() protoreflect.Message {
+	mi := &file_avitorent_v1_models_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Flat.ProtoReflect.Descriptor instead.
+func (*Flat) Descriptor() ([]byte, []int) {
+	return file_avitorent_v1_models_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Flat) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Flat) GetHouseId() int64 {
+	if x != nil {
+		return x.HouseId
+	}
+	return 0
+}
+
+func (x *Flat) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Flat) GetRooms() int32 {
+	if x != nil {
+		return x.Rooms
+	}
+	return 0
+}
+
+func (x *Flat) GetStatus() FlatStatus {
+	if x != nil {
+		return x.Status
+	}
+	return FlatStatus_FLAT_STATUS_UNSPECIFIED
+}
+
+func (x *Flat) GetNumber() int32 {
+	if x != nil && x.Number != nil {
+		return *x.Number
+	}
+	return 0
+}
+
+func (x *Flat) GetFloor() int32 {
+	if x != nil && x.Floor != nil {
+		return *x.Floor
+	}
+	return 0
+}
+
+func (x *Flat) GetTotalArea() float64 {
+	if x != nil && x.TotalArea != nil {
+		return *x.TotalArea
+	}
+	return 0
+}
+
+func (x *Flat) GetLivingArea() float64 {
+	if x != nil && x.LivingArea != nil {
+		return *x.LivingArea
+	}
+	return 0
+}
+
+func (x *Flat) GetBalcony() bool {
+	if x != nil && x.Balcony != nil {
+		return *x.Balcony
+	}
+	return false
+}
+
+func (x *Flat) GetListingType() string {
+	if x != nil {
+		return x.ListingType
+	}
+	return ""
+}
+
+func (x *Flat) GetRentPeriod() string {
+	if x != nil {
+		return x.RentPeriod
+	}
+	return ""
+}
+
+func (x *Flat) GetDeposit() int64 {
+	if x != nil && x.Deposit != nil {
+		return *x.Deposit
+	}
+	return 0
+}
+
+func (x *Flat) GetCommission() int32 {
+	if x != nil && x.Commission != nil {
+		return *x.Commission
+	}
+	return 0
+}
+
+func (x *Flat) GetUtilitiesIncluded() bool {
+	if x != nil && x.UtilitiesIncluded != nil {
+		return *x.UtilitiesIncluded
+	}
+	return false
+}
+
+type User struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserId   string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Email    string   `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	UserType UserType `protobuf:"varint,3,opt,name=user_type,json=userType,proto3,enum=avitorent.v1.UserType" json:"user_type,omitempty"`
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_avitorent_v1_models_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_avitorent_v1_models_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_avitorent_v1_models_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *User) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetUserType() UserType {
+	if x != nil {
+		return x.UserType
+	}
+	return UserType_USER_TYPE_UNSPECIFIED
+}
+
+type Subscription struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HouseId   int64                  `protobuf:"varint,1,opt,name=house_id,json=houseId,proto3" json:"house_id,omitempty"`
+	Email     string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Locale    string                 `protobuf:"bytes,4,opt,name=locale,proto3" json:"locale,omitempty"`
+	Delivery  string                 `protobuf:"bytes,5,opt,name=delivery,proto3" json:"delivery,omitempty"`
+}
+
+func (x *Subscription) Reset() {
+	*x = Subscription{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_avitorent_v1_models_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Subscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscription) ProtoMessage() {}
+
+func (x *Subscription) ProtoReflect() protoreflect.Message {
+	mi := &file_avitorent_v1_models_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
+func (*Subscription) Descriptor() ([]byte, []int) {
+	return file_avitorent_v1_models_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Subscription) GetHouseId() int64 {
+	if x != nil {
+		return x.HouseId
+	}
+	return 0
+}
+
+func (x *Subscription) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Subscription) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Subscription) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *Subscription) GetDelivery() string {
+	if x != nil {
+		return x.Delivery
+	}
+	return ""
+}
+
+var File_avitorent_v1_models_proto protoreflect.FileDescriptor
+
+var file_avitorent_v1_models_proto_rawDesc = []byte{
+	0x0a, 0x19, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2f, 0x76, 0x31, 0x2f, 0x6d,
+	0x6f, 0x64, 0x65, 0x6c, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x61, 0x76, 0x69,
+	0x74, 0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf6, 0x02, 0x0a, 0x05, 0x48,
+	0x6f, 0x75, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x79, 0x65, 0x61, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x79, 0x65,
+	0x61, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x72, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x65, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x72,
+	0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1b, 0x0a, 0x06, 0x66, 0x6c, 0x6f, 0x6f, 0x72, 0x73,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x06, 0x66, 0x6c, 0x6f, 0x6f, 0x72, 0x73,
+	0x88, 0x01, 0x01, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x61, 0x74, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x61, 0x74, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x12,
+	0x1f, 0x0a, 0x08, 0x65, 0x6c, 0x65, 0x76, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x08, 0x48, 0x01, 0x52, 0x08, 0x65, 0x6c, 0x65, 0x76, 0x61, 0x74, 0x6f, 0x72, 0x88, 0x01, 0x01,
+	0x12, 0x1d, 0x0a, 0x07, 0x70, 0x61, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x08, 0x48, 0x02, 0x52, 0x07, 0x70, 0x61, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x88, 0x01, 0x01, 0x42,
+	0x09, 0x0a, 0x07, 0x5f, 0x66, 0x6c, 0x6f, 0x6f, 0x72, 0x73, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x65,
+	0x6c, 0x65, 0x76, 0x61, 0x74, 0x6f, 0x72, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x70, 0x61, 0x72, 0x6b,
+	0x69, 0x6e, 0x67, 0x22, 0xde, 0x04, 0x0a, 0x04, 0x46, 0x6c, 0x61, 0x74, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x19, 0x0a, 0x08,
+	0x68, 0x6f, 0x75, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
+	0x68, 0x6f, 0x75, 0x73, 0x65, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x72, 0x6f, 0x6f, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x72, 0x6f,
+	0x6f, 0x6d, 0x73, 0x12, 0x30, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x46, 0x6c, 0x61, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1b, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x88,
+	0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x66, 0x6c, 0x6f, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x05, 0x48, 0x01, 0x52, 0x05, 0x66, 0x6c, 0x6f, 0x6f, 0x72, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a,
+	0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x61, 0x72, 0x65, 0x61, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x01, 0x48, 0x02, 0x52, 0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x41, 0x72, 0x65, 0x61, 0x88, 0x01,
+	0x01, 0x12, 0x24, 0x0a, 0x0b, 0x6c, 0x69, 0x76, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x72, 0x65, 0x61,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x48, 0x03, 0x52, 0x0a, 0x6c, 0x69, 0x76, 0x69, 0x6e, 0x67,
+	0x41, 0x72, 0x65, 0x61, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x62, 0x61, 0x6c, 0x63, 0x6f,
+	0x6e, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x48, 0x04, 0x52, 0x07, 0x62, 0x61, 0x6c, 0x63,
+	0x6f, 0x6e, 0x79, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x69, 0x73, 0x74, 0x69, 0x6e,
+	0x67, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6c, 0x69,
+	0x73, 0x74, 0x69, 0x6e, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x6e,
+	0x74, 0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x72, 0x65, 0x6e, 0x74, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x12, 0x1d, 0x0a, 0x07, 0x64, 0x65,
+	0x70, 0x6f, 0x73, 0x69, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x48, 0x05, 0x52, 0x07, 0x64,
+	0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x63, 0x6f, 0x6d,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x05, 0x48, 0x06, 0x52,
+	0x0a, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x32,
+	0x0a, 0x12, 0x75, 0x74, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x5f, 0x69, 0x6e, 0x63, 0x6c,
+	0x75, 0x64, 0x65, 0x64, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08, 0x48, 0x07, 0x52, 0x11, 0x75, 0x74,
+	0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x88,
+	0x01, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x42, 0x08, 0x0a,
+	0x06, 0x5f, 0x66, 0x6c, 0x6f, 0x6f, 0x72, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x5f, 0x61, 0x72, 0x65, 0x61, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6c, 0x69, 0x76, 0x69, 0x6e,
+	0x67, 0x5f, 0x61, 0x72, 0x65, 0x61, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x62, 0x61, 0x6c, 0x63, 0x6f,
+	0x6e, 0x79, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x64, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x42, 0x0d,
+	0x0a, 0x0b, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x15, 0x0a,
+	0x13, 0x5f, 0x75, 0x74, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x5f, 0x69, 0x6e, 0x63, 0x6c,
+	0x75, 0x64, 0x65, 0x64, 0x22, 0x6a, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x12, 0x17, 0x0a, 0x07,
+	0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75,
+	0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x33, 0x0a, 0x09, 0x75,
+	0x73, 0x65, 0x72, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16,
+	0x2e, 0x61, 0x76, 0x69, 0x74, 0x6f, 0x72, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x73,
+	0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65,
+	0x22, 0xae, 0x01, 0x0a, 0x0c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x19, 0x0a, 0x08, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x07, 0x68, 0x6f, 0x75, 0x73, 0x65, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05,
+	0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61,
+	0x69, 0x6c, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c,
+	0x6f, 0x63, 0x61, 0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72,
+	0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72,
+	0x79, 0x2a, 0xdc, 0x01, 0x0a, 0x0a, 0x46, 0x6c, 0x61, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x1b, 0x0a, 0x17, 0x46, 0x4c, 0x41, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x17, 0x0a,
+	0x13, 0x46, 0x4c, 0x41, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x43, 0x52, 0x45,
+	0x41, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x1d, 0x0a, 0x19, 0x46, 0x4c, 0x41, 0x54, 0x5f, 0x53,
+	0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x4f, 0x4e, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x52, 0x41, 0x54,
+	0x49, 0x4f, 0x4e, 0x10, 0x02, 0x12, 0x18, 0x0a, 0x14, 0x46, 0x4c, 0x41, 0x54, 0x5f, 0x53, 0x54,
+	0x41, 0x54, 0x55, 0x53, 0x5f, 0x41, 0x50, 0x50, 0x52, 0x4f, 0x56, 0x45, 0x44, 0x10, 0x03, 0x12,
+	0x18, 0x0a, 0x14, 0x46, 0x4c, 0x41, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x44,
+	0x45, 0x43, 0x4c, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x04, 0x12, 0x17, 0x0a, 0x13, 0x46, 0x4c, 0x41,
+	0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x45, 0x58, 0x50, 0x49, 0x52, 0x45, 0x44,
+	0x10, 0x05, 0x12, 0x16, 0x0a, 0x12, 0x46, 0x4c, 0x41, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55,
+	0x53, 0x5f, 0x52, 0x45, 0x4e, 0x54, 0x45, 0x44, 0x10, 0x06, 0x12, 0x14, 0x0a, 0x10, 0x46, 0x4c,
+	0x41, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x53, 0x4f, 0x4c, 0x44, 0x10, 0x07,
+	0x2a, 0x54, 0x0a, 0x08, 0x55, 0x73, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x19, 0x0a, 0x15,
+	0x55, 0x53, 0x45, 0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x55, 0x53, 0x45, 0x52, 0x5f,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x43, 0x4c, 0x49, 0x45, 0x4e, 0x54, 0x10, 0x01, 0x12, 0x17, 0x0a,
+	0x13, 0x55, 0x53, 0x45, 0x52, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x52,
+	0x41, 0x54, 0x4f, 0x52, 0x10, 0x02, 0x42, 0x2f, 0x5a, 0x2d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x65, 0x6c, 0x61, 0x70, 0x61, 0x73, 0x6b, 0x61, 0x2f, 0x61,
+	0x76, 0x69, 0x74, 0x6f, 0x2d, 0x72, 0x65, 0x6e, 0x74, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x61, 0x70,
+	0x69, 0x2f, 0x70, 0x62, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_avitorent_v1_models_proto_rawDescOnce sync.Once
+	file_avitorent_v1_models_proto_rawDescData = file_avitorent_v1_models_proto_rawDesc
+)
+
+func file_avitorent_v1_models_proto_rawDescGZIP() []byte {
+	file_avitorent_v1_models_proto_rawDescOnce.Do(func() {
+		file_avitorent_v1_models_proto_rawDescData = protoimpl.X.CompressGZIP(file_avitorent_v1_models_proto_rawDescData)
+	})
+	return file_avitorent_v1_models_proto_rawDescData
+}
+
+var file_avitorent_v1_models_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_avitorent_v1_models_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_avitorent_v1_models_proto_goTypes = []any{
+	(FlatStatus)(0),               // 0: avitorent.v1.FlatStatus
+	(UserType)(0),                 // 1: avitorent.v1.UserType
+	(*House)(nil),                 // 2: avitorent.v1.House
+	(*Flat)(nil),                  // 3: avitorent.v1.Flat
+	(*User)(nil),                  // 4: avitorent.v1.User
+	(*Subscription)(nil),          // 5: avitorent.v1.Subscription
+	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+}
+var file_avitorent_v1_models_proto_depIdxs = []int32{
+	6, // 0: avitorent.v1.House.created_at:type_name -> google.protobuf.Timestamp
+	6, // 1: avitorent.v1.House.updated_at:type_name -> google.protobuf.Timestamp
+	0, // 2: avitorent.v1.Flat.status:type_name -> avitorent.v1.FlatStatus
+	1, // 3: avitorent.v1.User.user_type:type_name -> avitorent.v1.UserType
+	6, // 4: avitorent.v1.Subscription.created_at:type_name -> google.protobuf.Timestamp
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_avitorent_v1_models_proto_init() }
+func file_avitorent_v1_models_proto_init() {
+	if File_avitorent_v1_models_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_avitorent_v1_models_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*House); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_avitorent_v1_models_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*Flat); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_avitorent_v1_models_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*User); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_avitorent_v1_models_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*Subscription); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_avitorent_v1_models_proto_msgTypes[0].OneofWrappers = []any{}
+	file_avitorent_v1_models_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_avitorent_v1_models_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_avitorent_v1_models_proto_goTypes,
+		DependencyIndexes: file_avitorent_v1_models_proto_depIdxs,
+		EnumInfos:         file_avitorent_v1_models_proto_enumTypes,
+		MessageInfos:      file_avitorent_v1_models_proto_msgTypes,
+	}.Build()
+	File_avitorent_v1_models_proto = out.File
+	file_avitorent_v1_models_proto_rawDesc = nil
+	file_avitorent_v1_models_proto_goTypes = nil
+	file_avitorent_v1_models_proto_depIdxs = nil
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/delapaska/avito-rent/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type auditKey struct{}
+
+type auditRecorder struct {
+	mu      sync.Mutex
+	entries []models.AuditEntry
+}
+
+// RecordAudit adds a change to the audit log of the current request. before
+// and after are stored as JSON, nil is stored as NULL. The change is written
+// when the request completes, together with the actor, request id and client
+// IP; outside of a request started with StartAudit it is dropped.
+func RecordAudit(ctx context.Context, action, entityType string, entityID any, before, after any) {
+	recorder, ok := ctx.Value(auditKey{}).(*auditRecorder)
+	if !ok {
+		return
+	}
+
+	entry := models.AuditEntry{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   fmt.Sprint(entityID),
+		Before:     marshalAudit(before),
+		After:      marshalAudit(after),
+	}
+
+	recorder.mu.Lock()
+	recorder.entries = append(recorder.entries, entry)
+	recorder.mu.Unlock()
+}
+
+func marshalAudit(value any) json.RawMessage {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Error marshalling audit value: %v\n", err)
+		return nil
+	}
+	return data
+}
+
+// StartAudit prepares ctx for RecordAudit. The returned function saves the
+// recorded changes to store, filling the actor and request fields from
+// request.
+func StartAudit(ctx context.Context, store models.AuditStore) (context.Context, func(request models.AuditEntry)) {
+	recorder := &auditRecorder{}
+	return context.WithValue(ctx, auditKey{}, recorder), func(request models.AuditEntry) {
+		recorder.mu.Lock()
+		entries := recorder.entries
+		recorder.entries = nil
+		recorder.mu.Unlock()
+		if len(entries) == 0 {
+			return
+		}
+
+		createdAt := time.Now().UTC()
+		for i := range entries {
+			entries[i].ActorID = request.ActorID
+			entries[i].ActorRole = request.ActorRole
+			entries[i].RequestID = request.RequestID
+			entries[i].ClientIP = request.ClientIP
+			entries[i].CreatedAt = createdAt
+		}
+		if err := store.AddEntries(entries); err != nil {
+			log.Printf("Error saving audit log: %v\n", err)
+		}
+	}
+}
+
+// Audit saves the changes handlers record with RecordAudit on
+// c.Request.Context().
+func Audit(store models.AuditStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, save := StartAudit(c.Request.Context(), store)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		request := models.AuditEntry{
+			ActorRole: c.GetString("userType"),
+			RequestID: c.GetString("RequestId"),
+			ClientIP:  c.ClientIP(),
+		}
+		if userID, ok := c.Get("userID"); ok {
+			if actorID, ok := userID.(uuid.UUID); ok {
+				request.ActorID = &actorID
+			}
+		}
+		save(request)
+	}
+}
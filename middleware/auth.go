@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
@@ -27,6 +28,20 @@ func GenerateJWT(userID uuid.UUID, userType string) (string, error) {
 	return token.SignedString([]byte(configs.Envs.JWTSecret))
 }
 
+func ParseJWT(tokenString string) (*models.Claims, error) {
+	claims := &models.Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(configs.Envs.JWTSecret), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return claims, nil
+}
+
 func AuthMiddleware(allowedRoles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		requestId, _ := c.Get("RequestId")
@@ -42,13 +57,8 @@ func AuthMiddleware(allowedRoles ...string) gin.HandlerFunc {
 			return
 		}
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		claims := &models.Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(configs.Envs.JWTSecret), nil
-		})
-
-		if err != nil || !token.Valid {
+		claims, err := ParseJWT(strings.TrimPrefix(authHeader, "Bearer "))
+		if err != nil {
 			c.Header("Retry-After", "30")
 			utils.WriteJSON(c, http.StatusUnauthorized, gin.H{
 				"message":    "Invalid token",
@@ -56,7 +56,11 @@ func Logger() gin.HandlerFunc {
 			"client_ip":   clientIP,
 			"method":      method,
 			"path":        path,
+			"request_id":  c.GetString("RequestId"),
 		})
+		if userID, ok := c.Get("userID"); ok {
+			entry = entry.WithField("user_id", userID)
+		}
 
 		if len(c.Errors) > 0 {
 			entry.Error(c.Errors.ByType(gin.ErrorTypePrivate).String())
@@ -4,12 +4,18 @@ const (
 	approved = iota
 	declined
 	onmoderation
+	expired
+	rented
+	sold
 )
 
 var statusMap = map[string]int{
 	"approved":      approved,
 	"declined":      declined,
 	"on moderation": onmoderation,
+	"expired":       expired,
+	"rented":        rented,
+	"sold":          sold,
 }
 
 func StatusExists(role string) bool {
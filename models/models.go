@@ -1,6 +1,10 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt"
@@ -20,8 +24,127 @@ const (
 
 	// StatusOnModeration Flat is under moderation and approval is pending
 	StatusOnModeration string = "on moderation"
+
+	// StatusExpired Flat was approved but its listing expired; the owner can renew it
+	StatusExpired string = "expired"
+
+	// StatusRented Flat for rent has been rented out; set by its owner
+	StatusRented string = "rented"
+
+	// StatusSold Flat for sale has been sold; set by its owner
+	StatusSold string = "sold"
 )
 
+// @Description Language of notifications
+const (
+	// LocaleRU Russian, the default
+	LocaleRU string = "ru"
+
+	// LocaleEN English
+	LocaleEN string = "en"
+)
+
+// @Description How often a subscriber gets notified
+const (
+	// DeliveryInstant One email per approved flat
+	DeliveryInstant string = "instant"
+
+	// DeliveryDaily One digest email per day
+	DeliveryDaily string = "daily"
+)
+
+// @Description Whether a flat is sold or let
+const (
+	// ListingSale The flat is for sale, the default
+	ListingSale string = "sale"
+
+	// ListingRent The flat is for rent
+	ListingRent string = "rent"
+)
+
+// @Description Period the rent of a flat is charged for
+const (
+	// RentMonthly Price is per month
+	RentMonthly string = "monthly"
+
+	// RentDaily Price is per day
+	RentDaily string = "daily"
+)
+
+// @Description Material of the walls of a house
+const (
+	// MaterialBrick Brick
+	MaterialBrick string = "brick"
+
+	// MaterialPanel Concrete panels
+	MaterialPanel string = "panel"
+
+	// MaterialMonolith Monolithic concrete
+	MaterialMonolith string = "monolith"
+
+	// MaterialMonolithBrick Monolithic frame with brick walls
+	MaterialMonolithBrick string = "monolith_brick"
+
+	// MaterialBlock Concrete or gas blocks
+	MaterialBlock string = "block"
+
+	// MaterialWood Wood
+	MaterialWood string = "wood"
+)
+
+// @Description What pre-moderation does with a new flat that breaks a rule
+const (
+	// RuleActionDecline The flat is declined right away
+	RuleActionDecline string = "decline"
+
+	// RuleActionHighRisk The flat waits for moderation marked as high risk
+	RuleActionHighRisk string = "high_risk"
+
+	// RuleActionPass The hit is only recorded
+	RuleActionPass string = "pass"
+)
+
+// @Description Rule a new flat broke during pre-moderation
+
+// @Name RuleHit
+// @Example { "rule": "rooms", "action": "decline", "reason": "50 rooms, at most 10 allowed" }
+type RuleHit struct {
+	// @Description Name of the rule: price, rooms or banned_words
+	// @Example "rooms"
+	Rule string `json:"rule"`
+	// @Description Action the rule is configured with
+	// @Enum decline,high_risk,pass
+	// @Example "decline"
+	Action string `json:"action"`
+	// @Description Why the flat broke the rule
+	// @Example "50 rooms, at most 10 allowed"
+	Reason string `json:"reason"`
+}
+
+// RuleHits are stored as a JSON array, NULL when empty.
+type RuleHits []RuleHit
+
+func (h RuleHits) Value() (driver.Value, error) {
+	if len(h) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(h)
+}
+
+func (h *RuleHits) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*h = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(src, h)
+	case string:
+		return json.Unmarshal([]byte(src), h)
+	default:
+		return fmt.Errorf("cannot scan %T into RuleHits", src)
+	}
+}
+
 type DummyStore interface{}
 
 // @Description Payload for dummy login
@@ -37,15 +160,90 @@ type Claims struct {
 	jwt.StandardClaims
 }
 
+var (
+	// ErrArchived is returned when archiving a house or a flat that is already archived
+	// and when changing the status of an archived flat.
+	ErrArchived = errors.New("already archived")
+
+	// ErrNotArchived is returned when restoring a house or a flat that is not archived.
+	ErrNotArchived = errors.New("not archived")
+
+	// ErrHouseNotFound is returned when creating a flat in a house that does not exist.
+	ErrHouseNotFound = errors.New("house not found")
+
+	// ErrHouseArchived is returned when creating or restoring a flat of an archived house.
+	ErrHouseArchived = errors.New("the house of the flat is archived")
+
+	// ErrDeveloperNotFound is returned when a house refers to a missing developer.
+	ErrDeveloperNotFound = errors.New("developer not found")
+
+	// ErrFlatNumberTaken is returned when the house already has a flat with the same number.
+	ErrFlatNumberTaken = errors.New("the house already has a flat with this number")
+
+	// ErrFloorAboveHouse is returned when the floor of a flat is above the top floor of its house.
+	ErrFloorAboveHouse = errors.New("floor is higher than the number of floors of the house")
+
+	// ErrNotExpired is returned when renewing a flat whose listing has not expired.
+	ErrNotExpired = errors.New("the listing of the flat has not expired")
+
+	// ErrNotFlatOwner is returned when someone but the owner marks a flat as rented or sold.
+	ErrNotFlatOwner = errors.New("only the owner of the flat can mark it as rented or sold")
+
+	// ErrNotListed is returned when marking a flat that is neither approved nor expired as rented or sold.
+	ErrNotListed = errors.New("only approved or expired flats can be marked as rented or sold")
+
+	// ErrDealStatus is returned when a rental is marked as sold or a flat for sale as rented.
+	ErrDealStatus = errors.New("flats for rent can only be rented and flats for sale can only be sold")
+
+	// ErrInvalidFinalPrice is returned when the final price of a deal is not positive.
+	ErrInvalidFinalPrice = errors.New("final_price must be positive")
+
+	// ErrInvalidTransition is returned when a flat cannot move from its status to the requested one.
+	ErrInvalidTransition = errors.New("invalid status change")
+
+	// ErrNotAssignedModerator is returned when a moderator approves or declines a flat another moderator took.
+	ErrNotAssignedModerator = errors.New("only the assigned moderator can change the status")
+
+	// ErrWebhookNotHTTPS is returned when a webhook URL does not use https.
+	ErrWebhookNotHTTPS = errors.New("webhook url must use https")
+
+	// ErrWebhookAddress is returned when a webhook host does not resolve to public addresses only.
+	ErrWebhookAddress = errors.New("webhook url must resolve to a public address")
+)
+
 type HouseStore interface {
 	CreateHouse(house House) (House, error)
-	GetHouseFlats(houseID string, userRole string) ([]Flat, error)
-	AddSubscription(houseID, email string) error
+	// GetHouseFlats and GetAllHouseFlats return the flats matching filter,
+	// every flat when filter is empty.
+	GetHouseFlats(houseID string, userRole string, filter FlatFilter) ([]Flat, error)
+	GetAllHouseFlats(houseID string, filter FlatFilter) ([]Flat, error)
+	ArchiveHouse(id int) (House, error)
+	RestoreHouse(id int) (House, error)
+	AddSubscription(subscription Subscription) error
+	GetHousesByIDs(ids []int) ([]House, error)
+	GetFlatsByHouseIDs(houseIDs []int, userRole string) ([]Flat, error)
+	// GetHouseStats aggregates the active flats of the houses whatever the
+	// role of the user, leaving out houses without any.
+	GetHouseStats(houseIDs []int) ([]HouseStats, error)
+	GetSubscriptions(houseIDs []int, email string) ([]Subscription, error)
+	GetHouseSubscribers(houseID int) ([]Subscription, error)
+	// GetLastDigestDay returns the last day whose digests were all delivered,
+	// nil before the first one.
+	GetLastDigestDay() (*time.Time, error)
+	// ClaimDigest reports whether the digest of the day for email is not sent
+	// and not claimed by another instance within lease, and claims it.
+	ClaimDigest(day time.Time, email string, lease time.Duration) (bool, error)
+	MarkDigestSent(day time.Time, email string) error
+	ReleaseDigest(day time.Time, email string) error
+	// CompleteDigest records the day as delivered once digests emails of it
+	// have been sent.
+	CompleteDigest(day time.Time, digests int) error
+	GetDigestEntries(from, to time.Time) ([]DigestEntry, error)
 }
 
 // @description House представляет собой структуру данных для хранения информации о доме.
 // @name House
-// @example { "id": 1, "address": "123 Elm Street", "year": 2020, "developer": "XYZ Construction", "created_at": "2024-08-04T00:00:00Z", "updated_at": "2024-08-04T00:00:00Z" }
+// @example { "id": 1, "address": "123 Elm Street", "year": 2020, "developer": "XYZ Construction", "developer_id": 3, "floors": 17, "material": "panel", "elevator": true, "parking": false, "created_at": "2024-08-04T00:00:00Z", "updated_at": "2024-08-04T00:00:00Z" }
 type House struct {
 	// @description Идентификатор дома
 	// @example 1
@@ -63,6 +261,27 @@ type House struct {
 	// @example "XYZ Construction"
 	Developer string `json:"developer"`
 
+	// @description Идентификатор застройщика, отсутствует у домов без застройщика
+	// @example 3
+	DeveloperID *int `json:"developer_id,omitempty"`
+
+	// @description Количество этажей, отсутствует, если не указано
+	// @example 17
+	Floors *int `json:"floors,omitempty"`
+
+	// @description Материал стен, отсутствует, если не указан
+	// @enum brick,panel,monolith,monolith_brick,block,wood
+	// @example "panel"
+	Material string `json:"material,omitempty"`
+
+	// @description Есть ли в доме лифт, отсутствует, если не указано
+	// @example true
+	Elevator *bool `json:"elevator,omitempty"`
+
+	// @description Есть ли у дома парковка, отсутствует, если не указано
+	// @example false
+	Parking *bool `json:"parking,omitempty"`
+
 	// @description Дата создания записи
 	// @example "2024-08-04T00:00:00Z"
 	Created_at time.Time `json:"created_at"`
@@ -70,11 +289,15 @@ type House struct {
 	// @description Дата последнего обновления записи
 	// @example "2024-08-04T00:00:00Z"
 	Updated_at time.Time `json:"updated_at"`
+
+	// @description Дата архивации дома, отсутствует у действующих домов
+	// @example "2024-08-18T00:00:00Z"
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
 }
 
 // @description HousePayload представляет собой структуру данных для создания или обновления информации о доме.
 // @name HousePayload
-// @example { "address": "123 Elm Street", "year": 2020, "developer": "XYZ Construction" }
+// @example { "address": "123 Elm Street", "year": 2020, "developer_id": 3, "floors": 17, "material": "panel", "elevator": true, "parking": false }
 type HousePayload struct {
 	// @description Адрес дома
 	// @example "123 Elm Street"
@@ -84,19 +307,76 @@ type HousePayload struct {
 	// @example 2020
 	Year int `json:"year" validate:"required"`
 
-	// @description Разработчик или строитель дома
+	// @description Название застройщика; если такого ещё нет, он будет создан. Нельзя передавать вместе с developer_id
 	// @example "XYZ Construction"
 	Developer string `json:"developer"`
+
+	// @description Идентификатор застройщика из /developers
+	// @example 3
+	DeveloperID *int `json:"developer_id" validate:"omitempty,min=1"`
+
+	// @description Количество этажей
+	// @example 17
+	Floors *int `json:"floors" validate:"omitempty,min=1,max=200"`
+
+	// @description Материал стен
+	// @enum brick,panel,monolith,monolith_brick,block,wood
+	// @example "panel"
+	Material string `json:"material" validate:"omitempty,oneof=brick panel monolith monolith_brick block wood"`
+
+	// @description Есть ли в доме лифт
+	// @example true
+	Elevator *bool `json:"elevator"`
+
+	// @description Есть ли у дома парковка
+	// @example false
+	Parking *bool `json:"parking"`
+}
+
+// Sort orders of house flat listings
+const (
+	// SortPricePerM2 Cheapest price per square meter first
+	SortPricePerM2 string = "price_per_m2"
+
+	// SortPricePerM2Desc Most expensive price per square meter first
+	SortPricePerM2Desc string = "-price_per_m2"
+)
+
+// FlatFilter narrows down and orders the flats of a house. Empty fields do
+// not filter; flats without an area never match a price per square meter
+// range and come last when sorted by it.
+type FlatFilter struct {
+	ListingType   string
+	PricePerM2Min *float64
+	PricePerM2Max *float64
+	Sort          string
 }
+
 type FlatStore interface {
 	CreateFlat(flat Flat) (Flat, error)
 	UpdateFlatStatus(userID uuid.UUID, flat UpdateStatusPayload) (Flat, error)
+	GetFlat(id int) (Flat, error)
+	ArchiveFlat(id int) (Flat, error)
+	RestoreFlat(id int) (Flat, error)
+	RenewFlat(id int) (Flat, error)
+	ExpireFlats(now time.Time) ([]Flat, error)
+	// ScheduleExpiry gives approved flats without an expiry date the
+	// listing TTL from now.
+	ScheduleExpiry(now time.Time) (int64, error)
+	ClaimExpiryReminders(from, to time.Time) ([]ExpiringFlat, error)
+	GetHouseSummary(id int) (HouseSummary, error)
+}
+
+// ExpiringFlat is an approved flat about to expire and the email of its owner.
+type ExpiringFlat struct {
+	Flat  Flat
+	Email string
 }
 
 // @Description Represents a flat in the system
 
 // @Name Flat
-// @Example { "id": 1, "house_id": 101, "price": 1200, "rooms": 3, "status": "created" }
+// @Example { "id": 1, "house_id": 101, "price": 1200, "rooms": 3, "status": "created", "number": 42, "floor": 5, "total_area": 64.5, "living_area": 41.2, "price_per_m2": 18.6, "balcony": true, "listing_type": "rent", "rent_period": "monthly", "deposit": 1200, "commission": 50, "utilities_included": false }
 type Flat struct {
 	// @Description Unique identifier for the flat
 	// @Example 1
@@ -104,7 +384,7 @@ type Flat struct {
 	// @Description Unique identifier for the house to which the flat belongs
 	// @Example 101
 	House_id int `json:"house_id"`
-	// @Description Price of the flat
+	// @Description Price of the flat; for rentals, the rent per rent_period
 	// @Example 1200
 	Price int `json:"price"`
 	// @Description Number of rooms in the flat
@@ -113,6 +393,111 @@ type Flat struct {
 	// @Description Status of the flat
 	// @Example "created"
 	Status string `json:"status"`
+	// @Description Number of the flat, unique within the house; absent when not given
+	// @Example 42
+	Number *int `json:"number,omitempty"`
+	// @Description Floor of the flat; absent when not given
+	// @Example 5
+	Floor *int `json:"floor,omitempty"`
+	// @Description Total area in square meters; absent when not given
+	// @Example 64.5
+	TotalArea *float64 `json:"total_area,omitempty"`
+	// @Description Living area in square meters; absent when not given
+	// @Example 41.2
+	LivingArea *float64 `json:"living_area,omitempty"`
+	// @Description Price per square meter of the total area, computed by the database; absent when the area is not given
+	// @Example 18.6
+	PricePerM2 *float64 `json:"price_per_m2,omitempty"`
+	// @Description Whether the flat has a balcony; absent when not given
+	// @Example true
+	Balcony *bool `json:"balcony,omitempty"`
+	// @Description Whether the flat is for sale or for rent
+	// @Enum sale,rent
+	// @Example "rent"
+	ListingType string `json:"listing_type,omitempty"`
+	// @Description Period the rent is charged for, only for rentals
+	// @Enum monthly,daily
+	// @Example "monthly"
+	RentPeriod string `json:"rent_period,omitempty"`
+	// @Description Deposit, only for rentals; absent when not given
+	// @Example 1200
+	Deposit *int `json:"deposit,omitempty"`
+	// @Description Agent commission in percent of the rent, only for rentals; absent when not given
+	// @Example 50
+	Commission *int `json:"commission,omitempty"`
+	// @Description Whether utilities are included in the rent, only for rentals; absent when not given
+	// @Example false
+	UtilitiesIncluded *bool `json:"utilities_included,omitempty"`
+	// @Description User who created the flat
+	OwnerID *uuid.UUID `json:"-"`
+	// @Description Date and time when the flat was archived, absent for active flats
+	// @Example "2024-08-18T09:00:00Z"
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	// @Description Flats of the same house that look like the same flat, found when it was created; only shown to the moderator taking the flat into moderation
+	// @Example [7, 12]
+	DuplicateOf []int `json:"duplicate_of,omitempty"`
+	// @Description Description of the flat; absent when not given
+	// @Example "Светлая квартира с видом на парк"
+	Description string `json:"description,omitempty"`
+	// @Description Whether pre-moderation asked to review the flat first; only shown to moderators
+	// @Example true
+	HighRisk bool `json:"high_risk,omitempty"`
+	// @Description Rules the flat broke when it was created; only shown to moderators
+	RuleHits RuleHits `json:"rule_hits,omitempty"`
+	// @Description Why pre-moderation declined the flat
+	// @Example "price 1 is below 10% of the median price 12500000 of the house"
+	DeclineReason string `json:"decline_reason,omitempty"`
+	// @Description Date and time when the listing of an approved flat expires; absent when it does not
+	// @Example "2024-09-25T09:00:00Z"
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// @Description Price the flat was rented out or sold for; absent until then
+	// @Example 1150
+	FinalPrice *int `json:"final_price,omitempty"`
+	// @Description Date and time when the owner marked the flat as rented or sold
+	// @Example "2024-09-10T09:00:00Z"
+	ClosedAt *time.Time `json:"closed_at,omitempty"`
+}
+
+// HideModeration clears what only moderators may see of the flat.
+func (f *Flat) HideModeration() {
+	f.HighRisk = false
+	f.RuleHits = nil
+	f.DuplicateOf = nil
+}
+
+// @Description Short description of the house of a flat
+
+// @Name HouseSummary
+// @Example { "id": 101, "address": "Лесная улица, 7, Москва", "year": 2003, "developer": "ПИК", "floors": 17, "material": "panel" }
+type HouseSummary struct {
+	// @Description Unique identifier of the house
+	// @Example 101
+	Id int `json:"id"`
+	// @Description Address of the house
+	// @Example "Лесная улица, 7, Москва"
+	Address string `json:"address"`
+	// @Description Year the house was built
+	// @Example 2003
+	Year int `json:"year"`
+	// @Description Name of the developer of the house; absent when not known
+	// @Example "ПИК"
+	Developer string `json:"developer,omitempty"`
+	// @Description Number of floors; absent when not given
+	// @Example 17
+	Floors *int `json:"floors,omitempty"`
+	// @Description Material of the walls; absent when not given
+	// @Example "panel"
+	Material string `json:"material,omitempty"`
+}
+
+// @Description A flat with the summary of its house
+
+// @Name FlatWithHouse
+type FlatWithHouse struct {
+	// @Description The flat
+	Flat Flat `json:"flat"`
+	// @Description Summary of the house of the flat
+	House HouseSummary `json:"house"`
 }
 
 // @Description Payload for updating the status of a flat
@@ -120,29 +505,86 @@ type Flat struct {
 // @Name UpdateStatusPayload
 // @Example { "status": "approved", "id": 1 }
 type UpdateStatusPayload struct {
-	// @Description Status to update the flat to
-	// @Enum created,approved,declined,on moderation
+	// @Description Status to update the flat to; rented and sold are set by the owner of the flat
+	// @Enum created,approved,declined,on moderation,rented,sold
 	// @Example "approved"
-	Status string `json:"status" validate:"required oneof=created on moderation approved declined"`
+	Status string `json:"status" validate:"required oneof=created on moderation approved declined rented sold"`
 	// @Description Unique identifier of the flat to update
 	// @Example 1
 	Id int `json:"id" validate:"required"`
+	// @Description Price the flat was rented out or sold for, only with rented and sold; the listed price when omitted
+	// @Example 1150
+	FinalPrice *int `json:"final_price,omitempty"`
 }
 
 // @Description Payload for creating a new flat
 
 // @Name FlatPayload
-// @Example { "house_id": 101, "price": 1200, "rooms": 3 }
+// @Example { "house_id": 101, "price": 1200, "rooms": 3, "number": 42, "floor": 5, "total_area": 64.5, "living_area": 41.2, "balcony": true, "listing_type": "rent", "rent_period": "monthly", "deposit": 1200, "commission": 50, "utilities_included": false }
 type FlatPayload struct {
 	// @Description Unique identifier of the house to which the flat belongs
 	// @Example 101
 	House_id int `json:"house_id" validate:"required"`
-	// @Description Price of the flat
+	// @Description Price of the flat; for rentals, the rent per rent_period
 	// @Example 1200
 	Price int `json:"price" validate:"required"`
 	// @Description Number of rooms in the flat
 	// @Example 3
 	Rooms int `json:"rooms" validate:"required"`
+	// @Description Number of the flat, unique within the house
+	// @Example 42
+	Number *int `json:"number" validate:"omitempty,min=1"`
+	// @Description Floor of the flat, not higher than the number of floors of the house
+	// @Example 5
+	Floor *int `json:"floor" validate:"omitempty,min=1,max=200"`
+	// @Description Total area in square meters
+	// @Example 64.5
+	TotalArea *float64 `json:"total_area" validate:"omitempty,gt=0,max=10000"`
+	// @Description Living area in square meters, not larger than the total area
+	// @Example 41.2
+	LivingArea *float64 `json:"living_area" validate:"omitempty,gt=0,max=10000"`
+	// @Description Whether the flat has a balcony
+	// @Example true
+	Balcony *bool `json:"balcony"`
+	// @Description Whether the flat is for sale or for rent, sale by default
+	// @Enum sale,rent
+	// @Example "rent"
+	ListingType string `json:"listing_type" validate:"omitempty,oneof=sale rent"`
+	// @Description Period the rent is charged for, required for rentals and not allowed for sales
+	// @Enum monthly,daily
+	// @Example "monthly"
+	RentPeriod string `json:"rent_period" validate:"omitempty,oneof=monthly daily"`
+	// @Description Deposit, only for rentals
+	// @Example 1200
+	Deposit *int `json:"deposit" validate:"omitempty,min=0"`
+	// @Description Agent commission in percent of the rent, only for rentals
+	// @Example 50
+	Commission *int `json:"commission" validate:"omitempty,min=0,max=100"`
+	// @Description Whether utilities are included in the rent, only for rentals
+	// @Example false
+	UtilitiesIncluded *bool `json:"utilities_included"`
+	// @Description Description of the flat
+	// @Example "Светлая квартира с видом на парк"
+	Description string `json:"description" validate:"max=2000"`
+}
+
+// Check enforces the rules of the payload the validate tags cannot express
+// and defaults the listing type to sale.
+func (p *FlatPayload) Check() error {
+	if p.TotalArea != nil && p.LivingArea != nil && *p.LivingArea > *p.TotalArea {
+		return errors.New("living_area cannot be larger than total_area")
+	}
+	if p.ListingType == "" {
+		p.ListingType = ListingSale
+	}
+	if p.ListingType == ListingRent && p.RentPeriod == "" {
+		return errors.New("rent_period is required for rentals")
+	}
+	if p.ListingType == ListingSale &&
+		(p.RentPeriod != "" || p.Deposit != nil || p.Commission != nil || p.UtilitiesIncluded != nil) {
+		return errors.New("rent_period, deposit, commission and utilities_included are only allowed for rentals")
+	}
+	return nil
 }
 
 type UserStore interface {
@@ -196,7 +638,7 @@ type LoginUserPayload struct {
 // @Description Subscription information
 
 // @Name Subscription
-// @Example { "id": 1, "house_id": "house_123", "email": "user@example.com", "created_at": "2023-07-21T17:32:28Z" }
+// @Example { "id": 1, "house_id": "house_123", "email": "user@example.com", "locale": "ru", "delivery": "instant", "created_at": "2023-07-21T17:32:28Z" }
 type Subscription struct {
 	// @Description Unique identifier of the subscription
 	// @Example 1
@@ -210,6 +652,14 @@ type Subscription struct {
 	// @Example "user@example.com"
 	Email string `json:"email"`
 
+	// @Description Language of the notifications
+	// @Example "ru"
+	Locale string `json:"locale"`
+
+	// @Description How often the subscriber is notified
+	// @Example "instant"
+	Delivery string `json:"delivery"`
+
 	// @Description Date and time when the subscription was created
 	// @Example "2023-07-21T17:32:28Z"
 	CreatedAt time.Time `json:"created_at"`
@@ -218,9 +668,726 @@ type Subscription struct {
 // @Description Payload for subscribing to house updates
 
 // @Name SubscribePayload
-// @Example { "email": "user@example.com" }
+// @Example { "email": "user@example.com", "locale": "en", "delivery": "daily" }
 type SubscribePayload struct {
 	// @Description Email address to subscribe to house updates
 	// @Example "user@example.com"
 	Email string `json:"email" validate:"required,email"`
+	// @Description Language of the notifications, ru by default
+	// @Example "en"
+	Locale string `json:"locale" validate:"omitempty,oneof=ru en"`
+	// @Description instant for an email per approved flat (default), daily for one digest a day
+	// @Example "daily"
+	Delivery string `json:"delivery" validate:"omitempty,oneof=instant daily"`
+}
+
+// HouseStats counts the active flats of a house by status. Prices are taken
+// over the approved flats and final prices over the closed deals.
+type HouseStats struct {
+	HouseID                                                                 int
+	Flats, Created, OnModeration, Approved, Declined, Expired, Rented, Sold int
+	MinPrice, MaxPrice                                                      *int
+	AvgPrice                                                                *float64
+	MinPricePerM2, MaxPricePerM2, AvgPricePerM2                             *float64
+	AvgSoldPrice, AvgMonthlyRent                                            *float64
+}
+
+// @Description Approved flat to include in a daily digest
+type DigestEntry struct {
+	Email  string
+	Locale string
+	House  House
+	Flat   Flat
+}
+
+// @Description GraphQL request
+
+// @Name GraphQLPayload
+// @Example { "query": "query($id: ID!) { house(id: $id) { address flats { id price status } stats { flats avgPrice } subscription { email } } }", "variables": { "id": "1" } }
+type GraphQLPayload struct {
+	// @Description GraphQL query or mutation
+	Query string `json:"query" validate:"required"`
+	// @Description Operation to run when the query contains several
+	OperationName string `json:"operationName"`
+	// @Description Values of the query variables
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// @Description Event kinds pushed to house event streams
+const (
+	// EventFlatCreated A flat has been added to the house
+	EventFlatCreated string = "flat_created"
+
+	// EventFlatApproved A flat has passed moderation
+	EventFlatApproved string = "flat_approved"
+
+	// EventFlatPriceChanged The price of a flat has changed
+	EventFlatPriceChanged string = "flat_price_changed"
+)
+
+type HouseEventStore interface {
+	// GetHouseEvents returns the events after afterID and also the events
+	// created up to lookBack before it, which may have committed after it.
+	GetHouseEvents(houseID int, afterID int64, lookBack time.Duration) ([]HouseEvent, error)
+	GetHouseEvent(id int64) (HouseEvent, error)
+}
+
+// @Description Change of a flat in a house, delivered over the house event stream
+
+// @Name HouseEvent
+// @Example { "id": 42, "house_id": 1, "type": "flat_price_changed", "flat": { "id": 3, "house_id": 1, "price": 9500000, "rooms": 2, "status": "approved" }, "old_price": 10000000, "created_at": "2024-08-10T12:00:00Z" }
+type HouseEvent struct {
+	// @Description Event identifier, used as the SSE event id
+	// @Example 42
+	ID int64 `json:"id"`
+	// @Description Identifier of the house
+	// @Example 1
+	HouseID int `json:"house_id"`
+	// @Description Kind of the event
+	// @Enum flat_created,flat_approved,flat_price_changed
+	// @Example "flat_price_changed"
+	Type string `json:"type"`
+	// @Description State of the flat after the change
+	Flat Flat `json:"flat"`
+	// @Description Previous price for flat_price_changed events
+	// @Example 10000000
+	OldPrice *int `json:"old_price,omitempty"`
+	// @Description Date and time of the change
+	// @Example "2024-08-10T12:00:00Z"
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// @Description Status of a webhook delivery
+const (
+	// DeliveryPending Delivery is waiting for its next attempt
+	DeliveryPending string = "pending"
+
+	// DeliverySucceeded The endpoint accepted the event
+	DeliverySucceeded string = "succeeded"
+
+	// DeliveryFailed All attempts failed or the webhook was disabled
+	DeliveryFailed string = "failed"
+)
+
+type WebhookStore interface {
+	CreateWebhook(webhook Webhook) (Webhook, error)
+	GetWebhooks(userID uuid.UUID) ([]Webhook, error)
+	GetWebhook(id int, userID uuid.UUID) (Webhook, error)
+	DeleteWebhook(id int, userID uuid.UUID) error
+	EnableWebhook(id int, userID uuid.UUID) (Webhook, error)
+	GetDeliveryAttempts(webhookID int, limit int) ([]WebhookDeliveryAttempt, error)
+	ClaimDueDeliveries(limit int, lease time.Duration) ([]WebhookDelivery, error)
+	RecordDeliveryAttempt(attempt WebhookDeliveryAttempt, retryAt *time.Time, disableAfter int) error
+}
+
+// @Description Webhook registered by a partner
+
+// @Name Webhook
+// @Example { "id": 1, "url": "https://partner.example.com/hooks/avito", "event_types": ["flat_approved"], "house_ids": [1, 2], "active": true, "failure_count": 0, "created_at": "2024-08-11T09:00:00Z" }
+type Webhook struct {
+	// @Description Unique identifier of the webhook
+	// @Example 1
+	ID int `json:"id"`
+	// @Description Owner of the webhook
+	UserID uuid.UUID `json:"-"`
+	// @Description Role of the owner, clients only receive events about approved flats
+	UserType string `json:"-"`
+	// @Description Endpoint the events are POSTed to
+	// @Example "https://partner.example.com/hooks/avito"
+	URL string `json:"url"`
+	// @Description HMAC-SHA256 key, only returned when the webhook is created
+	// @Example "5f1d7c9a..."
+	Secret string `json:"secret,omitempty"`
+	// @Description Events to deliver
+	// @Example ["flat_approved"]
+	EventTypes []string `json:"event_types"`
+	// @Description Houses to deliver events for, empty for all houses
+	// @Example [1, 2]
+	HouseIDs []int `json:"house_ids"`
+	// @Description Whether events are delivered
+	// @Example true
+	Active bool `json:"active"`
+	// @Description Consecutive failed attempts
+	// @Example 0
+	FailureCount int `json:"failure_count"`
+	// @Description When the webhook was disabled after repeated failures
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+	// @Description Date and time when the webhook was created
+	// @Example "2024-08-11T09:00:00Z"
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// @Description Payload for registering a webhook
+
+// @Name WebhookPayload
+// @Example { "url": "https://partner.example.com/hooks/avito", "secret": "my-signing-secret", "event_types": ["flat_created", "flat_approved"], "house_ids": [1] }
+type WebhookPayload struct {
+	// @Description Endpoint the events are POSTed to
+	// @Example "https://partner.example.com/hooks/avito"
+	URL string `json:"url" validate:"required,url,startswith=https://"`
+	// @Description HMAC-SHA256 key, generated when omitted
+	// @Example "my-signing-secret"
+	Secret string `json:"secret" validate:"omitempty,min=16,max=255"`
+	// @Description Events to deliver
+	// @Example ["flat_created", "flat_approved"]
+	EventTypes []string `json:"event_types" validate:"required,min=1,dive,oneof=flat_created flat_approved flat_price_changed"`
+	// @Description Houses to deliver events for, empty for all houses
+	// @Example [1]
+	HouseIDs []int `json:"house_ids" validate:"dive,min=1"`
+}
+
+// @Description Webhook delivery waiting to be sent
+type WebhookDelivery struct {
+	ID        int64
+	WebhookID int
+	URL       string
+	Secret    string
+	Attempts  int
+	Event     HouseEvent
+}
+
+// @Description Attempt to deliver an event to a webhook
+
+// @Name WebhookDeliveryAttempt
+// @Example { "id": 10, "delivery_id": 5, "event_id": 42, "event_type": "flat_approved", "attempt": 2, "status_code": 500, "error": "unexpected status 500", "duration_ms": 120, "created_at": "2024-08-11T09:00:10Z" }
+type WebhookDeliveryAttempt struct {
+	// @Description Unique identifier of the attempt
+	// @Example 10
+	ID int64 `json:"id"`
+	// @Description Delivery the attempt belongs to, the same across retries
+	// @Example 5
+	DeliveryID int64 `json:"delivery_id"`
+	// @Description Webhook the event was delivered to
+	WebhookID int `json:"-"`
+	// @Description Delivered event
+	// @Example 42
+	EventID int64 `json:"event_id"`
+	// @Description Kind of the delivered event
+	// @Example "flat_approved"
+	EventType string `json:"event_type"`
+	// @Description Attempt number, starting from 1
+	// @Example 2
+	Attempt int `json:"attempt"`
+	// @Description HTTP status returned by the endpoint, absent on network errors
+	// @Example 500
+	StatusCode *int `json:"status_code,omitempty"`
+	// @Description Reason of the failure
+	// @Example "unexpected status 500"
+	Error string `json:"error,omitempty"`
+	// @Description Request duration in milliseconds
+	// @Example 120
+	DurationMs int `json:"duration_ms"`
+	// @Description Date and time of the attempt
+	// @Example "2024-08-11T09:00:10Z"
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SavedSearchStore interface {
+	CreateSavedSearch(search SavedSearch) (SavedSearch, error)
+	GetSavedSearches(userID uuid.UUID) ([]SavedSearch, error)
+	GetSavedSearch(id int, userID uuid.UUID) (SavedSearch, error)
+	UpdateSavedSearch(search SavedSearch) (SavedSearch, error)
+	DeleteSavedSearch(id int, userID uuid.UUID) error
+	GetMatchingSearches(flat Flat) ([]SavedSearch, error)
+}
+
+// @Description Saved search of a user. Every newly approved flat that matches all set criteria is emailed to the user.
+
+// @Name SavedSearch
+// @Example { "id": 1, "name": "Двушка до 10 млн", "email": "user@example.com", "locale": "ru", "rooms_min": 2, "rooms_max": 2, "price_max": 10000000, "address": "Москва", "created_at": "2024-08-14T09:00:00Z", "updated_at": "2024-08-14T09:00:00Z" }
+type SavedSearch struct {
+	// @Description Unique identifier of the saved search
+	// @Example 1
+	ID int `json:"id"`
+	// @Description Owner of the search
+	UserID uuid.UUID `json:"-"`
+	// @Description Name of the search shown in notifications
+	// @Example "Двушка до 10 млн"
+	Name string `json:"name"`
+	// @Description Email the matches are sent to
+	// @Example "user@example.com"
+	Email string `json:"email"`
+	// @Description Language of the notifications
+	// @Example "ru"
+	Locale string `json:"locale"`
+	// @Description Minimum number of rooms
+	// @Example 2
+	RoomsMin *int `json:"rooms_min,omitempty"`
+	// @Description Maximum number of rooms
+	// @Example 2
+	RoomsMax *int `json:"rooms_max,omitempty"`
+	// @Description Minimum price
+	// @Example 5000000
+	PriceMin *int `json:"price_min,omitempty"`
+	// @Description Maximum price
+	// @Example 10000000
+	PriceMax *int `json:"price_max,omitempty"`
+	// @Description Minimum year the house was built
+	// @Example 2000
+	YearMin *int `json:"year_min,omitempty"`
+	// @Description Maximum year the house was built
+	// @Example 2024
+	YearMax *int `json:"year_max,omitempty"`
+	// @Description Developer of the house, case-insensitive
+	// @Example "Мосстрой"
+	Developer string `json:"developer,omitempty"`
+	// @Description Text the house address must contain, case-insensitive
+	// @Example "Москва"
+	Address string `json:"address,omitempty"`
+	// @Description Date and time when the search was created
+	// @Example "2024-08-14T09:00:00Z"
+	CreatedAt time.Time `json:"created_at"`
+	// @Description Date and time when the search was last changed
+	// @Example "2024-08-14T09:00:00Z"
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// @Description Payload for creating or replacing a saved search. At least one criterion is required.
+
+// @Name SavedSearchPayload
+// @Example { "name": "Двушка до 10 млн", "rooms_min": 2, "rooms_max": 2, "price_max": 10000000, "address": "Москва" }
+type SavedSearchPayload struct {
+	// @Description Name of the search shown in notifications
+	// @Example "Двушка до 10 млн"
+	Name string `json:"name" validate:"required,max=255"`
+	// @Description Language of the notifications, ru by default
+	// @Example "ru"
+	Locale string `json:"locale" validate:"omitempty,oneof=ru en"`
+	// @Description Minimum number of rooms
+	// @Example 2
+	RoomsMin *int `json:"rooms_min" validate:"omitempty,min=1"`
+	// @Description Maximum number of rooms
+	// @Example 2
+	RoomsMax *int `json:"rooms_max" validate:"omitempty,min=1"`
+	// @Description Minimum price
+	// @Example 5000000
+	PriceMin *int `json:"price_min" validate:"omitempty,min=0"`
+	// @Description Maximum price
+	// @Example 10000000
+	PriceMax *int `json:"price_max" validate:"omitempty,min=0"`
+	// @Description Minimum year the house was built
+	// @Example 2000
+	YearMin *int `json:"year_min" validate:"omitempty,min=0"`
+	// @Description Maximum year the house was built
+	// @Example 2024
+	YearMax *int `json:"year_max" validate:"omitempty,min=0"`
+	// @Description Developer of the house, case-insensitive
+	// @Example "Мосстрой"
+	Developer string `json:"developer" validate:"max=255"`
+	// @Description Text the house address must contain, case-insensitive
+	// @Example "Москва"
+	Address string `json:"address" validate:"max=255"`
+}
+
+type FavoriteStore interface {
+	AddFavorite(userID uuid.UUID, flatID int, userRole string) error
+	RemoveFavorite(userID uuid.UUID, flatID int) error
+	GetFavorites(userID uuid.UUID, userRole string) ([]Favorite, error)
+	GetFlatFavorites(flatID int) (FlatFavorites, error)
+}
+
+// @Description Flat bookmarked by a user
+
+// @Name Favorite
+// @Example { "flat": { "id": 1, "house_id": 101, "price": 1200, "rooms": 3, "status": "approved" }, "created_at": "2024-08-15T09:00:00Z" }
+type Favorite struct {
+	// @Description Bookmarked flat
+	Flat Flat `json:"flat"`
+	// @Description Date and time when the flat was bookmarked
+	// @Example "2024-08-15T09:00:00Z"
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// @Description Number of users who bookmarked a flat
+
+// @Name FlatFavorites
+// @Example { "flat_id": 1, "favorites": 12 }
+type FlatFavorites struct {
+	// @Description Unique identifier of the flat
+	// @Example 1
+	FlatID int `json:"flat_id"`
+	// @Description User who created the flat
+	OwnerID *uuid.UUID `json:"-"`
+	// @Description Number of users who bookmarked the flat
+	// @Example 12
+	Favorites int `json:"favorites"`
+}
+
+// @Description Status of a viewing
+const (
+	// ViewingBooked The client is expected at the viewing
+	ViewingBooked string = "booked"
+
+	// ViewingCancelled The client or the owner cancelled the viewing
+	ViewingCancelled string = "cancelled"
+)
+
+type ViewingStore interface {
+	CreateSlot(slot ViewingSlot) (ViewingSlot, error)
+	GetSlots(flatID int, from time.Time) ([]ViewingSlot, error)
+	DeleteSlot(flatID, slotID int) error
+	BookSlot(viewing Viewing) (Viewing, error)
+	GetViewing(id int) (Viewing, error)
+	GetViewings(userID uuid.UUID) ([]Viewing, error)
+	GetFlatViewings(flatID int, from time.Time) ([]Viewing, error)
+	CancelViewing(id int) (Viewing, error)
+	ClaimViewingReminders(from, to time.Time) ([]Viewing, error)
+}
+
+// @Description Time when the owner of a flat can show it. Slots of a flat never overlap.
+
+// @Name ViewingSlot
+// @Example { "id": 1, "flat_id": 10, "starts_at": "2024-08-20T15:00:00Z", "ends_at": "2024-08-20T15:30:00Z", "booked": false, "created_at": "2024-08-16T09:00:00Z" }
+type ViewingSlot struct {
+	// @Description Unique identifier of the slot
+	// @Example 1
+	ID int `json:"id"`
+	// @Description Flat shown during the slot
+	// @Example 10
+	FlatID int `json:"flat_id"`
+	// @Description Start of the slot
+	// @Example "2024-08-20T15:00:00Z"
+	StartsAt time.Time `json:"starts_at"`
+	// @Description End of the slot
+	// @Example "2024-08-20T15:30:00Z"
+	EndsAt time.Time `json:"ends_at"`
+	// @Description Whether a client has booked the slot
+	// @Example false
+	Booked bool `json:"booked"`
+	// @Description Date and time when the slot was added
+	// @Example "2024-08-16T09:00:00Z"
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// @Description Payload for adding a viewing slot
+
+// @Name ViewingSlotPayload
+// @Example { "starts_at": "2024-08-20T15:00:00Z", "ends_at": "2024-08-20T15:30:00Z" }
+type ViewingSlotPayload struct {
+	// @Description Start of the slot, must be in the future
+	// @Example "2024-08-20T15:00:00Z"
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	// @Description End of the slot, at most 4 hours after the start
+	// @Example "2024-08-20T15:30:00Z"
+	EndsAt time.Time `json:"ends_at" validate:"required,gtfield=StartsAt"`
+}
+
+// @Description Viewing of a flat booked by a client
+
+// @Name Viewing
+// @Example { "id": 1, "slot_id": 1, "flat": { "id": 10, "house_id": 1, "price": 12500000, "rooms": 3, "status": "approved" }, "email": "user@example.com", "locale": "ru", "starts_at": "2024-08-20T15:00:00Z", "ends_at": "2024-08-20T15:30:00Z", "status": "booked", "created_at": "2024-08-16T10:00:00Z" }
+type Viewing struct {
+	// @Description Unique identifier of the viewing
+	// @Example 1
+	ID int `json:"id"`
+	// @Description Booked slot
+	// @Example 1
+	SlotID int `json:"slot_id"`
+	// @Description Flat to be shown
+	Flat Flat `json:"flat"`
+	// @Description Client who booked the viewing
+	UserID uuid.UUID `json:"-"`
+	// @Description Email the confirmation and the reminder are sent to
+	// @Example "user@example.com"
+	Email string `json:"email"`
+	// @Description Language of the notifications
+	// @Example "ru"
+	Locale string `json:"locale"`
+	// @Description Start of the viewing
+	// @Example "2024-08-20T15:00:00Z"
+	StartsAt time.Time `json:"starts_at"`
+	// @Description End of the viewing
+	// @Example "2024-08-20T15:30:00Z"
+	EndsAt time.Time `json:"ends_at"`
+	// @Description Status of the viewing
+	// @Example "booked"
+	Status string `json:"status"`
+	// @Description Date and time when the viewing was booked
+	// @Example "2024-08-16T10:00:00Z"
+	CreatedAt time.Time `json:"created_at"`
+	// @Description Date and time when the viewing was cancelled
+	// @Example "2024-08-17T10:00:00Z"
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+}
+
+// @Description Payload for booking a viewing. The email defaults to the one the user registered with.
+
+// @Name BookViewingPayload
+// @Example { "email": "user@example.com", "locale": "ru" }
+type BookViewingPayload struct {
+	// @Description Email the confirmation and the reminder are sent to
+	// @Example "user@example.com"
+	Email string `json:"email" validate:"omitempty,email,max=255"`
+	// @Description Language of the notifications, ru by default
+	// @Example "ru"
+	Locale string `json:"locale" validate:"omitempty,oneof=ru en"`
+}
+
+type MessageStore interface {
+	StartConversation(flatID int, clientID, ownerID uuid.UUID) (int, error)
+	GetConversation(id int, userID uuid.UUID) (Conversation, error)
+	GetConversations(userID uuid.UUID) ([]Conversation, error)
+	GetReportedConversations() ([]Conversation, error)
+	AddMessage(message Message) (Message, error)
+	GetMessages(conversationID int, beforeID int64, limit int) ([]Message, error)
+	MarkRead(conversationID int, readerID uuid.UUID) error
+	ReportConversation(id int, reason string) error
+}
+
+// @Description Conversation about a flat between a client and the owner of the flat
+
+// @Name Conversation
+// @Example { "id": 1, "flat": { "id": 10, "house_id": 1, "price": 12500000, "rooms": 3, "status": "approved" }, "client_id": "a4b4a122-11c1-4b52-bd95-4a5d3c4be616", "owner_id": "b540a379-94ac-4eee-8c4e-83faf2f2d508", "unread": 2, "created_at": "2024-08-17T09:00:00Z", "last_message_at": "2024-08-17T09:30:00Z" }
+type Conversation struct {
+	// @Description Unique identifier of the conversation
+	// @Example 1
+	ID int `json:"id"`
+	// @Description Flat the conversation is about
+	Flat Flat `json:"flat"`
+	// @Description Client who started the conversation
+	// @Example "a4b4a122-11c1-4b52-bd95-4a5d3c4be616"
+	ClientID uuid.UUID `json:"client_id"`
+	// @Description Owner of the flat
+	// @Example "b540a379-94ac-4eee-8c4e-83faf2f2d508"
+	OwnerID uuid.UUID `json:"owner_id"`
+	// @Description Number of messages the current user has not read yet
+	// @Example 2
+	Unread int `json:"unread"`
+	// @Description Date and time when the conversation was started
+	// @Example "2024-08-17T09:00:00Z"
+	CreatedAt time.Time `json:"created_at"`
+	// @Description Date and time of the last message
+	// @Example "2024-08-17T09:30:00Z"
+	LastMessageAt time.Time `json:"last_message_at"`
+	// @Description Date and time when a participant reported abuse
+	// @Example "2024-08-17T10:00:00Z"
+	ReportedAt *time.Time `json:"reported_at,omitempty"`
+	// @Description Reason given in the abuse report
+	// @Example "Spam"
+	ReportReason string `json:"report_reason,omitempty"`
+}
+
+// @Description Message in a conversation
+
+// @Name Message
+// @Example { "id": 42, "conversation_id": 1, "sender_id": "a4b4a122-11c1-4b52-bd95-4a5d3c4be616", "body": "Здравствуйте! Квартира ещё сдаётся?", "created_at": "2024-08-17T09:00:00Z" }
+type Message struct {
+	// @Description Unique identifier of the message, used as the pagination cursor
+	// @Example 42
+	ID int64 `json:"id"`
+	// @Description Conversation the message belongs to
+	// @Example 1
+	ConversationID int `json:"conversation_id"`
+	// @Description Author of the message
+	// @Example "a4b4a122-11c1-4b52-bd95-4a5d3c4be616"
+	SenderID uuid.UUID `json:"sender_id"`
+	// @Description Text of the message
+	// @Example "Здравствуйте! Квартира ещё сдаётся?"
+	Body string `json:"body"`
+	// @Description Date and time when the message was sent
+	// @Example "2024-08-17T09:00:00Z"
+	CreatedAt time.Time `json:"created_at"`
+	// @Description Date and time when the recipient read the message
+	// @Example "2024-08-17T09:05:00Z"
+	ReadAt *time.Time `json:"read_at,omitempty"`
+}
+
+// @Description Payload for sending a message
+
+// @Name MessagePayload
+// @Example { "body": "Здравствуйте! Квартира ещё сдаётся?" }
+type MessagePayload struct {
+	// @Description Text of the message
+	// @Example "Здравствуйте! Квартира ещё сдаётся?"
+	Body string `json:"body" validate:"required,max=4000"`
+}
+
+// @Description Payload for reporting abuse in a conversation
+
+// @Name ReportPayload
+// @Example { "reason": "Spam" }
+type ReportPayload struct {
+	// @Description What is wrong with the conversation
+	// @Example "Spam"
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
+
+// @Description Action recorded in the audit log
+const (
+	// AuditUserRegister A user registered
+	AuditUserRegister string = "user.register"
+
+	// AuditHouseCreate A moderator created a house
+	AuditHouseCreate string = "house.create"
+
+	// AuditHouseArchive A moderator archived a house with its flats
+	AuditHouseArchive string = "house.archive"
+
+	// AuditHouseRestore A moderator restored a house with its flats
+	AuditHouseRestore string = "house.restore"
+
+	// AuditHouseSubscribe A user subscribed to new flats of a house
+	AuditHouseSubscribe string = "house.subscribe"
+
+	// AuditFlatCreate A user created a flat
+	AuditFlatCreate string = "flat.create"
+
+	// AuditFlatModerate A moderator changed the status of a flat
+	AuditFlatModerate string = "flat.moderate"
+
+	// AuditFlatArchive A flat was archived
+	AuditFlatArchive string = "flat.archive"
+
+	// AuditFlatRestore A flat was restored
+	AuditFlatRestore string = "flat.restore"
+
+	// AuditFlatRenew The owner sent an expired flat back to moderation
+	AuditFlatRenew string = "flat.renew"
+
+	// AuditFlatClose The owner marked a flat as rented or sold
+	AuditFlatClose string = "flat.close"
+
+	// AuditFavoriteAdd A user bookmarked a flat
+	AuditFavoriteAdd string = "favorite.add"
+
+	// AuditFavoriteRemove A user removed a flat from favorites
+	AuditFavoriteRemove string = "favorite.remove"
+
+	// AuditSearchCreate A user saved a search
+	AuditSearchCreate string = "search.create"
+
+	// AuditSearchUpdate A user replaced a saved search
+	AuditSearchUpdate string = "search.update"
+
+	// AuditSearchDelete A user deleted a saved search
+	AuditSearchDelete string = "search.delete"
+
+	// AuditSlotCreate The owner of a flat offered a viewing slot
+	AuditSlotCreate string = "viewing_slot.create"
+
+	// AuditSlotDelete The owner of a flat withdrew a viewing slot
+	AuditSlotDelete string = "viewing_slot.delete"
+
+	// AuditViewingBook A client booked a viewing
+	AuditViewingBook string = "viewing.book"
+
+	// AuditViewingCancel A viewing was cancelled
+	AuditViewingCancel string = "viewing.cancel"
+
+	// AuditMessageSend A message was sent, the text is not recorded
+	AuditMessageSend string = "message.send"
+
+	// AuditConversationReport A participant reported a conversation
+	AuditConversationReport string = "conversation.report"
+
+	// AuditWebhookCreate A webhook was registered, the secret is not recorded
+	AuditWebhookCreate string = "webhook.create"
+
+	// AuditWebhookDelete A webhook was deleted
+	AuditWebhookDelete string = "webhook.delete"
+
+	// AuditWebhookEnable A disabled webhook was enabled
+	AuditWebhookEnable string = "webhook.enable"
+
+	// AuditDeveloperCreate A moderator added a developer
+	AuditDeveloperCreate string = "developer.create"
+
+	// AuditDeveloperUpdate A moderator renamed a developer
+	AuditDeveloperUpdate string = "developer.update"
+
+	// AuditDeveloperDelete A moderator deleted a developer without houses
+	AuditDeveloperDelete string = "developer.delete"
+)
+
+type AuditStore interface {
+	AddEntries(entries []AuditEntry) error
+	GetEntries(filter AuditFilter) ([]AuditEntry, error)
+}
+
+// @Description Change made through the API
+
+// @Name AuditEntry
+// @Example { "id": 1001, "actor_id": "b540a379-94ac-4eee-8c4e-83faf2f2d508", "actor_role": "moderator", "action": "flat.moderate", "entity_type": "flat", "entity_id": "10", "before": {"id": 10, "house_id": 1, "price": 12500000, "rooms": 3, "status": "on moderation"}, "after": {"id": 10, "house_id": 1, "price": 12500000, "rooms": 3, "status": "approved"}, "request_id": "8f14e45fceea167a5a36dedd4bea2543", "client_ip": "203.0.113.7", "created_at": "2024-08-19T09:00:00Z" }
+type AuditEntry struct {
+	// @Description Unique identifier of the entry, used as the pagination cursor
+	// @Example 1001
+	ID int64 `json:"id"`
+	// @Description User who made the change, absent for registration
+	// @Example "b540a379-94ac-4eee-8c4e-83faf2f2d508"
+	ActorID *uuid.UUID `json:"actor_id,omitempty"`
+	// @Description Role of the user
+	// @Example "moderator"
+	ActorRole string `json:"actor_role"`
+	// @Description What was done
+	// @Example "flat.moderate"
+	Action string `json:"action"`
+	// @Description Kind of the changed entity
+	// @Example "flat"
+	EntityType string `json:"entity_type"`
+	// @Description Identifier of the changed entity
+	// @Example "10"
+	EntityID string `json:"entity_id"`
+	// @Description Entity before the change, absent for created entities
+	Before json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	// @Description Entity after the change, absent for deleted entities
+	After json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	// @Description Identifier of the request that made the change
+	// @Example "8f14e45fceea167a5a36dedd4bea2543"
+	RequestID string `json:"request_id"`
+	// @Description Address of the client
+	// @Example "203.0.113.7"
+	ClientIP string `json:"client_ip"`
+	// @Description Date and time of the change
+	// @Example "2024-08-19T09:00:00Z"
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// @Description Filters of the audit log, empty fields match everything
+type AuditFilter struct {
+	ActorID    *uuid.UUID
+	Action     string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+	BeforeID   int64
+	Limit      int
+}
+
+type DeveloperStore interface {
+	CreateDeveloper(name string) (Developer, error)
+	GetDevelopers() ([]Developer, error)
+	GetDeveloper(id int) (Developer, error)
+	UpdateDeveloper(id int, name string) (Developer, error)
+	DeleteDeveloper(id int) error
+	GetDeveloperHouses(id int, includeArchived bool) ([]House, error)
+}
+
+// @Description Developer who builds houses
+
+// @Name Developer
+// @Example { "id": 3, "name": "ПИК", "created_at": "2024-08-20T09:00:00Z", "updated_at": "2024-08-20T09:00:00Z" }
+type Developer struct {
+	// @Description Unique identifier of the developer
+	// @Example 3
+	ID int `json:"id"`
+	// @Description Name of the developer, unique regardless of case
+	// @Example "ПИК"
+	Name string `json:"name"`
+	// @Description Date and time when the developer was added
+	// @Example "2024-08-20T09:00:00Z"
+	CreatedAt time.Time `json:"created_at"`
+	// @Description Date and time when the developer was last renamed
+	// @Example "2024-08-20T09:00:00Z"
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// @Description Payload for creating or renaming a developer
+
+// @Name DeveloperPayload
+// @Example { "name": "ПИК" }
+type DeveloperPayload struct {
+	// @Description Name of the developer
+	// @Example "ПИК"
+	Name string `json:"name" validate:"required,max=255"`
 }
@@ -0,0 +1,131 @@
+// Package moderation checks new flats against configurable rules before a
+// moderator sees them.
+package moderation
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/delapaska/avito-rent/configs"
+	"github.com/delapaska/avito-rent/models"
+)
+
+// Rules are the checks a new flat goes through. The zero value checks
+// nothing.
+type Rules struct {
+	// PriceMinRatio and PriceMaxRatio bound the price relative to the median
+	// price of the approved flats of the house with the same listing type and
+	// rent period. The rule is skipped for houses with fewer than
+	// MinComparables such flats.
+	PriceMinRatio  float64
+	PriceMaxRatio  float64
+	MinComparables int
+	PriceAction    string
+
+	RoomsMin    int
+	RoomsMax    int
+	RoomsAction string
+
+	// BannedWords are matched ignoring case anywhere in the description.
+	BannedWords       []string
+	BannedWordsAction string
+}
+
+// FromConfig returns the rules of cfg. Unknown actions fall back to pass, so
+// a typo in the configuration never declines flats.
+func FromConfig(cfg configs.Moderation) Rules {
+	return Rules{
+		PriceMinRatio:     cfg.PriceMinRatio,
+		PriceMaxRatio:     cfg.PriceMaxRatio,
+		MinComparables:    cfg.MinComparables,
+		PriceAction:       parseAction("price", cfg.PriceAction),
+		RoomsMin:          cfg.RoomsMin,
+		RoomsMax:          cfg.RoomsMax,
+		RoomsAction:       parseAction("rooms", cfg.RoomsAction),
+		BannedWords:       cfg.BannedWords,
+		BannedWordsAction: parseAction("banned_words", cfg.BannedWordsAction),
+	}
+}
+
+func parseAction(rule, action string) string {
+	switch action {
+	case models.RuleActionDecline, models.RuleActionHighRisk, models.RuleActionPass:
+		return action
+	}
+	log.Printf("Unknown moderation action %q for rule %s, using pass\n", action, rule)
+	return models.RuleActionPass
+}
+
+// ChecksPrice reports whether Check needs the median price of the house.
+func (r Rules) ChecksPrice() bool {
+	return r.PriceMinRatio > 0 || r.PriceMaxRatio > 0
+}
+
+// Verdict is the outcome of Check. Action is the strictest action of the
+// hits, pass when there are none.
+type Verdict struct {
+	Action string
+	Hits   models.RuleHits
+}
+
+// Reason joins the reasons of the hits that decline the flat.
+func (v Verdict) Reason() string {
+	var reasons []string
+	for _, hit := range v.Hits {
+		if hit.Action == models.RuleActionDecline {
+			reasons = append(reasons, hit.Reason)
+		}
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// Check runs the rules against flat. median is the median price of the
+// comparable flats of the house and comparables their number.
+func (r Rules) Check(flat models.Flat, median float64, comparables int) Verdict {
+	v := Verdict{Action: models.RuleActionPass}
+	hit := func(rule, action, reason string) {
+		v.Hits = append(v.Hits, models.RuleHit{Rule: rule, Action: action, Reason: reason})
+		if severity(action) > severity(v.Action) {
+			v.Action = action
+		}
+	}
+
+	if r.ChecksPrice() && comparables >= max(r.MinComparables, 1) && median > 0 {
+		price := float64(flat.Price)
+		if r.PriceMinRatio > 0 && price < median*r.PriceMinRatio {
+			hit("price", r.PriceAction, fmt.Sprintf("price %d is below %g%% of the median price %.0f of the house",
+				flat.Price, r.PriceMinRatio*100, median))
+		}
+		if r.PriceMaxRatio > 0 && price > median*r.PriceMaxRatio {
+			hit("price", r.PriceAction, fmt.Sprintf("price %d is above %g%% of the median price %.0f of the house",
+				flat.Price, r.PriceMaxRatio*100, median))
+		}
+	}
+
+	if r.RoomsMin > 0 && flat.Rooms < r.RoomsMin {
+		hit("rooms", r.RoomsAction, fmt.Sprintf("%d rooms, at least %d required", flat.Rooms, r.RoomsMin))
+	}
+	if r.RoomsMax > 0 && flat.Rooms > r.RoomsMax {
+		hit("rooms", r.RoomsAction, fmt.Sprintf("%d rooms, at most %d allowed", flat.Rooms, r.RoomsMax))
+	}
+
+	description := strings.ToLower(flat.Description)
+	for _, word := range r.BannedWords {
+		if word != "" && strings.Contains(description, strings.ToLower(word)) {
+			hit("banned_words", r.BannedWordsAction, fmt.Sprintf("description contains %q", word))
+		}
+	}
+
+	return v
+}
+
+func severity(action string) int {
+	switch action {
+	case models.RuleActionDecline:
+		return 2
+	case models.RuleActionHighRisk:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,69 @@
+package moderation
+
+import (
+	"testing"
+
+	"github.com/delapaska/avito-rent/configs"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	rules := FromConfig(configs.Moderation{
+		PriceMinRatio:     0.1,
+		PriceMaxRatio:     10,
+		MinComparables:    3,
+		PriceAction:       models.RuleActionDecline,
+		RoomsMin:          1,
+		RoomsMax:          10,
+		RoomsAction:       models.RuleActionDecline,
+		BannedWords:       []string{"предоплата"},
+		BannedWordsAction: models.RuleActionHighRisk,
+	})
+	flat := models.Flat{Price: 12000000, Rooms: 3, Description: "Светлая квартира"}
+
+	t.Run("should pass a regular flat", func(t *testing.T) {
+		v := rules.Check(flat, 12500000, 5)
+
+		assert.Equal(t, models.RuleActionPass, v.Action)
+		assert.Empty(t, v.Hits)
+	})
+
+	t.Run("should decline an obviously wrong price and room count", func(t *testing.T) {
+		bad := flat
+		bad.Price, bad.Rooms = 1, 50
+
+		v := rules.Check(bad, 12500000, 5)
+
+		assert.Equal(t, models.RuleActionDecline, v.Action)
+		assert.Len(t, v.Hits, 2)
+		assert.Equal(t, "price 1 is below 10% of the median price 12500000 of the house; 50 rooms, at most 10 allowed", v.Reason())
+	})
+
+	t.Run("should skip the price rule without enough comparable flats", func(t *testing.T) {
+		bad := flat
+		bad.Price = 1
+
+		v := rules.Check(bad, 12500000, 2)
+
+		assert.Equal(t, models.RuleActionPass, v.Action)
+	})
+
+	t.Run("should mark banned words as high risk", func(t *testing.T) {
+		risky := flat
+		risky.Description = "Только ПРЕДОПЛАТА на карту"
+
+		v := rules.Check(risky, 12500000, 5)
+
+		assert.Equal(t, models.RuleActionHighRisk, v.Action)
+		assert.Equal(t, models.RuleHits{{Rule: "banned_words", Action: models.RuleActionHighRisk, Reason: `description contains "предоплата"`}}, v.Hits)
+		assert.Empty(t, v.Reason())
+	})
+
+	t.Run("should only record hits of rules that pass", func(t *testing.T) {
+		v := FromConfig(configs.Moderation{RoomsMax: 2, RoomsAction: "typo"}).Check(flat, 0, 0)
+
+		assert.Equal(t, models.RuleActionPass, v.Action)
+		assert.Len(t, v.Hits, 1)
+	})
+}
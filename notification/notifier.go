@@ -0,0 +1,304 @@
+package notification
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/sender"
+)
+
+const (
+	digestCheckInterval   = 10 * time.Minute
+	reminderCheckInterval = time.Minute
+
+	// digestClaimLease is how long a claimed digest waits for its sender
+	// before another instance may send it.
+	digestClaimLease = 10 * time.Minute
+
+	// digestCatchUp is how many days back missed digests are still sent.
+	digestCatchUp = 7
+
+	// reminderLead is how long before a viewing the reminder is sent.
+	reminderLead = 24 * time.Hour
+)
+
+// Notifier emails house subscribers and clients with booked viewings. Its
+// methods block while sending, so handlers call them in a goroutine.
+type Notifier struct {
+	houses   models.HouseStore
+	searches models.SavedSearchStore
+	viewings models.ViewingStore
+	sender   *sender.Sender
+}
+
+func NewNotifier(houses models.HouseStore, searches models.SavedSearchStore, viewings models.ViewingStore) *Notifier {
+	return &Notifier{houses: houses, searches: searches, viewings: viewings, sender: sender.New()}
+}
+
+// NotifySubscribed confirms a new subscription to the house.
+func (n *Notifier) NotifySubscribed(subscription models.Subscription) {
+	id, err := strconv.Atoi(subscription.HouseID)
+	if err != nil {
+		log.Printf("Not notifying %s: invalid house id %q\n", subscription.Email, subscription.HouseID)
+		return
+	}
+	house, ok := n.house(id)
+	if !ok {
+		return
+	}
+
+	n.send(subscription.Email, subscription.Locale, EventSubscriptionCreated, Data{
+		Email:    subscription.Email,
+		Delivery: subscription.Delivery,
+		House:    house,
+	})
+}
+
+// NotifyFlatApproved tells every instant subscriber of the house and every
+// user with a matching saved search about a newly approved flat. Daily
+// subscribers get it in the next digest.
+func (n *Notifier) NotifyFlatApproved(flat models.Flat) {
+	house, ok := n.house(flat.House_id)
+	if !ok {
+		return
+	}
+
+	subscribers, err := n.houses.GetHouseSubscribers(flat.House_id)
+	if err != nil {
+		log.Printf("Failed to get subscribers of house %d: %v\n", flat.House_id, err)
+	}
+	for _, sub := range subscribers {
+		if sub.Delivery == models.DeliveryDaily {
+			continue
+		}
+		n.send(sub.Email, sub.Locale, models.EventFlatApproved, Data{Email: sub.Email, House: house, Flat: flat})
+	}
+
+	searches, err := n.searches.GetMatchingSearches(flat)
+	if err != nil {
+		log.Printf("Failed to match flat %d against saved searches: %v\n", flat.Id, err)
+		return
+	}
+	// An email gets one message per flat even when several of its searches match.
+	notified := make(map[string]bool)
+	for _, search := range searches {
+		if notified[search.Email] {
+			continue
+		}
+		notified[search.Email] = true
+		n.send(search.Email, search.Locale, EventSearchMatch, Data{Email: search.Email, House: house, Flat: flat, Search: search})
+	}
+}
+
+// RunDigests sends the digests of every day up to yesterday (UTC) that have
+// not all been delivered, once the clock passes hour. Every digest email is
+// claimed in the database, so running it on every instance sends it once,
+// and a digest that failed to send is sent on the next run.
+func (n *Notifier) RunDigests(ctx context.Context, hour int) {
+	ticker := time.NewTicker(digestCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now().UTC()
+		if now.Hour() >= hour {
+			n.sendDueDigests(now)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (n *Notifier) sendDueDigests(now time.Time) {
+	last, err := n.houses.GetLastDigestDay()
+	if err != nil {
+		log.Printf("Failed to get the last daily digest: %v\n", err)
+		return
+	}
+	for _, day := range digestDays(last, now) {
+		if err := n.SendDigest(day); err != nil {
+			log.Printf("Failed to send daily digest of %s: %v\n", day.Format("2006-01-02"), err)
+			return
+		}
+	}
+}
+
+// digestDays returns the days after last up to the day before now, at most
+// the digestCatchUp latest of them. Before the first digest only yesterday is
+// due.
+func digestDays(last *time.Time, now time.Time) []time.Time {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	first := today.AddDate(0, 0, -1)
+	if last != nil {
+		first = last.AddDate(0, 0, 1)
+	}
+	if earliest := today.AddDate(0, 0, -digestCatchUp); first.Before(earliest) {
+		first = earliest
+	}
+
+	var days []time.Time
+	for day := first; day.Before(today); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days
+}
+
+// SendDigest sends every daily subscriber one email with the flats approved
+// during day in the houses they follow. The day is recorded as delivered once
+// every digest of it is sent; a failed send releases its claim, so the next
+// run sends it again.
+func (n *Notifier) SendDigest(day time.Time) error {
+	entries, err := n.houses.GetDigestEntries(day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return err
+	}
+
+	digests := buildDigests(day, entries)
+	for _, d := range digests {
+		claimed, err := n.houses.ClaimDigest(day, d.data.Email, digestClaimLease)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := n.send(d.data.Email, d.locale, EventDailyDigest, d.data); err != nil {
+			if err := n.houses.ReleaseDigest(day, d.data.Email); err != nil {
+				log.Printf("Failed to release the digest for %s: %v\n", d.data.Email, err)
+			}
+			continue
+		}
+		if err := n.houses.MarkDigestSent(day, d.data.Email); err != nil {
+			return err
+		}
+	}
+
+	return n.houses.CompleteDigest(day, len(digests))
+}
+
+// NotifyViewingBooked confirms a booked viewing to the client.
+func (n *Notifier) NotifyViewingBooked(viewing models.Viewing) {
+	n.sendViewing(EventViewingBooked, viewing)
+}
+
+// NotifyViewingCancelled tells the client that a viewing was cancelled.
+func (n *Notifier) NotifyViewingCancelled(viewing models.Viewing) {
+	n.sendViewing(EventViewingCancelled, viewing)
+}
+
+// NotifyMessageReceived tells the recipient of a message about it. Users have
+// no language setting, so the email is in Russian.
+func (n *Notifier) NotifyMessageReceived(email string, conversation models.Conversation, message models.Message) {
+	house, ok := n.house(conversation.Flat.House_id)
+	if !ok {
+		return
+	}
+	n.send(email, models.LocaleRU, EventMessageReceived, Data{Email: email, House: house, Flat: conversation.Flat, Message: message})
+}
+
+// NotifyFlatExpiring reminds the owner that the listing of an approved flat
+// is about to expire. Users have no language setting, so the email is in
+// Russian.
+func (n *Notifier) NotifyFlatExpiring(email string, flat models.Flat) {
+	house, ok := n.house(flat.House_id)
+	if !ok {
+		return
+	}
+	n.send(email, models.LocaleRU, EventFlatExpiring, Data{Email: email, House: house, Flat: flat})
+}
+
+// RunViewingReminders reminds clients about viewings starting within a day.
+// Reminders are claimed in the database, so running it on every instance
+// sends each of them once.
+func (n *Notifier) RunViewingReminders(ctx context.Context) {
+	ticker := time.NewTicker(reminderCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now().UTC()
+		viewings, err := n.viewings.ClaimViewingReminders(now, now.Add(reminderLead))
+		if err != nil {
+			log.Printf("Failed to claim viewing reminders: %v\n", err)
+		}
+		for _, viewing := range viewings {
+			n.sendViewing(EventViewingReminder, viewing)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (n *Notifier) sendViewing(event string, viewing models.Viewing) {
+	house, ok := n.house(viewing.Flat.House_id)
+	if !ok {
+		return
+	}
+	n.send(viewing.Email, viewing.Locale, event, Data{Email: viewing.Email, House: house, Flat: viewing.Flat, Viewing: viewing})
+}
+
+type digest struct {
+	locale string
+	data   Data
+}
+
+// buildDigests groups entries ordered by email and house into one digest per
+// email.
+func buildDigests(day time.Time, entries []models.DigestEntry) []digest {
+	var digests []digest
+	for _, e := range entries {
+		if len(digests) == 0 || digests[len(digests)-1].data.Email != e.Email {
+			digests = append(digests, digest{
+				locale: e.Locale,
+				data:   Data{Email: e.Email, Delivery: models.DeliveryDaily, Date: day},
+			})
+		}
+		data := &digests[len(digests)-1].data
+
+		if len(data.Houses) == 0 || data.Houses[len(data.Houses)-1].House.Id != e.House.Id {
+			data.Houses = append(data.Houses, DigestHouse{House: e.House})
+		}
+		house := &data.Houses[len(data.Houses)-1]
+		house.Flats = append(house.Flats, e.Flat)
+	}
+	return digests
+}
+
+func (n *Notifier) house(id int) (models.House, bool) {
+	houses, err := n.houses.GetHousesByIDs([]int{id})
+	if err != nil {
+		log.Printf("Failed to get house %d: %v\n", id, err)
+		return models.House{}, false
+	}
+	if len(houses) == 0 {
+		log.Printf("Not notifying about house %d: house not found\n", id)
+		return models.House{}, false
+	}
+	return houses[0], true
+}
+
+// send renders and sends the email. Failures are logged, so callers that do
+// not retry can ignore the error.
+func (n *Notifier) send(email, locale, event string, data Data) error {
+	message, err := Render(locale, event, data)
+	if err != nil {
+		log.Printf("Failed to render %s notification: %v\n", event, err)
+		return err
+	}
+
+	if err := n.sender.SendMessage(context.Background(), email, message); err != nil {
+		log.Printf("Failed to send email to %s: %v\n", email, err)
+		return err
+	}
+	return nil
+}
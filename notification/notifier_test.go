@@ -0,0 +1,45 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/delapaska/avito-rent/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildDigests(t *testing.T) {
+	day := time.Date(2024, 8, 12, 0, 0, 0, 0, time.UTC)
+	first := models.House{Id: 1, Address: "Лесная улица, 7"}
+	second := models.House{Id: 2, Address: "Тверская улица, 1"}
+	entry := func(email, locale string, house models.House, flatID int) models.DigestEntry {
+		return models.DigestEntry{Email: email, Locale: locale, House: house, Flat: models.Flat{Id: flatID, House_id: house.Id}}
+	}
+
+	digests := buildDigests(day, []models.DigestEntry{
+		entry("a@example.com", models.LocaleEN, first, 10),
+		entry("a@example.com", models.LocaleEN, first, 11),
+		entry("a@example.com", models.LocaleEN, second, 20),
+		entry("b@example.com", models.LocaleRU, second, 20),
+	})
+
+	assert.Len(t, digests, 2)
+	assert.Equal(t, models.LocaleEN, digests[0].locale)
+	assert.Equal(t, day, digests[0].data.Date)
+	assert.Len(t, digests[0].data.Houses, 2)
+	assert.Equal(t, []models.Flat{{Id: 10, House_id: 1}, {Id: 11, House_id: 1}}, digests[0].data.Houses[0].Flats)
+	assert.Equal(t, "b@example.com", digests[1].data.Email)
+	assert.Equal(t, []DigestHouse{{House: second, Flats: []models.Flat{{Id: 20, House_id: 2}}}}, digests[1].data.Houses)
+	assert.Empty(t, buildDigests(day, nil))
+}
+
+func TestDigestDays(t *testing.T) {
+	now := time.Date(2024, 8, 12, 9, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2024, 8, d, 0, 0, 0, 0, time.UTC) }
+	last := func(d int) *time.Time { t := day(d); return &t }
+
+	assert.Equal(t, []time.Time{day(11)}, digestDays(nil, now))
+	assert.Equal(t, []time.Time{day(9), day(10), day(11)}, digestDays(last(8), now))
+	assert.Empty(t, digestDays(last(11), now))
+	assert.Equal(t, []time.Time{day(5), day(6), day(7), day(8), day(9), day(10), day(11)}, digestDays(last(1), now))
+}
@@ -0,0 +1,167 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/sender"
+)
+
+const (
+	// EventSubscriptionCreated is sent right after a subscription is saved.
+	EventSubscriptionCreated = "subscription_created"
+
+	// EventDailyDigest lists the flats approved during a day.
+	EventDailyDigest = "daily_digest"
+
+	// EventSearchMatch is sent when an approved flat matches a saved search.
+	EventSearchMatch = "search_match"
+
+	// EventViewingBooked confirms a booked viewing.
+	EventViewingBooked = "viewing_booked"
+
+	// EventViewingReminder is sent the day before a viewing.
+	EventViewingReminder = "viewing_reminder"
+
+	// EventViewingCancelled is sent when the client or the owner cancels a viewing.
+	EventViewingCancelled = "viewing_cancelled"
+
+	// EventMessageReceived is sent when a conversation gets its first unread message.
+	EventMessageReceived = "message_received"
+
+	// EventFlatExpiring is sent to the owner some days before the listing of a flat expires.
+	EventFlatExpiring = "flat_expiring"
+)
+
+var (
+	locales = []string{models.LocaleRU, models.LocaleEN}
+	events  = []string{EventSubscriptionCreated, models.EventFlatApproved, EventDailyDigest, EventSearchMatch,
+		EventViewingBooked, EventViewingReminder, EventViewingCancelled, EventMessageReceived, EventFlatExpiring}
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+// Data is what the templates are rendered with. Flat is empty for events
+// about the house itself; Date and Houses are only set for digests, Search
+// for search matches, Viewing for viewings and Message for messages.
+type Data struct {
+	Email    string
+	Delivery string
+	House    models.House
+	Flat     models.Flat
+	Date     time.Time
+	Houses   []DigestHouse
+	Search   models.SavedSearch
+	Viewing  models.Viewing
+	Message  models.Message
+}
+
+// DigestHouse groups the flats of a digest by house.
+type DigestHouse struct {
+	House models.House
+	Flats []models.Flat
+}
+
+type templateSet struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+var funcs = map[string]any{
+	"price":  formatPrice,
+	"plural": plural,
+}
+
+// templates holds a set per "<locale>/<event>". Every locale must have a
+// template for every event; a missing one fails at startup.
+var templates = loadTemplates()
+
+func loadTemplates() map[string]templateSet {
+	sets := make(map[string]templateSet)
+	for _, locale := range locales {
+		for _, event := range events {
+			name := locale + "/" + event
+			text, err := texttemplate.New(event+".txt.tmpl").Funcs(funcs).
+				ParseFS(templatesFS, "templates/"+name+".txt.tmpl")
+			if err != nil {
+				panic(err)
+			}
+			if text.Lookup("subject") == nil {
+				panic(fmt.Sprintf("notification template %s has no subject", name))
+			}
+			html, err := htmltemplate.New(event+".html.tmpl").Funcs(funcs).
+				ParseFS(templatesFS, "templates/"+name+".html.tmpl")
+			if err != nil {
+				panic(err)
+			}
+			sets[name] = templateSet{text: text, html: html}
+		}
+	}
+	return sets
+}
+
+// Render builds the message for event in the given locale, falling back to
+// Russian for unknown locales.
+func Render(locale, event string, data Data) (sender.Message, error) {
+	set, ok := templates[locale+"/"+event]
+	if !ok {
+		set, ok = templates[models.LocaleRU+"/"+event]
+	}
+	if !ok {
+		return sender.Message{}, fmt.Errorf("no template for event %s", event)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := set.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return sender.Message{}, err
+	}
+	if err := set.text.Execute(&text, data); err != nil {
+		return sender.Message{}, err
+	}
+	if err := set.html.Execute(&html, data); err != nil {
+		return sender.Message{}, err
+	}
+
+	return sender.Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+// formatPrice groups digits by three: 12500000 becomes "12 500 000".
+func formatPrice(price int) string {
+	s := strconv.Itoa(price)
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && (len(s)-i)%3 == 0 && s[i-1] != '-' {
+			b.WriteRune(' ')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// plural picks the Russian plural form for n: 1 комната, 2 комнаты, 5 комнат.
+func plural(n int, one, few, many string) string {
+	n %= 100
+	if n >= 11 && n <= 14 {
+		return many
+	}
+	switch n % 10 {
+	case 1:
+		return one
+	case 2, 3, 4:
+		return few
+	default:
+		return many
+	}
+}
@@ -0,0 +1,129 @@
+package notification
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/delapaska/avito-rent/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	house := models.House{Id: 1, Address: "Лесная улица, 7, Москва", Year: 2003, Developer: "<Мосстрой>"}
+	flat := models.Flat{Id: 10, House_id: 1, Price: 12500000, Rooms: 3, Status: models.StatusApproved}
+	data := Data{
+		Email:  "user@example.com",
+		House:  house,
+		Flat:   flat,
+		Date:   time.Date(2024, 8, 12, 0, 0, 0, 0, time.UTC),
+		Houses: []DigestHouse{{House: house, Flats: []models.Flat{flat}}},
+		Viewing: models.Viewing{
+			Flat:     flat,
+			StartsAt: time.Date(2024, 8, 20, 15, 0, 0, 0, time.UTC),
+			EndsAt:   time.Date(2024, 8, 20, 15, 30, 0, 0, time.UTC),
+		},
+	}
+
+	t.Run("should render every event in every locale", func(t *testing.T) {
+		for _, locale := range locales {
+			for _, event := range events {
+				message, err := Render(locale, event, data)
+				assert.NoError(t, err)
+				assert.NotEmpty(t, message.Subject)
+				assert.Contains(t, message.Text, data.House.Address)
+				assert.Contains(t, message.HTML, data.House.Address)
+			}
+		}
+	})
+
+	t.Run("should interpolate flat details in russian", func(t *testing.T) {
+		message, err := Render(models.LocaleRU, models.EventFlatApproved, data)
+		assert.NoError(t, err)
+		assert.Equal(t, "Новая квартира в доме Лесная улица, 7, Москва", message.Subject)
+		assert.Contains(t, message.Text, "3 комнаты")
+		assert.Contains(t, message.Text, "12 500 000 ₽")
+	})
+
+	t.Run("should escape html and fall back to russian for unknown locales", func(t *testing.T) {
+		message, err := Render("de", EventSubscriptionCreated, data)
+		assert.NoError(t, err)
+		assert.Contains(t, message.Text, "от застройщика <Мосстрой>")
+		assert.Contains(t, message.HTML, "от застройщика &lt;Мосстрой&gt;")
+	})
+
+	t.Run("should mention the digest for daily subscribers", func(t *testing.T) {
+		daily := data
+		daily.Delivery = models.DeliveryDaily
+		message, err := Render(models.LocaleRU, EventSubscriptionCreated, daily)
+		assert.NoError(t, err)
+		assert.Contains(t, message.Text, "Раз в день")
+	})
+
+	t.Run("should list digest flats by house", func(t *testing.T) {
+		message, err := Render(models.LocaleRU, EventDailyDigest, data)
+		assert.NoError(t, err)
+		assert.Equal(t, "Новые квартиры за 12.08.2024", message.Subject)
+		assert.Contains(t, message.Text, "Лесная улица, 7, Москва:\n  Квартира №10, 3 комнаты, 12 500 000 ₽\n")
+	})
+
+	t.Run("should show the time of a viewing", func(t *testing.T) {
+		message, err := Render(models.LocaleRU, EventViewingBooked, data)
+		assert.NoError(t, err)
+		assert.Equal(t, "Просмотр квартиры Лесная улица, 7, Москва", message.Subject)
+		assert.Contains(t, message.Text, "Время: 20.08.2024 15:00–15:30 UTC")
+	})
+
+	t.Run("should show when a listing expires", func(t *testing.T) {
+		expiring := data
+		expiresAt := time.Date(2024, 9, 25, 9, 0, 0, 0, time.UTC)
+		expiring.Flat.ExpiresAt = &expiresAt
+		message, err := Render(models.LocaleRU, EventFlatExpiring, expiring)
+		assert.NoError(t, err)
+		assert.Equal(t, "Объявление о квартире Лесная улица, 7, Москва скоро снимется с публикации", message.Subject)
+		assert.Contains(t, message.Text, "Публикуется до: 25.09.2024 09:00 UTC")
+		assert.Contains(t, message.Text, "POST /flat/10/renew")
+	})
+
+	t.Run("should build a multipart/alternative email", func(t *testing.T) {
+		message, err := Render(models.LocaleEN, models.EventFlatApproved, data)
+		assert.NoError(t, err)
+
+		raw, err := message.MIME(data.Email)
+		assert.NoError(t, err)
+		m, err := mail.ReadMessage(strings.NewReader(string(raw)))
+		assert.NoError(t, err)
+
+		subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+		assert.NoError(t, err)
+		assert.Equal(t, message.Subject, subject)
+
+		mediaType, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+		assert.NoError(t, err)
+		assert.Equal(t, "multipart/alternative", mediaType)
+
+		r := multipart.NewReader(m.Body, params["boundary"])
+		var types []string
+		for {
+			part, err := r.NextPart()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err)
+			body, _ := io.ReadAll(part)
+			types = append(types, part.Header.Get("Content-Type"))
+			assert.Contains(t, string(body), "12 500 000 RUB")
+		}
+		assert.Equal(t, []string{"text/plain; charset=UTF-8", "text/html; charset=UTF-8"}, types)
+	})
+}
+
+func TestPlural(t *testing.T) {
+	for n, want := range map[int]string{1: "комната", 2: "комнаты", 5: "комнат", 11: "комнат", 21: "комната", 104: "комнаты"} {
+		assert.Equal(t, want, plural(n, "комната", "комнаты", "комнат"))
+	}
+}
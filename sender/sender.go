@@ -1,10 +1,15 @@
 package sender
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"math/rand"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
 	"time"
 )
 
@@ -14,6 +19,55 @@ func New() *Sender {
 	return &Sender{}
 }
 
+// Message is an email with plain text and HTML versions of the same body.
+type Message struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// MIME renders the message as a multipart/alternative email, so mail
+// clients that cannot show HTML fall back to the text part.
+func (m Message) MIME(recipient string) ([]byte, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", m.Text},
+		{"text/html; charset=UTF-8", m.HTML},
+	}
+	for _, p := range parts {
+		pw, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := qw.Write([]byte(p.content)); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "To: %s\r\n", recipient)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", m.Subject))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", w.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
 func (s *Sender) SendEmail(ctx context.Context, recipient string, message string) error {
 
 	duration := time.Duration(rand.Int63n(3000)) * time.Millisecond
@@ -28,3 +82,11 @@ func (s *Sender) SendEmail(ctx context.Context, recipient string, message string
 
 	return nil
 }
+
+func (s *Sender) SendMessage(ctx context.Context, recipient string, message Message) error {
+	raw, err := message.MIME(recipient)
+	if err != nil {
+		return err
+	}
+	return s.SendEmail(ctx, recipient, string(raw))
+}
@@ -0,0 +1,136 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditMiddleware(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	userID := uuid.New()
+	r := gin.Default()
+	r.Use(middleware.Audit(NewStore(db)))
+	setUser := func(c *gin.Context) {
+		c.Set("RequestId", "req-1")
+		c.Set("userID", userID)
+		c.Set("userType", "moderator")
+	}
+	r.POST("/flat/:id/moderate", setUser, func(c *gin.Context) {
+		before := models.Flat{Id: 1, Status: models.StatusOnModeration}
+		after := models.Flat{Id: 1, Status: models.StatusApproved}
+		middleware.RecordAudit(c.Request.Context(), models.AuditFlatModerate, "flat", 1, before, after)
+		c.Status(http.StatusOK)
+	})
+	r.POST("/flat/:id/noop", setUser, func(c *gin.Context) {
+		c.Status(http.StatusBadRequest)
+	})
+
+	t.Run("should save recorded changes with the actor and request", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO audit_log \(actor_id, actor_role, action, entity_type, entity_id, before, after, request_id, client_ip, created_at\)`).
+			WithArgs(&userID, "moderator", models.AuditFlatModerate, "flat", "1",
+				`{"id":1,"house_id":0,"price":0,"rooms":0,"status":"on moderation"}`,
+				`{"id":1,"house_id":0,"price":0,"rooms":0,"status":"approved"}`,
+				"req-1", "192.0.2.1", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, httptest.NewRequest("POST", "/flat/1/moderate", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should not write anything when nothing was recorded", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, httptest.NewRequest("POST", "/flat/1/noop", nil))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleGetAudit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	r := gin.Default()
+	r.GET("/audit", NewHandler(NewStore(db)).handleGetAudit)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, httptest.NewRequest("GET", path, nil))
+		return recorder
+	}
+
+	columns := []string{"id", "actor_id", "actor_role", "action", "entity_type", "entity_id", "before", "after", "request_id", "client_ip", "created_at"}
+	actorID := uuid.New()
+	createdAt := time.Date(2024, 8, 19, 9, 0, 0, 0, time.UTC)
+
+	t.Run("should pass filters to the query", func(t *testing.T) {
+		from := time.Date(2024, 8, 19, 0, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`SELECT id, actor_id, actor_role, action, entity_type, entity_id, before, after, request_id, client_ip, created_at FROM audit_log`).
+			WithArgs(&actorID, "flat.moderate", "flat", "10", &from, nil, int64(1002), 20).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1001, actorID, "moderator", "flat.moderate", "flat", "10",
+					[]byte(`{"status": "on moderation"}`), []byte(`{"status": "approved"}`), "req-1", "203.0.113.7", createdAt))
+
+		recorder := serve("/audit?actor_id=" + actorID.String() + "&action=flat.moderate&entity_type=flat&entity_id=10&from=2024-08-19T03:00:00%2B03:00&limit=20&before=1002")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var response struct {
+			Entries []models.AuditEntry `json:"entries"`
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("error decoding response: %v", err)
+		}
+		if assert.Len(t, response.Entries, 1) {
+			entry := response.Entries[0]
+			assert.Equal(t, int64(1001), entry.ID)
+			assert.Equal(t, &actorID, entry.ActorID)
+			assert.JSONEq(t, `{"status": "approved"}`, string(entry.After))
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return entries without an actor and change", func(t *testing.T) {
+		mock.ExpectQuery(`FROM audit_log`).
+			WithArgs(nil, "", "", "", nil, nil, int64(0), 100).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, nil, "", "user.register", "user", actorID.String(), nil, []byte(`{"email": "user@example.com"}`), "req-2", "203.0.113.8", createdAt))
+
+		recorder := serve("/audit")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.NotContains(t, recorder.Body.String(), "actor_id")
+		assert.NotContains(t, recorder.Body.String(), "before")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject invalid filters", func(t *testing.T) {
+		for _, query := range []string{"actor_id=42", "from=yesterday", "limit=0", "limit=501", "before=-1"} {
+			recorder := serve("/audit?" + query)
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, query)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
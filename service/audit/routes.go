@@ -0,0 +1,118 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultEntryLimit = 100
+	maxEntryLimit     = 500
+)
+
+type Handler struct {
+	store models.AuditStore
+}
+
+func NewHandler(store models.AuditStore) *Handler {
+	return &Handler{store: store}
+}
+
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+
+	moderationsOnly := router.Group("/")
+	moderationsOnly.Use(middleware.AuthMiddleware("moderator"))
+	{
+		moderationsOnly.GET("/audit", h.handleGetAudit)
+	}
+}
+
+// queryTime parses an RFC 3339 query parameter, writing 400 when it is invalid.
+func queryTime(c *gin.Context, name string) (*time.Time, bool) {
+	s := c.Query(name)
+	if s == "" {
+		return nil, true
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		utils.WriteError(c, http.StatusBadRequest, name+" must be an RFC 3339 date and time")
+		return nil, false
+	}
+	t = t.UTC()
+	return &t, true
+}
+
+// @Summary Get Audit Log
+// @Description Changes made through the API, newest first. Every filter is optional. Pass the id of the oldest received entry as before to get the previous page. Requires moderator access.
+// @Tags Audit
+// @Produce json
+// @Security Bearer
+// @Param actor_id query string false "User who made the change"
+// @Param action query string false "Action, for example flat.moderate"
+// @Param entity_type query string false "Kind of the changed entity, for example flat"
+// @Param entity_id query string false "Identifier of the changed entity"
+// @Param from query string false "Only return changes made at or after this time, RFC 3339"
+// @Param to query string false "Only return changes made before this time, RFC 3339"
+// @Param limit query int false "Maximum number of entries, 100 by default and at most 500"
+// @Param before query int false "Only return entries older than this entry id"
+// @Success 200 {object} utils.AuditResponse "Audit log retrieved"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /audit [get]
+func (h *Handler) handleGetAudit(c *gin.Context) {
+	filter := models.AuditFilter{
+		Action:     c.Query("action"),
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+		Limit:      defaultEntryLimit,
+	}
+
+	if s := c.Query("actor_id"); s != "" {
+		actorID, err := uuid.Parse(s)
+		if err != nil {
+			utils.WriteError(c, http.StatusBadRequest, "actor_id must be a UUID")
+			return
+		}
+		filter.ActorID = &actorID
+	}
+	var ok bool
+	if filter.From, ok = queryTime(c, "from"); !ok {
+		return
+	}
+	if filter.To, ok = queryTime(c, "to"); !ok {
+		return
+	}
+	if s := c.Query("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 || n > maxEntryLimit {
+			utils.WriteError(c, http.StatusBadRequest, "limit must be between 1 and 500")
+			return
+		}
+		filter.Limit = n
+	}
+	if s := c.Query("before"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n <= 0 {
+			utils.WriteError(c, http.StatusBadRequest, "before must be an entry id")
+			return
+		}
+		filter.BeforeID = n
+	}
+
+	entries, err := h.store.GetEntries(filter)
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(c, http.StatusOK, gin.H{"entries": entries})
+}
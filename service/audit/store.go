@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+
+	"github.com/delapaska/avito-rent/models"
+	"github.com/google/uuid"
+)
+
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+func (s *Store) AddEntries(entries []models.AuditEntry) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v\n", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO audit_log (actor_id, actor_role, action, entity_type, entity_id, before, after, request_id, client_ip, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	for _, e := range entries {
+		_, err := tx.Exec(query, e.ActorID, e.ActorRole, e.Action, e.EntityType, e.EntityID,
+			nullJSON(e.Before), nullJSON(e.After), e.RequestID, e.ClientIP, e.CreatedAt.Format("2006-01-02T15:04:05Z"))
+		if err != nil {
+			log.Printf("Error executing insert query: %v\n", err)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// nullJSON passes JSON as text so that Postgres casts it to JSONB.
+func nullJSON(data json.RawMessage) any {
+	if data == nil {
+		return nil
+	}
+	return string(data)
+}
+
+// GetEntries returns up to filter.Limit matching entries, newest first. A
+// positive filter.BeforeID returns the page of entries older than it.
+func (s *Store) GetEntries(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	query := `
+		SELECT id, actor_id, actor_role, action, entity_type, entity_id, before, after, request_id, client_ip, created_at
+		FROM audit_log
+		WHERE ($1::uuid IS NULL OR actor_id = $1)
+			AND ($2 = '' OR action = $2)
+			AND ($3 = '' OR entity_type = $3)
+			AND ($4 = '' OR entity_id = $4)
+			AND ($5::timestamp IS NULL OR created_at >= $5)
+			AND ($6::timestamp IS NULL OR created_at < $6)
+			AND ($7 = 0 OR id < $7)
+		ORDER BY id DESC
+		LIMIT $8`
+
+	rows, err := s.db.Query(query, filter.ActorID, filter.Action, filter.EntityType, filter.EntityID,
+		filter.From, filter.To, filter.BeforeID, filter.Limit)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		var e models.AuditEntry
+		var actorID uuid.NullUUID
+		var before, after []byte
+		err := rows.Scan(&e.ID, &actorID, &e.ActorRole, &e.Action, &e.EntityType, &e.EntityID,
+			&before, &after, &e.RequestID, &e.ClientIP, &e.CreatedAt)
+		if err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		if actorID.Valid {
+			e.ActorID = &actorID.UUID
+		}
+		e.Before, e.After = before, after
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
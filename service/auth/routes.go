@@ -171,6 +171,11 @@ func (h *Handler) handleRegister(c *gin.Context) {
 		return
 	}
 
+	middleware.RecordAudit(c.Request.Context(), models.AuditUserRegister, "user", newUser.User_id, nil, gin.H{
+		"user_id":  newUser.User_id,
+		"email":    newUser.Email,
+		"userType": newUser.UserType,
+	})
 	utils.WriteJSON(c, http.StatusCreated, gin.H{
 		"user_id": newUser.User_id,
 	})
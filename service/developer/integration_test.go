@@ -0,0 +1,189 @@
+package developer
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var developerColumnNames = []string{"id", "name", "created_at", "updated_at"}
+
+func TestHandleCreateDeveloper(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	r := gin.Default()
+	r.POST("/developers", NewHandler(NewStore(db)).handleCreateDeveloper)
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, httptest.NewRequest("POST", "/developers", bytes.NewBufferString(body)))
+		return recorder
+	}
+
+	createdAt := time.Date(2024, 8, 20, 9, 0, 0, 0, time.UTC)
+
+	t.Run("should create a developer with a trimmed name", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO developers \(name, created_at, updated_at\)`).
+			WithArgs("ПИК", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows(developerColumnNames).AddRow(3, "ПИК", createdAt, createdAt))
+
+		recorder := serve(`{"name": "  ПИК "}`)
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		var developer models.Developer
+		if err := json.Unmarshal(recorder.Body.Bytes(), &developer); err != nil {
+			t.Fatalf("error decoding response: %v", err)
+		}
+		assert.Equal(t, 3, developer.ID)
+		assert.Equal(t, "ПИК", developer.Name)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return 409 when the name is taken", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO developers`).
+			WithArgs("пик", sqlmock.AnyArg()).
+			WillReturnError(&pq.Error{Code: uniqueViolation})
+
+		recorder := serve(`{"name": "пик"}`)
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject an empty name", func(t *testing.T) {
+		recorder := serve(`{"name": "   "}`)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleDeleteDeveloper(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	r := gin.Default()
+	r.DELETE("/developers/:id", NewHandler(NewStore(db)).handleDeleteDeveloper)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, httptest.NewRequest("DELETE", path, nil))
+		return recorder
+	}
+
+	createdAt := time.Date(2024, 8, 20, 9, 0, 0, 0, time.UTC)
+
+	t.Run("should delete a developer without houses", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id, name, created_at, updated_at FROM developers WHERE id = \$1`).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows(developerColumnNames).AddRow(3, "ПИК", createdAt, createdAt))
+		mock.ExpectExec(`DELETE FROM developers WHERE id = \$1`).
+			WithArgs(3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		recorder := serve("/developers/3")
+
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return 409 when the developer has houses", func(t *testing.T) {
+		mock.ExpectQuery(`FROM developers WHERE id = \$1`).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows(developerColumnNames).AddRow(3, "ПИК", createdAt, createdAt))
+		mock.ExpectExec(`DELETE FROM developers`).
+			WithArgs(3).
+			WillReturnError(&pq.Error{Code: foreignKeyViolation})
+
+		recorder := serve("/developers/3")
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return 404 for a missing developer", func(t *testing.T) {
+		mock.ExpectQuery(`FROM developers WHERE id = \$1`).
+			WithArgs(4).
+			WillReturnRows(sqlmock.NewRows(developerColumnNames))
+
+		recorder := serve("/developers/4")
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleGetDeveloperHouses(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	r := gin.Default()
+	r.GET("/developers/:id/houses", func(c *gin.Context) {
+		c.Set("userType", c.GetHeader("X-User-Type"))
+	}, NewHandler(NewStore(db)).handleGetDeveloperHouses)
+
+	serve := func(userType string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest("GET", "/developers/3/houses", nil)
+		request.Header.Set("X-User-Type", userType)
+		r.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	createdAt := time.Date(2024, 8, 20, 9, 0, 0, 0, time.UTC)
+	houseColumns := []string{"id", "address", "year", "name", "developer_id", "floors", "material", "elevator", "parking", "created_at", "updated_at", "archived_at"}
+
+	for _, tc := range []struct {
+		userType string
+		query    string
+	}{
+		{"client", `WHERE h.developer_id = \$1 AND h.archived_at IS NULL ORDER BY h.id`},
+		{"moderator", `WHERE h.developer_id = \$1 ORDER BY h.id`},
+	} {
+		t.Run("should list houses for a "+tc.userType, func(t *testing.T) {
+			mock.ExpectQuery(`FROM developers WHERE id = \$1`).
+				WithArgs(3).
+				WillReturnRows(sqlmock.NewRows(developerColumnNames).AddRow(3, "ПИК", createdAt, createdAt))
+			mock.ExpectQuery(tc.query).
+				WithArgs(3).
+				WillReturnRows(sqlmock.NewRows(houseColumns).
+					AddRow(1, "Лесная улица, 7, Москва", 2011, "ПИК", 3, 17, "panel", true, nil, createdAt, createdAt, nil))
+
+			recorder := serve(tc.userType)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			var response struct {
+				Houses []models.House `json:"houses"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatalf("error decoding response: %v", err)
+			}
+			if assert.Len(t, response.Houses, 1) {
+				assert.Equal(t, "ПИК", response.Houses[0].Developer)
+				assert.Equal(t, 3, *response.Houses[0].DeveloperID)
+				assert.Equal(t, "panel", response.Houses[0].Material)
+				assert.Nil(t, response.Houses[0].Parking)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
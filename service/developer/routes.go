@@ -0,0 +1,286 @@
+package developer
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type Handler struct {
+	store models.DeveloperStore
+}
+
+func NewHandler(store models.DeveloperStore) *Handler {
+	return &Handler{store: store}
+}
+
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+
+	allUsers := router.Group("/")
+	allUsers.Use(middleware.AuthMiddleware("moderator", "client"))
+	{
+		allUsers.GET("/developers", h.handleGetDevelopers)
+		allUsers.GET("/developers/:id", h.handleGetDeveloper)
+		allUsers.GET("/developers/:id/houses", h.handleGetDeveloperHouses)
+	}
+
+	moderationsOnly := router.Group("/")
+	moderationsOnly.Use(middleware.AuthMiddleware("moderator"))
+	{
+		moderationsOnly.POST("/developers", h.handleCreateDeveloper)
+		moderationsOnly.PUT("/developers/:id", h.handleUpdateDeveloper)
+		moderationsOnly.DELETE("/developers/:id", h.handleDeleteDeveloper)
+	}
+}
+
+// pathID parses the :id path parameter, writing 400 when it is invalid.
+func pathID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		utils.WriteError(c, http.StatusBadRequest, "invalid developer id")
+		return 0, false
+	}
+	return id, true
+}
+
+// parsePayload reads and validates the developer name, writing 400 when it is invalid.
+func parsePayload(c *gin.Context) (models.DeveloperPayload, bool) {
+	var payload models.DeveloperPayload
+	if err := utils.ParseJSON(c, &payload); err != nil {
+		utils.WriteError(c, http.StatusBadRequest, err.Error())
+		return payload, false
+	}
+	payload.Name = strings.TrimSpace(payload.Name)
+	if err := utils.Validate.Struct(payload); err != nil {
+		errors := err.(validator.ValidationErrors)
+		utils.WriteError(c, http.StatusBadRequest, utils.FormatValidationError(errors))
+		return payload, false
+	}
+	return payload, true
+}
+
+// @Summary Create Developer
+// @Description Add a developer. Names are unique regardless of case. Requires moderator access.
+// @Tags Developers
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.DeveloperPayload true "Developer name"
+// @Success 201 {object} models.Developer "Developer created"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden"
+// @Failure 409 {object} utils.ErrorResponse "Name is already taken"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /developers [post]
+func (h *Handler) handleCreateDeveloper(c *gin.Context) {
+	payload, ok := parsePayload(c)
+	if !ok {
+		return
+	}
+
+	developer, err := h.store.CreateDeveloper(payload.Name)
+	if errors.Is(err, ErrNameTaken) {
+		utils.WriteError(c, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	middleware.RecordAudit(c.Request.Context(), models.AuditDeveloperCreate, "developer", developer.ID, nil, developer)
+	utils.WriteJSON(c, http.StatusCreated, developer)
+}
+
+// @Summary Get Developers
+// @Description All developers, ordered by name. Requires authorization for both moderator and client.
+// @Tags Developers
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} utils.DevelopersResponse "Developers retrieved"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /developers [get]
+func (h *Handler) handleGetDevelopers(c *gin.Context) {
+	developers, err := h.store.GetDevelopers()
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(c, http.StatusOK, gin.H{"developers": developers})
+}
+
+// @Summary Get Developer
+// @Description Requires authorization for both moderator and client.
+// @Tags Developers
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Developer ID"
+// @Success 200 {object} models.Developer "Developer retrieved"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Developer not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /developers/{id} [get]
+func (h *Handler) handleGetDeveloper(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	developer, err := h.store.GetDeveloper(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.WriteError(c, http.StatusNotFound, models.ErrDeveloperNotFound.Error())
+		return
+	}
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(c, http.StatusOK, developer)
+}
+
+// @Summary Get Developer Houses
+// @Description Houses built by the developer. Archived houses are only returned to moderators. Requires authorization for both moderator and client.
+// @Tags Developers
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Developer ID"
+// @Success 200 {object} utils.HousesResponse "Houses retrieved"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Developer not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /developers/{id}/houses [get]
+func (h *Handler) handleGetDeveloperHouses(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.store.GetDeveloper(id); errors.Is(err, sql.ErrNoRows) {
+		utils.WriteError(c, http.StatusNotFound, models.ErrDeveloperNotFound.Error())
+		return
+	} else if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	houses, err := h.store.GetDeveloperHouses(id, c.GetString("userType") == "moderator")
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(c, http.StatusOK, gin.H{"houses": houses})
+}
+
+// @Summary Update Developer
+// @Description Rename the developer. Its houses show the new name. Requires moderator access.
+// @Tags Developers
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Developer ID"
+// @Param request body models.DeveloperPayload true "Developer name"
+// @Success 200 {object} models.Developer "Developer updated"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden"
+// @Failure 404 {object} utils.ErrorResponse "Developer not found"
+// @Failure 409 {object} utils.ErrorResponse "Name is already taken"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /developers/{id} [put]
+func (h *Handler) handleUpdateDeveloper(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+	payload, ok := parsePayload(c)
+	if !ok {
+		return
+	}
+
+	before, err := h.store.GetDeveloper(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.WriteError(c, http.StatusNotFound, models.ErrDeveloperNotFound.Error())
+		return
+	}
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	developer, err := h.store.UpdateDeveloper(id, payload.Name)
+	switch {
+	case errors.Is(err, ErrNameTaken):
+		utils.WriteError(c, http.StatusConflict, err.Error())
+		return
+	case errors.Is(err, sql.ErrNoRows):
+		utils.WriteError(c, http.StatusNotFound, models.ErrDeveloperNotFound.Error())
+		return
+	case err != nil:
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	middleware.RecordAudit(c.Request.Context(), models.AuditDeveloperUpdate, "developer", developer.ID, before, developer)
+	utils.WriteJSON(c, http.StatusOK, developer)
+}
+
+// @Summary Delete Developer
+// @Description Remove a developer. Developers that still have houses, archived ones included, cannot be removed. Requires moderator access.
+// @Tags Developers
+// @Security Bearer
+// @Param id path int true "Developer ID"
+// @Success 204 "Developer deleted"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden"
+// @Failure 404 {object} utils.ErrorResponse "Developer not found"
+// @Failure 409 {object} utils.ErrorResponse "Developer has houses"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /developers/{id} [delete]
+func (h *Handler) handleDeleteDeveloper(c *gin.Context) {
+	id, ok := pathID(c)
+	if !ok {
+		return
+	}
+
+	before, err := h.store.GetDeveloper(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.WriteError(c, http.StatusNotFound, models.ErrDeveloperNotFound.Error())
+		return
+	}
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	err = h.store.DeleteDeveloper(id)
+	switch {
+	case errors.Is(err, ErrHasHouses):
+		utils.WriteError(c, http.StatusConflict, err.Error())
+		return
+	case errors.Is(err, sql.ErrNoRows):
+		utils.WriteError(c, http.StatusNotFound, models.ErrDeveloperNotFound.Error())
+		return
+	case err != nil:
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	middleware.RecordAudit(c.Request.Context(), models.AuditDeveloperDelete, "developer", id, before, nil)
+	c.Status(http.StatusNoContent)
+}
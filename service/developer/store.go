@@ -0,0 +1,172 @@
+package developer
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/utils"
+	"github.com/lib/pq"
+)
+
+// ErrNameTaken is returned when another developer has the same name
+// regardless of case.
+var ErrNameTaken = errors.New("a developer with this name already exists")
+
+// ErrHasHouses is returned when a developer that still has houses is deleted.
+var ErrHasHouses = errors.New("the developer has houses")
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+const developerColumns = `id, name, created_at, updated_at`
+
+func scanDeveloper(row utils.Scanner) (models.Developer, error) {
+	var d models.Developer
+	err := row.Scan(&d.ID, &d.Name, &d.CreatedAt, &d.UpdatedAt)
+	return d, err
+}
+
+func hasCode(err error, code string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && string(pqErr.Code) == code
+}
+
+func (s *Store) CreateDeveloper(name string) (models.Developer, error) {
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+	query := `
+		INSERT INTO developers (name, created_at, updated_at)
+		VALUES ($1, $2, $2)
+		RETURNING ` + developerColumns
+
+	developer, err := scanDeveloper(s.db.QueryRow(query, strings.TrimSpace(name), currentTime))
+	if hasCode(err, uniqueViolation) {
+		return models.Developer{}, ErrNameTaken
+	}
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return models.Developer{}, err
+	}
+	return developer, nil
+}
+
+func (s *Store) GetDevelopers() ([]models.Developer, error) {
+	rows, err := s.db.Query(`SELECT ` + developerColumns + ` FROM developers ORDER BY lower(name)`)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	developers := []models.Developer{}
+	for rows.Next() {
+		developer, err := scanDeveloper(rows)
+		if err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		developers = append(developers, developer)
+	}
+
+	return developers, rows.Err()
+}
+
+func (s *Store) GetDeveloper(id int) (models.Developer, error) {
+	return scanDeveloper(s.db.QueryRow(`SELECT `+developerColumns+` FROM developers WHERE id = $1`, id))
+}
+
+// UpdateDeveloper renames the developer. Houses refer to developers by id, so
+// they show the new name right away.
+func (s *Store) UpdateDeveloper(id int, name string) (models.Developer, error) {
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+	query := `
+		UPDATE developers
+		SET name = $1, updated_at = $2
+		WHERE id = $3
+		RETURNING ` + developerColumns
+
+	developer, err := scanDeveloper(s.db.QueryRow(query, strings.TrimSpace(name), currentTime, id))
+	if hasCode(err, uniqueViolation) {
+		return models.Developer{}, ErrNameTaken
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Error executing query: %v\n", err)
+	}
+	return developer, err
+}
+
+// DeleteDeveloper removes a developer without houses. It returns ErrHasHouses
+// otherwise, archived houses included.
+func (s *Store) DeleteDeveloper(id int) error {
+	res, err := s.db.Exec(`DELETE FROM developers WHERE id = $1`, id)
+	if hasCode(err, foreignKeyViolation) {
+		return ErrHasHouses
+	}
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetDeveloperHouses returns the houses of the developer, archived ones only
+// when includeArchived is set.
+func (s *Store) GetDeveloperHouses(id int, includeArchived bool) ([]models.House, error) {
+	query := `
+		SELECT h.id, h.address, h.year, d.name, h.developer_id,
+			h.floors, COALESCE(h.material, ''), h.elevator, h.parking, h.created_at, h.updated_at, h.archived_at
+		FROM house h
+		JOIN developers d ON d.id = h.developer_id
+		WHERE h.developer_id = $1`
+	if !includeArchived {
+		query += ` AND h.archived_at IS NULL`
+	}
+	query += ` ORDER BY h.id`
+
+	rows, err := s.db.Query(query, id)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	houses := []models.House{}
+	for rows.Next() {
+		var house models.House
+		var archivedAt sql.NullTime
+		err := rows.Scan(&house.Id, &house.Address, &house.Year, &house.Developer, &house.DeveloperID,
+			&house.Floors, &house.Material, &house.Elevator, &house.Parking, &house.Created_at, &house.Updated_at, &archivedAt)
+		if err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		if archivedAt.Valid {
+			house.ArchivedAt = &archivedAt.Time
+		}
+		houses = append(houses, house)
+	}
+
+	return houses, rows.Err()
+}
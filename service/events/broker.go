@@ -0,0 +1,142 @@
+package events
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/delapaska/avito-rent/models"
+	"github.com/lib/pq"
+)
+
+// channel is the NOTIFY channel the flat trigger publishes event ids to.
+const channel = "house_events"
+
+// subscriberBuffer is how many events a stream may lag behind before it is
+// dropped; the client then reconnects and catches up with Last-Event-ID.
+const subscriberBuffer = 64
+
+// Broker fans out house events received over Postgres LISTEN/NOTIFY to the
+// streams open on this instance. Every API instance runs its own broker, so
+// an event is delivered no matter which instance changed the flat.
+type Broker struct {
+	store models.HouseEventStore
+
+	mu          sync.Mutex
+	subscribers map[int]map[chan models.HouseEvent]struct{}
+}
+
+func NewBroker(store models.HouseEventStore) *Broker {
+	return &Broker{
+		store:       store,
+		subscribers: make(map[int]map[chan models.HouseEvent]struct{}),
+	}
+}
+
+// Listen starts receiving notifications on a dedicated connection.
+func (b *Broker) Listen(psqlInfo string) error {
+	listener := pq.NewListener(psqlInfo, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("Events listener: %v\n", err)
+		}
+	})
+	if err := listener.Listen(channel); err != nil {
+		return err
+	}
+
+	go b.run(listener.Notify)
+	return nil
+}
+
+func (b *Broker) run(notifications <-chan *pq.Notification) {
+	for n := range notifications {
+		if n == nil {
+			// The connection was re-established and notifications may have
+			// been lost, so make the clients resume from their last event.
+			b.closeAll()
+			continue
+		}
+		b.dispatch(n.Extra)
+	}
+}
+
+func (b *Broker) dispatch(payload string) {
+	var notification struct {
+		ID      int64 `json:"id"`
+		HouseID int   `json:"house_id"`
+	}
+	if err := json.Unmarshal([]byte(payload), &notification); err != nil {
+		log.Printf("Error decoding notification: %v\n", err)
+		return
+	}
+
+	b.mu.Lock()
+	_, ok := b.subscribers[notification.HouseID]
+	b.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	event, err := b.store.GetHouseEvent(notification.ID)
+	if err != nil {
+		log.Printf("Error fetching house event %d: %v\n", notification.ID, err)
+		return
+	}
+	b.publish(event)
+}
+
+func (b *Broker) publish(event models.HouseEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[event.HouseID] {
+		select {
+		case ch <- event:
+		default:
+			b.remove(event.HouseID, ch)
+		}
+	}
+}
+
+// Subscribe returns a channel of events of the house. The channel is closed
+// when the subscriber falls behind or the listener reconnects.
+func (b *Broker) Subscribe(houseID int) (<-chan models.HouseEvent, func()) {
+	ch := make(chan models.HouseEvent, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[houseID] == nil {
+		b.subscribers[houseID] = make(map[chan models.HouseEvent]struct{})
+	}
+	b.subscribers[houseID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(houseID, ch)
+	}
+}
+
+func (b *Broker) remove(houseID int, ch chan models.HouseEvent) {
+	subs := b.subscribers[houseID]
+	if _, ok := subs[ch]; !ok {
+		return
+	}
+	delete(subs, ch)
+	close(ch)
+	if len(subs) == 0 {
+		delete(b.subscribers, houseID)
+	}
+}
+
+func (b *Broker) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for houseID, subs := range b.subscribers {
+		for ch := range subs {
+			b.remove(houseID, ch)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleHouseEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(db)
+	broker := NewBroker(store)
+	handler := NewHandler(store, broker)
+
+	newRouter := func(userType string) *gin.Engine {
+		r := gin.Default()
+		r.GET("/house/:id/events", func(c *gin.Context) {
+			c.Set("userType", userType)
+		}, handler.handleHouseEvents)
+		return r
+	}
+
+	flatJSON := func(id int, status string) []byte {
+		b, _ := json.Marshal(models.Flat{Id: id, House_id: 1, Price: 100000, Rooms: 3, Status: status})
+		return b
+	}
+
+	t.Run("should replay events after Last-Event-ID hiding unapproved flats from clients", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery(`SELECT id, house_id, type, flat, old_price, created_at FROM house_events WHERE house_id = \$1 AND \( id > \$2 OR created_at >= \(SELECT created_at FROM house_events WHERE id = \$2\) - make_interval\(secs => \$3\) \) ORDER BY id`).
+			WithArgs(1, int64(5), replayLookBack.Seconds()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "type", "flat", "old_price", "created_at"}).
+				AddRow(6, 1, models.EventFlatCreated, flatJSON(10, models.StatusCreated), nil, now).
+				AddRow(7, 1, models.EventFlatApproved, flatJSON(11, models.StatusApproved), nil, now))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, "GET", "/house/1/events", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Last-Event-ID", "5")
+
+		recorder := httptest.NewRecorder()
+		newRouter("client").ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+		assert.NotContains(t, recorder.Body.String(), "id: 6\n")
+		assert.Contains(t, recorder.Body.String(), "id: 7\nevent: flat_approved\ndata: ")
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("should stream live events to moderators", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, "GET", "/house/1/events", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			broker.publish(models.HouseEvent{
+				ID:      8,
+				HouseID: 1,
+				Type:    models.EventFlatCreated,
+				Flat:    models.Flat{Id: 12, House_id: 1, Price: 100000, Rooms: 3, Status: models.StatusCreated},
+			})
+		}()
+
+		recorder := httptest.NewRecorder()
+		newRouter("moderator").ServeHTTP(recorder, req)
+
+		assert.Contains(t, recorder.Body.String(), "id: 8\nevent: flat_created\ndata: ")
+	})
+
+	t.Run("should deliver events committed out of id order once", func(t *testing.T) {
+		now := time.Now()
+		// Event 13 committed before event 12, so the client resumes after 13
+		// and still has to get 12, which the look-back window returns.
+		mock.ExpectQuery(`FROM house_events WHERE house_id = \$1`).
+			WithArgs(1, int64(13), replayLookBack.Seconds()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "type", "flat", "old_price", "created_at"}).
+				AddRow(12, 1, models.EventFlatCreated, flatJSON(20, models.StatusCreated), nil, now).
+				AddRow(13, 1, models.EventFlatCreated, flatJSON(21, models.StatusCreated), nil, now))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, "GET", "/house/1/events", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Last-Event-ID", "13")
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			for _, id := range []int64{15, 12, 14} {
+				broker.publish(models.HouseEvent{
+					ID:      id,
+					HouseID: 1,
+					Type:    models.EventFlatCreated,
+					Flat:    models.Flat{Id: int(id), House_id: 1, Price: 100000, Rooms: 3, Status: models.StatusCreated},
+				})
+			}
+		}()
+
+		recorder := httptest.NewRecorder()
+		newRouter("moderator").ServeHTTP(recorder, req)
+
+		body := recorder.Body.String()
+		for _, id := range []string{"12", "13", "14", "15"} {
+			assert.Equal(t, 1, strings.Count(body, "id: "+id+"\n"), "event %s", id)
+		}
+		assert.Less(t, strings.Index(body, "id: 15\n"), strings.Index(body, "id: 14\n"))
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("should return bad request for invalid Last-Event-ID", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/house/1/events?lastEventId=abc", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		recorder := httptest.NewRecorder()
+		newRouter("client").ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestBrokerDropsSlowSubscribers(t *testing.T) {
+	broker := NewBroker(nil)
+	events, unsubscribe := broker.Subscribe(1)
+	defer unsubscribe()
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		broker.publish(models.HouseEvent{ID: int64(i + 1), HouseID: 1})
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+}
@@ -0,0 +1,156 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const heartbeatInterval = 15 * time.Second
+
+// replayLookBack is how long before Last-Event-ID events are replayed again.
+// Concurrent transactions commit with ids out of order, so an event with a
+// smaller id may have become visible after the client received a larger one.
+const replayLookBack = time.Minute
+
+// deliveredLimit caps the ids a stream remembers to skip repeated events.
+const deliveredLimit = 1024
+
+type Handler struct {
+	store  models.HouseEventStore
+	broker *Broker
+}
+
+func NewHandler(store models.HouseEventStore, broker *Broker) *Handler {
+	return &Handler{store: store, broker: broker}
+}
+
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+
+	allUsers := router.Group("/")
+	allUsers.Use(middleware.AuthMiddleware("moderator", "client"))
+	{
+		allUsers.GET("/house/:id/events", h.handleHouseEvents)
+	}
+}
+
+// @Summary House Events
+// @Description Server-Sent Events stream of flat changes in a house: flat_created, flat_approved and flat_price_changed. Clients only receive events about approved flats. Send Last-Event-ID (or the lastEventId query parameter) to replay events missed since that id. Events created up to a minute before it are replayed too, because they may have committed later, so a resumed stream can repeat events; deduplicate them by id. Requires authorization for both moderator and client.
+// @Tags House
+// @Produce text/event-stream
+// @Security Bearer
+// @Param id path string true "House ID"
+// @Param Last-Event-ID header string false "Id of the last received event"
+// @Param lastEventId query string false "Id of the last received event, for clients that cannot set headers"
+// @Success 200 {object} models.HouseEvent "Stream of events"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /house/{id}/events [get]
+func (h *Handler) handleHouseEvents(c *gin.Context) {
+	houseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.WriteError(c, http.StatusBadRequest, "invalid house id")
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("lastEventId")
+	}
+	var lastID int64
+	if lastEventID != "" {
+		lastID, err = strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || lastID < 0 {
+			utils.WriteError(c, http.StatusBadRequest, "invalid Last-Event-ID")
+			return
+		}
+	}
+
+	userType := c.GetString("userType")
+
+	// Subscribe before reading the backlog so nothing committed in between is lost.
+	live, unsubscribe := h.broker.Subscribe(houseID)
+	defer unsubscribe()
+
+	var backlog []models.HouseEvent
+	if lastEventID != "" {
+		backlog, err = h.store.GetHouseEvents(houseID, lastID, replayLookBack)
+		if err != nil {
+			utils.WriteError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprint(c.Writer, "retry: 3000\n\n")
+	c.Writer.Flush()
+
+	// The backlog and the live events overlap and do not arrive in id order,
+	// so repeats are detected by id rather than by comparing with the last one.
+	delivered := make(map[int64]struct{})
+	var deliveredOrder []int64
+	send := func(event models.HouseEvent) error {
+		if _, ok := delivered[event.ID]; ok {
+			return nil
+		}
+		delivered[event.ID] = struct{}{}
+		deliveredOrder = append(deliveredOrder, event.ID)
+		if len(deliveredOrder) > deliveredLimit {
+			delete(delivered, deliveredOrder[0])
+			deliveredOrder = deliveredOrder[1:]
+		}
+		if userType != "moderator" && event.Flat.Status != models.StatusApproved {
+			return nil
+		}
+
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}
+
+	for _, event := range backlog {
+		if err := send(event); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-live:
+			if !ok {
+				return
+			}
+			if err := send(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package events
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/utils"
+)
+
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+func (s *Store) GetHouseEvents(houseID int, afterID int64, lookBack time.Duration) ([]models.HouseEvent, error) {
+	// Ids are taken when a flat changes but events become visible on commit,
+	// so an event with a smaller id can commit after afterID was delivered.
+	query := `
+		SELECT id, house_id, type, flat, old_price, created_at
+		FROM house_events
+		WHERE house_id = $1 AND (
+			id > $2
+			OR created_at >= (SELECT created_at FROM house_events WHERE id = $2) - make_interval(secs => $3)
+		)
+		ORDER BY id`
+
+	rows, err := s.db.Query(query, houseID, afterID, lookBack.Seconds())
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.HouseEvent
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (s *Store) GetHouseEvent(id int64) (models.HouseEvent, error) {
+	query := `
+		SELECT id, house_id, type, flat, old_price, created_at
+		FROM house_events
+		WHERE id = $1`
+
+	return scanEvent(s.db.QueryRow(query, id))
+}
+
+func scanEvent(row utils.Scanner) (models.HouseEvent, error) {
+	var event models.HouseEvent
+	var flat []byte
+	var oldPrice sql.NullInt64
+	if err := row.Scan(&event.ID, &event.HouseID, &event.Type, &flat, &oldPrice, &event.CreatedAt); err != nil {
+		return models.HouseEvent{}, err
+	}
+	if err := json.Unmarshal(flat, &event.Flat); err != nil {
+		return models.HouseEvent{}, err
+	}
+	if oldPrice.Valid {
+		price := int(oldPrice.Int64)
+		event.OldPrice = &price
+	}
+	return event, nil
+}
@@ -0,0 +1,204 @@
+package favorite
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRouter(handler *Handler, userID uuid.UUID, userType string) *gin.Engine {
+	r := gin.Default()
+	setUser := func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("userType", userType)
+	}
+	r.POST("/flat/:id/favorite", setUser, handler.handleAddFavorite)
+	r.DELETE("/flat/:id/favorite", setUser, handler.handleRemoveFavorite)
+	r.GET("/flat/:id/favorites", setUser, handler.handleGetFlatFavorites)
+	r.GET("/favorites", setUser, handler.handleGetFavorites)
+	return r
+}
+
+func serve(t *testing.T, r *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestHandleAddFavorite(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	userID := uuid.New()
+	r := newRouter(NewHandler(NewStore(db)), userID, "client")
+
+	t.Run("should bookmark an approved flat", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT status FROM flat WHERE id = \$1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.StatusApproved))
+		mock.ExpectExec(`INSERT INTO favorites \(user_id, flat_id, created_at\)`).
+			WithArgs(userID, 1, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		recorder := serve(t, r, "POST", "/flat/1/favorite")
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should hide flats that are not approved from clients", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT status FROM flat WHERE id = \$1`).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.StatusOnModeration))
+
+		recorder := serve(t, r, "POST", "/flat/2/favorite")
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject an invalid flat id", func(t *testing.T) {
+		recorder := serve(t, r, "POST", "/flat/abc/favorite")
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestHandleRemoveFavorite(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	userID := uuid.New()
+	r := newRouter(NewHandler(NewStore(db)), userID, "client")
+
+	t.Run("should remove a bookmark", func(t *testing.T) {
+		mock.ExpectExec(`DELETE FROM favorites WHERE user_id = \$1 AND flat_id = \$2`).
+			WithArgs(userID, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		recorder := serve(t, r, "DELETE", "/flat/1/favorite")
+
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return 404 for flats that are not bookmarked", func(t *testing.T) {
+		mock.ExpectExec(`DELETE FROM favorites WHERE user_id = \$1 AND flat_id = \$2`).
+			WithArgs(userID, 2).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		recorder := serve(t, r, "DELETE", "/flat/2/favorite")
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleGetFavorites(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	userID := uuid.New()
+	columns := []string{"id", "house_id", "price", "rooms", "status", "created_at"}
+
+	t.Run("should only return approved flats to clients", func(t *testing.T) {
+		r := newRouter(NewHandler(NewStore(db)), userID, "client")
+		mock.ExpectQuery(`WHERE fav.user_id = \$1 AND f.status = 'approved' AND f.archived_at IS NULL ORDER BY`).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, 1, 5000000, 2, models.StatusApproved, time.Now()))
+
+		recorder := serve(t, r, "GET", "/favorites")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var response struct {
+			Favorites []models.Favorite `json:"favorites"`
+		}
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		assert.Len(t, response.Favorites, 1)
+		assert.Equal(t, 1, response.Favorites[0].Flat.Id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return every bookmark to moderators", func(t *testing.T) {
+		r := newRouter(NewHandler(NewStore(db)), userID, "moderator")
+		mock.ExpectQuery(`WHERE fav.user_id = \$1 ORDER BY`).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		recorder := serve(t, r, "GET", "/favorites")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"favorites": []}`, recorder.Body.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleGetFlatFavorites(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	ownerID := uuid.New()
+	columns := []string{"id", "owner_id", "count"}
+
+	t.Run("should return the count to the owner", func(t *testing.T) {
+		r := newRouter(NewHandler(NewStore(db)), ownerID, "client")
+		mock.ExpectQuery(`SELECT f.id, f.owner_id`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, ownerID, 12))
+
+		recorder := serve(t, r, "GET", "/flat/1/favorites")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"flat_id": 1, "favorites": 12}`, recorder.Body.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should forbid other clients", func(t *testing.T) {
+		r := newRouter(NewHandler(NewStore(db)), uuid.New(), "client")
+		mock.ExpectQuery(`SELECT f.id, f.owner_id`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, ownerID, 12))
+
+		recorder := serve(t, r, "GET", "/flat/1/favorites")
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return the count to moderators", func(t *testing.T) {
+		r := newRouter(NewHandler(NewStore(db)), uuid.New(), "moderator")
+		mock.ExpectQuery(`SELECT f.id, f.owner_id`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, nil, 0))
+
+		recorder := serve(t, r, "GET", "/flat/1/favorites")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
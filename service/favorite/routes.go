@@ -0,0 +1,185 @@
+package favorite
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	store models.FavoriteStore
+}
+
+func NewHandler(store models.FavoriteStore) *Handler {
+	return &Handler{store: store}
+}
+
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+
+	allUsers := router.Group("/")
+	allUsers.Use(middleware.AuthMiddleware("moderator", "client"))
+	{
+		allUsers.POST("/flat/:id/favorite", h.handleAddFavorite)
+		allUsers.DELETE("/flat/:id/favorite", h.handleRemoveFavorite)
+		allUsers.GET("/flat/:id/favorites", h.handleGetFlatFavorites)
+		allUsers.GET("/favorites", h.handleGetFavorites)
+	}
+}
+
+// flatID parses the :id path parameter, writing 400 when it is invalid.
+func flatID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		utils.WriteError(c, http.StatusBadRequest, "invalid flat id")
+		return 0, false
+	}
+	return id, true
+}
+
+// @Summary Add Favorite
+// @Description Bookmark a flat. Clients can only bookmark approved flats. Adding a flat twice has no effect. Requires authorization for both moderator and client.
+// @Tags Favorites
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Flat ID"
+// @Success 201 {object} utils.SubscriptionResponse "Flat added to favorites"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Flat not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /flat/{id}/favorite [post]
+func (h *Handler) handleAddFavorite(c *gin.Context) {
+	requestId, _ := c.Get("RequestId")
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := flatID(c)
+	if !ok {
+		return
+	}
+
+	err := h.store.AddFavorite(userID, id, c.GetString("userType"))
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.WriteError(c, http.StatusNotFound, "flat not found")
+		return
+	}
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, "Failed to save favorite")
+		return
+	}
+
+	middleware.RecordAudit(c.Request.Context(), models.AuditFavoriteAdd, "favorite", id, nil, nil)
+	utils.WriteJSON(c, http.StatusCreated, gin.H{
+		"message":    "Flat added to favorites",
+		"request_id": requestId,
+		"code":       http.StatusCreated,
+	})
+}
+
+// @Summary Remove Favorite
+// @Description Remove a flat from the favorites of the current user. Requires authorization for both moderator and client.
+// @Tags Favorites
+// @Security Bearer
+// @Param id path int true "Flat ID"
+// @Success 204 "Flat removed from favorites"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Flat is not in favorites"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /flat/{id}/favorite [delete]
+func (h *Handler) handleRemoveFavorite(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := flatID(c)
+	if !ok {
+		return
+	}
+
+	err := h.store.RemoveFavorite(userID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.WriteError(c, http.StatusNotFound, "flat is not in favorites")
+		return
+	}
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	middleware.RecordAudit(c.Request.Context(), models.AuditFavoriteRemove, "favorite", id, nil, nil)
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Get Favorites
+// @Description Flats bookmarked by the current user, newest first. Flats that are no longer approved stay bookmarked but are only shown to moderators. Requires authorization for both moderator and client.
+// @Tags Favorites
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} utils.FavoritesResponse "Favorites retrieved"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /favorites [get]
+func (h *Handler) handleGetFavorites(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	favorites, err := h.store.GetFavorites(userID, c.GetString("userType"))
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(c, http.StatusOK, gin.H{"favorites": favorites})
+}
+
+// @Summary Get Flat Favorites
+// @Description Number of users who bookmarked the flat. Available to moderators and to the client who created the flat.
+// @Tags Favorites
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Flat ID"
+// @Success 200 {object} models.FlatFavorites "Favorite count retrieved"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Not the owner of the flat"
+// @Failure 404 {object} utils.ErrorResponse "Flat not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /flat/{id}/favorites [get]
+func (h *Handler) handleGetFlatFavorites(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := flatID(c)
+	if !ok {
+		return
+	}
+
+	favorites, err := h.store.GetFlatFavorites(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.WriteError(c, http.StatusNotFound, "flat not found")
+		return
+	}
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	isOwner := favorites.OwnerID != nil && *favorites.OwnerID == userID
+	if c.GetString("userType") != "moderator" && !isOwner {
+		utils.WriteError(c, http.StatusForbidden, "only moderators and the owner of the flat can see its favorites")
+		return
+	}
+
+	utils.WriteJSON(c, http.StatusOK, favorites)
+}
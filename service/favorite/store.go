@@ -0,0 +1,109 @@
+package favorite
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/delapaska/avito-rent/models"
+	"github.com/google/uuid"
+)
+
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// AddFavorite bookmarks the flat. Clients can only bookmark approved flats and
+// nobody can bookmark archived ones; sql.ErrNoRows is returned when the flat
+// does not exist or is hidden.
+func (s *Store) AddFavorite(userID uuid.UUID, flatID int, userRole string) error {
+	var status string
+	err := s.db.QueryRow(`SELECT status FROM flat WHERE id = $1 AND archived_at IS NULL`, flatID).Scan(&status)
+	if err != nil {
+		return err
+	}
+	if userRole != "moderator" && status != models.StatusApproved {
+		return sql.ErrNoRows
+	}
+
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	_, err = s.db.Exec(`
+		INSERT INTO favorites (user_id, flat_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`,
+		userID, flatID, currentTime)
+	if err != nil {
+		log.Printf("Error executing insert query: %v\n", err)
+	}
+	return err
+}
+
+func (s *Store) RemoveFavorite(userID uuid.UUID, flatID int) error {
+	res, err := s.db.Exec(`DELETE FROM favorites WHERE user_id = $1 AND flat_id = $2`, userID, flatID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetFavorites returns the bookmarks of the user, newest first. Flats that
+// left the approved status or were archived stay bookmarked but are hidden
+// from clients.
+func (s *Store) GetFavorites(userID uuid.UUID, userRole string) ([]models.Favorite, error) {
+	query := `
+		SELECT f.id, f.house_id, f.price, f.rooms, f.status, fav.created_at
+		FROM favorites fav
+		JOIN flat f ON f.id = fav.flat_id
+		WHERE fav.user_id = $1`
+	if userRole != "moderator" {
+		query += ` AND f.status = 'approved' AND f.archived_at IS NULL`
+	}
+	query += ` ORDER BY fav.created_at DESC, f.id DESC`
+
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	favorites := []models.Favorite{}
+	for rows.Next() {
+		var fav models.Favorite
+		if err := rows.Scan(&fav.Flat.Id, &fav.Flat.House_id, &fav.Flat.Price, &fav.Flat.Rooms, &fav.Flat.Status, &fav.CreatedAt); err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		favorites = append(favorites, fav)
+	}
+
+	return favorites, rows.Err()
+}
+
+func (s *Store) GetFlatFavorites(flatID int) (models.FlatFavorites, error) {
+	query := `
+		SELECT f.id, f.owner_id, (SELECT COUNT(*) FROM favorites WHERE flat_id = f.id)
+		FROM flat f
+		WHERE f.id = $1`
+
+	var result models.FlatFavorites
+	var ownerID uuid.NullUUID
+	if err := s.db.QueryRow(query, flatID).Scan(&result.FlatID, &ownerID, &result.Favorites); err != nil {
+		return models.FlatFavorites{}, err
+	}
+	if ownerID.Valid {
+		result.OwnerID = &ownerID.UUID
+	}
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package flat
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/notification"
+)
+
+const expiryCheckInterval = time.Minute
+
+// Expirer moves approved flats whose listing expired to models.StatusExpired
+// and reminds their owners beforehand. Both are done in the database, so
+// every API instance can run its own expirer.
+type Expirer struct {
+	store    models.FlatStore
+	notifier *notification.Notifier
+	lead     time.Duration
+	now      func() time.Time
+}
+
+// NewExpirer returns an expirer reminding owners lead before their flats
+// expire; a zero lead sends no reminders.
+func NewExpirer(store models.FlatStore, notifier *notification.Notifier, lead time.Duration) *Expirer {
+	return &Expirer{
+		store:    store,
+		notifier: notifier,
+		lead:     lead,
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Run first schedules the expiry of approved flats that have none, and then
+// expires flats until ctx is done.
+func (e *Expirer) Run(ctx context.Context) {
+	if n, err := e.store.ScheduleExpiry(e.now()); err != nil {
+		log.Printf("Failed to schedule the expiry of flats: %v\n", err)
+	} else if n > 0 {
+		log.Printf("Scheduled the expiry of %d flats\n", n)
+	}
+
+	ticker := time.NewTicker(expiryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		e.expire()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// expire sends the due reminders and then expires the due flats.
+func (e *Expirer) expire() {
+	now := e.now()
+	if e.lead > 0 {
+		flats, err := e.store.ClaimExpiryReminders(now, now.Add(e.lead))
+		if err != nil {
+			log.Printf("Failed to claim expiry reminders: %v\n", err)
+		}
+		for _, f := range flats {
+			e.notifier.NotifyFlatExpiring(f.Email, f.Flat)
+		}
+	}
+
+	flats, err := e.store.ExpireFlats(now)
+	if err != nil {
+		log.Printf("Failed to expire flats: %v\n", err)
+		return
+	}
+	if len(flats) > 0 {
+		log.Printf("Expired the listings of %d flats\n", len(flats))
+	}
+}
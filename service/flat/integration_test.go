@@ -11,10 +11,13 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/moderation"
 	"github.com/delapaska/avito-rent/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -25,6 +28,12 @@ func TestHandleCreateFlat(t *testing.T) {
 	}
 	defer db.Close()
 
+	expectHouse := func(floors any, archived bool) {
+		mock.ExpectQuery(`SELECT floors, archived_at IS NOT NULL FROM house WHERE id = \$1 FOR SHARE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"floors", "archived"}).AddRow(floors, archived))
+	}
+
 	r := gin.Default()
 	store := NewStore(db)
 	handler := &Handler{store: store}
@@ -40,9 +49,12 @@ func TestHandleCreateFlat(t *testing.T) {
 		marshalled, _ := json.Marshal(payload)
 		currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
 		mock.ExpectBegin()
-		mock.ExpectQuery(`INSERT INTO flat \(house_id, price, rooms, status\) VALUES \(\$1, \$2, \$3, 'created'\) RETURNING id, house_id, price, rooms, status`).
-			WithArgs(payload.House_id, payload.Price, payload.Rooms).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "price", "rooms", "status"}).AddRow(1, payload.House_id, payload.Price, payload.Rooms, "created"))
+		expectHouse(nil, false)
+		mock.ExpectQuery(`INSERT INTO flat \(house_id, price, rooms, status, owner_id, number, floor, total_area, living_area, balcony, listing_type, rent_period, deposit, commission, utilities_included, description, high_risk, rule_hits, decline_reason\)`).
+			WithArgs(payload.House_id, payload.Price, payload.Rooms, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, "created", "", false, nil, "").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "price", "rooms", "status", "owner_id", "archived_at", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}).AddRow(1, payload.House_id, payload.Price, payload.Rooms, "created", nil, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", nil, nil, nil))
+		mock.ExpectExec(`INSERT INTO flat_duplicates`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
 		mock.ExpectExec(`UPDATE house SET updated_at = \$1 WHERE id = \$2`).
 			WithArgs(currentTime, payload.House_id).
 			WillReturnResult(sqlmock.NewResult(1, 1))
@@ -66,11 +78,12 @@ func TestHandleCreateFlat(t *testing.T) {
 		}
 
 		expected := models.Flat{
-			Id:       1,
-			House_id: payload.House_id,
-			Price:    payload.Price,
-			Rooms:    payload.Rooms,
-			Status:   "created",
+			Id:          1,
+			House_id:    payload.House_id,
+			Price:       payload.Price,
+			Rooms:       payload.Rooms,
+			Status:      "created",
+			ListingType: models.ListingSale,
 		}
 		assert.Equal(t, expected, response)
 	})
@@ -186,8 +199,9 @@ func TestHandleCreateFlat(t *testing.T) {
 		marshalled, _ := json.Marshal(payload)
 
 		mock.ExpectBegin()
-		mock.ExpectQuery(`INSERT INTO flat \(house_id, price, rooms, status\) VALUES \(\$1, \$2, \$3, 'created'\) RETURNING id, house_id, price, rooms, status`).
-			WithArgs(payload.House_id, payload.Price, payload.Rooms).
+		expectHouse(nil, false)
+		mock.ExpectQuery(`INSERT INTO flat \(house_id, price, rooms, status, owner_id, number, floor, total_area, living_area, balcony, listing_type, rent_period, deposit, commission, utilities_included, description, high_risk, rule_hits, decline_reason\)`).
+			WithArgs(payload.House_id, payload.Price, payload.Rooms, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, "created", "", false, nil, "").
 			WillReturnError(fmt.Errorf("database error"))
 		mock.ExpectRollback()
 
@@ -218,3 +232,763 @@ func TestHandleCreateFlat(t *testing.T) {
 		assert.Contains(t, response["message"].(string), "database error")
 	})
 }
+
+func TestHandleCreateFlatAttributes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	expectHouse := func(floors any, archived bool) {
+		mock.ExpectQuery(`SELECT floors, archived_at IS NOT NULL FROM house WHERE id = \$1 FOR SHARE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"floors", "archived"}).AddRow(floors, archived))
+	}
+
+	r := gin.Default()
+	r.POST("/flats", (&Handler{store: NewStore(db)}).handleCreateFlat)
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, httptest.NewRequest("POST", "/flats", bytes.NewBufferString(body)))
+		return recorder
+	}
+
+	columns := []string{"id", "house_id", "price", "rooms", "status", "owner_id", "archived_at", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}
+
+	t.Run("should create flat with attributes", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectHouse(9, false)
+		mock.ExpectQuery(`INSERT INTO flat`).
+			WithArgs(1, 100000, 3, nil, 42, 5, 64.5, 41.2, true, "sale", "", nil, nil, nil, "created", "", false, nil, "").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 1, 100000, 3, "created", nil, nil, 42, 5, []byte("64.50"), []byte("41.20"), true, "sale", "", nil, nil, nil, []byte("1550.39"), "", false, nil, "", nil, nil, nil))
+		mock.ExpectExec(`INSERT INTO flat_duplicates`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`UPDATE house SET updated_at`).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		recorder := serve(`{"house_id": 1, "price": 100000, "rooms": 3, "number": 42, "floor": 5, "total_area": 64.5, "living_area": 41.2, "balcony": true, "listing_type": "sale"}`)
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.JSONEq(t, `{"id": 1, "house_id": 1, "price": 100000, "rooms": 3, "status": "created",
+			"number": 42, "floor": 5, "total_area": 64.5, "living_area": 41.2, "price_per_m2": 1550.39, "balcony": true, "listing_type": "sale"}`, recorder.Body.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject a floor above the house", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectHouse(9, false)
+		mock.ExpectRollback()
+
+		recorder := serve(`{"house_id": 1, "price": 100000, "rooms": 3, "floor": 10}`)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), models.ErrFloorAboveHouse.Error())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return 404 for a missing house", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT floors, archived_at IS NOT NULL FROM house WHERE id = \$1 FOR SHARE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"floors", "archived"}))
+		mock.ExpectRollback()
+
+		recorder := serve(`{"house_id": 1, "price": 100000, "rooms": 3}`)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return 409 for an archived house", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectHouse(nil, true)
+		mock.ExpectRollback()
+
+		recorder := serve(`{"house_id": 1, "price": 100000, "rooms": 3}`)
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), models.ErrHouseArchived.Error())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return 409 when the number is taken", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectHouse(nil, false)
+		mock.ExpectQuery(`INSERT INTO flat`).
+			WithArgs(1, 100000, 3, nil, 42, nil, nil, nil, nil, "sale", "", nil, nil, nil, "created", "", false, nil, "").
+			WillReturnError(&pq.Error{Code: "23505"})
+		mock.ExpectRollback()
+
+		recorder := serve(`{"house_id": 1, "price": 100000, "rooms": 3, "number": 42}`)
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject invalid attributes", func(t *testing.T) {
+		for _, body := range []string{
+			`{"house_id": 1, "price": 100000, "rooms": 3, "total_area": 40, "living_area": 41}`,
+			`{"house_id": 1, "price": 100000, "rooms": 3, "floor": 0}`,
+			`{"house_id": 1, "price": 100000, "rooms": 3, "number": -1}`,
+			`{"house_id": 1, "price": 100000, "rooms": 3, "total_area": -5}`,
+		} {
+			recorder := serve(body)
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, body)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleCreateFlatListingType(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	expectHouse := func(floors any, archived bool) {
+		mock.ExpectQuery(`SELECT floors, archived_at IS NOT NULL FROM house WHERE id = \$1 FOR SHARE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"floors", "archived"}).AddRow(floors, archived))
+	}
+
+	r := gin.Default()
+	r.POST("/flats", (&Handler{store: NewStore(db)}).handleCreateFlat)
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, httptest.NewRequest("POST", "/flats", bytes.NewBufferString(body)))
+		return recorder
+	}
+
+	t.Run("should create a rental with its terms", func(t *testing.T) {
+		columns := []string{"id", "house_id", "price", "rooms", "status", "owner_id", "archived_at", "number", "floor", "total_area", "living_area", "balcony",
+			"listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}
+		mock.ExpectBegin()
+		expectHouse(nil, false)
+		mock.ExpectQuery(`INSERT INTO flat`).
+			WithArgs(1, 45000, 2, nil, nil, nil, nil, nil, nil, "rent", "monthly", 45000, 50, false, "created", "", false, nil, "").
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, 1, 45000, 2, "created", nil, nil, nil, nil, nil, nil, nil, "rent", "monthly", 45000, 50, false, nil, "", false, nil, "", nil, nil, nil))
+		mock.ExpectExec(`INSERT INTO flat_duplicates`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`UPDATE house SET updated_at`).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		recorder := serve(`{"house_id": 1, "price": 45000, "rooms": 2, "listing_type": "rent", "rent_period": "monthly",
+			"deposit": 45000, "commission": 50, "utilities_included": false}`)
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.JSONEq(t, `{"id": 1, "house_id": 1, "price": 45000, "rooms": 2, "status": "created", "listing_type": "rent",
+			"rent_period": "monthly", "deposit": 45000, "commission": 50, "utilities_included": false}`, recorder.Body.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject terms that do not fit the listing type", func(t *testing.T) {
+		for _, body := range []string{
+			`{"house_id": 1, "price": 45000, "rooms": 2, "listing_type": "rent"}`,
+			`{"house_id": 1, "price": 45000, "rooms": 2, "listing_type": "rent", "rent_period": "weekly"}`,
+			`{"house_id": 1, "price": 45000, "rooms": 2, "listing_type": "rent", "rent_period": "daily", "commission": 101}`,
+			`{"house_id": 1, "price": 9000000, "rooms": 2, "deposit": 1000}`,
+			`{"house_id": 1, "price": 9000000, "rooms": 2, "listing_type": "sale", "rent_period": "monthly"}`,
+			`{"house_id": 1, "price": 9000000, "rooms": 2, "listing_type": "lease"}`,
+		} {
+			recorder := serve(body)
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, body)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleArchiveFlat(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	ownerID := uuid.New()
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("userType", c.GetHeader("userType"))
+		if id, err := uuid.Parse(c.GetHeader("userID")); err == nil {
+			c.Set("userID", id)
+		}
+	})
+	handler := &Handler{store: NewStore(db)}
+	r.POST("/flat/:id/archive", handler.handleArchiveFlat)
+	r.POST("/flat/:id/restore", handler.handleRestoreFlat)
+
+	flatColumns := []string{"id", "house_id", "price", "rooms", "status", "owner_id", "archived_at", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}
+	archivedAt := time.Date(2024, 8, 18, 9, 0, 0, 0, time.UTC)
+
+	do := func(path, userType string, userID uuid.UUID) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", path, nil)
+		req.Header.Set("userType", userType)
+		req.Header.Set("userID", userID.String())
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	t.Run("should archive flat of the owner without moderation fields", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, number, floor, total_area, living_area, balcony, listing_type, COALESCE\(rent_period, ''\), deposit, commission, utilities_included, price_per_m2, COALESCE\(description, ''\), high_risk, rule_hits, COALESCE\(decline_reason, ''\), expires_at, final_price, closed_at FROM flat WHERE id = \$1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(flatColumns).AddRow(1, 1, 100000, 3, "approved", ownerID, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", true, nil, "", nil, nil, nil))
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT archived_at IS NOT NULL FROM flat WHERE id = \$1 FOR UPDATE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"archived"}).AddRow(false))
+		mock.ExpectQuery(`UPDATE flat SET archived_at = \$1 WHERE id = \$2 RETURNING id, house_id, price, rooms, status, owner_id, archived_at, number, floor, total_area, living_area, balcony, listing_type, COALESCE\(rent_period, ''\), deposit, commission, utilities_included, price_per_m2, COALESCE\(description, ''\), high_risk, rule_hits, COALESCE\(decline_reason, ''\), expires_at, final_price, closed_at`).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnRows(sqlmock.NewRows(flatColumns).AddRow(1, 1, 100000, 3, "approved", ownerID, archivedAt, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", true, nil, "", nil, nil, nil))
+		mock.ExpectCommit()
+
+		recorder := do("/flat/1/archive", "client", ownerID)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.NotContains(t, recorder.Body.String(), "high_risk")
+		var response struct {
+			Flat models.Flat `json:"flat"`
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("error decoding response: %v", err)
+		}
+		if assert.NotNil(t, response.Flat.ArchivedAt) {
+			assert.Equal(t, archivedAt, *response.Flat.ArchivedAt)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should forbid archiving flat of another client", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, number, floor, total_area, living_area, balcony, listing_type, COALESCE\(rent_period, ''\), deposit, commission, utilities_included, price_per_m2, COALESCE\(description, ''\), high_risk, rule_hits, COALESCE\(decline_reason, ''\), expires_at, final_price, closed_at FROM flat WHERE id = \$1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(flatColumns).AddRow(1, 1, 100000, 3, "approved", ownerID, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", nil, nil, nil))
+
+		recorder := do("/flat/1/archive", "client", uuid.New())
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return conflict when flat is already archived", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, number, floor, total_area, living_area, balcony, listing_type, COALESCE\(rent_period, ''\), deposit, commission, utilities_included, price_per_m2, COALESCE\(description, ''\), high_risk, rule_hits, COALESCE\(decline_reason, ''\), expires_at, final_price, closed_at FROM flat WHERE id = \$1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(flatColumns).AddRow(1, 1, 100000, 3, "approved", ownerID, archivedAt, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", nil, nil, nil))
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT archived_at IS NOT NULL FROM flat WHERE id = \$1 FOR UPDATE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"archived"}).AddRow(true))
+		mock.ExpectRollback()
+
+		recorder := do("/flat/1/archive", "moderator", uuid.New())
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return conflict when restoring flat of an archived house", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, number, floor, total_area, living_area, balcony, listing_type, COALESCE\(rent_period, ''\), deposit, commission, utilities_included, price_per_m2, COALESCE\(description, ''\), high_risk, rule_hits, COALESCE\(decline_reason, ''\), expires_at, final_price, closed_at FROM flat WHERE id = \$1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(flatColumns).AddRow(1, 1, 100000, 3, "approved", ownerID, archivedAt, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", nil, nil, nil))
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT f.archived_at IS NOT NULL, h.archived_at IS NOT NULL FROM flat f JOIN house h ON h.id = f.house_id WHERE f.id = \$1 FOR UPDATE OF f`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"archived", "house_archived"}).AddRow(true, true))
+		mock.ExpectRollback()
+
+		recorder := do("/flat/1/restore", "client", ownerID)
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "restore the house first")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return not found for unknown flat", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, number, floor, total_area, living_area, balcony, listing_type, COALESCE\(rent_period, ''\), deposit, commission, utilities_included, price_per_m2, COALESCE\(description, ''\), high_risk, rule_hits, COALESCE\(decline_reason, ''\), expires_at, final_price, closed_at FROM flat WHERE id = \$1`).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows(flatColumns))
+
+		recorder := do("/flat/2/restore", "moderator", uuid.New())
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleGetFlat(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	ownerID := uuid.New()
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("userType", c.GetHeader("userType"))
+		if id, err := uuid.Parse(c.GetHeader("userID")); err == nil {
+			c.Set("userID", id)
+		}
+	})
+	r.GET("/flat/:id", (&Handler{store: NewStore(db)}).handleGetFlat)
+
+	flatColumns := []string{"id", "house_id", "price", "rooms", "status", "owner_id", "archived_at", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}
+	archivedAt := time.Date(2024, 8, 18, 9, 0, 0, 0, time.UTC)
+	expectGetFlat := func(status string, archivedAt any, highRisk bool) {
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, .* FROM flat WHERE id = \$1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(flatColumns).AddRow(1, 1, 100000, 3, status, ownerID, archivedAt, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", highRisk, nil, "", nil, nil, nil))
+	}
+	expectGetHouse := func() {
+		mock.ExpectQuery(`SELECT h.id, h.address, h.year, COALESCE\(d.name, ''\), h.floors, COALESCE\(h.material, ''\) FROM house h LEFT JOIN developers d ON d.id = h.developer_id WHERE h.id = \$1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "address", "year", "developer", "floors", "material"}).AddRow(1, "Лесная улица, 7", 2003, "ПИК", 9, "panel"))
+	}
+
+	do := func(userType string, userID uuid.UUID) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/flat/1", nil)
+		req.Header.Set("userType", userType)
+		req.Header.Set("userID", userID.String())
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	t.Run("should return an approved flat with its house to any client", func(t *testing.T) {
+		expectGetFlat(models.StatusApproved, nil, false)
+		expectGetHouse()
+
+		recorder := do("client", uuid.New())
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"flat": {"id": 1, "house_id": 1, "price": 100000, "rooms": 3, "status": "approved", "listing_type": "sale"},
+			"house": {"id": 1, "address": "Лесная улица, 7", "year": 2003, "developer": "ПИК", "floors": 9, "material": "panel"}}`, recorder.Body.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should hide flats of other users that are not listed", func(t *testing.T) {
+		expectGetFlat(models.StatusDeclined, nil, false)
+		recorder := do("client", uuid.New())
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+		expectGetFlat(models.StatusApproved, archivedAt, false)
+		recorder = do("client", uuid.New())
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should show the owner their flat in any status without moderation fields", func(t *testing.T) {
+		expectGetFlat(models.StatusOnModeration, nil, true)
+		expectGetHouse()
+
+		recorder := do("client", ownerID)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"status":"on moderation"`)
+		assert.NotContains(t, recorder.Body.String(), "high_risk")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should show moderators every flat", func(t *testing.T) {
+		expectGetFlat(models.StatusCreated, archivedAt, true)
+		expectGetHouse()
+
+		recorder := do("moderator", uuid.New())
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"high_risk":true`)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return not found for unknown flat", func(t *testing.T) {
+		mock.ExpectQuery(`FROM flat WHERE id = \$1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(flatColumns))
+
+		recorder := do("moderator", uuid.New())
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleRenewFlat(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	ownerID := uuid.New()
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("userType", c.GetHeader("userType"))
+		if id, err := uuid.Parse(c.GetHeader("userID")); err == nil {
+			c.Set("userID", id)
+		}
+	})
+	r.POST("/flat/:id/renew", (&Handler{store: NewStore(db)}).handleRenewFlat)
+
+	flatColumns := []string{"id", "house_id", "price", "rooms", "status", "owner_id", "archived_at", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}
+	expiredAt := time.Date(2024, 9, 25, 9, 0, 0, 0, time.UTC)
+	expectGetFlat := func(status string) {
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, .* FROM flat WHERE id = \$1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(flatColumns).AddRow(1, 1, 100000, 3, status, ownerID, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", expiredAt, nil, nil))
+	}
+
+	do := func(userType string, userID uuid.UUID) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/flat/1/renew", nil)
+		req.Header.Set("userType", userType)
+		req.Header.Set("userID", userID.String())
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	t.Run("should send an expired flat of the owner back to moderation", func(t *testing.T) {
+		expectGetFlat(models.StatusExpired)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT status, archived_at IS NOT NULL FROM flat WHERE id = \$1 FOR UPDATE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"status", "archived"}).AddRow(models.StatusExpired, false))
+		mock.ExpectQuery(`UPDATE flat SET status = \$1, moderator_id = NULL, expires_at = NULL, expiry_reminder_sent_at = NULL WHERE id = \$2 RETURNING`).
+			WithArgs(models.StatusCreated, 1).
+			WillReturnRows(sqlmock.NewRows(flatColumns).AddRow(1, 1, 100000, 3, models.StatusCreated, ownerID, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", nil, nil, nil))
+		mock.ExpectCommit()
+
+		recorder := do("client", ownerID)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"flat": {"id": 1, "house_id": 1, "price": 100000, "rooms": 3, "status": "created", "listing_type": "sale"}}`, recorder.Body.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should forbid renewing a flat of another user", func(t *testing.T) {
+		expectGetFlat(models.StatusExpired)
+
+		recorder := do("moderator", uuid.New())
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return conflict when the listing has not expired", func(t *testing.T) {
+		expectGetFlat(models.StatusApproved)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT status, archived_at IS NOT NULL FROM flat WHERE id = \$1 FOR UPDATE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"status", "archived"}).AddRow(models.StatusApproved, false))
+		mock.ExpectRollback()
+
+		recorder := do("client", ownerID)
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), models.ErrNotExpired.Error())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleUpdateFlatStatusDuplicates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	moderatorID := uuid.New()
+	r := gin.Default()
+	r.POST("/flat/update", func(c *gin.Context) {
+		c.Set("userID", moderatorID)
+		c.Set("userType", "moderator")
+	}, (&Handler{store: NewStore(db)}).handleUpdateFlatStatus)
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, httptest.NewRequest("POST", "/flat/update", bytes.NewBufferString(body)))
+		return recorder
+	}
+
+	flatColumns := []string{"id", "house_id", "price", "rooms", "status", "owner_id", "archived_at", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}
+
+	t.Run("should show duplicates to the moderator taking the flat", func(t *testing.T) {
+		mock.ExpectQuery(`FROM flat WHERE id = \$1`).
+			WithArgs(9).
+			WillReturnRows(sqlmock.NewRows(flatColumns).AddRow(9, 1, 100000, 3, "created", nil, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", nil, nil, nil))
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT status, moderator_id, archived_at IS NOT NULL, owner_id, listing_type, price FROM flat WHERE id = \$1 FOR UPDATE`).
+			WithArgs(9).
+			WillReturnRows(sqlmock.NewRows([]string{"status", "moderator_id", "archived", "owner_id", "listing_type", "price"}).AddRow("created", nil, false, nil, "sale", 100000))
+		mock.ExpectExec(`UPDATE flat SET status = \$1, moderator_id = \$2 WHERE id = \$3`).
+			WithArgs(models.StatusOnModeration, moderatorID, 9).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery(`FROM flat WHERE id = \$1`).
+			WithArgs(9).
+			WillReturnRows(sqlmock.NewRows(flatColumns).AddRow(9, 1, 100000, 3, "on moderation", nil, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", nil, nil, nil))
+		mock.ExpectQuery(`SELECT duplicate_id FROM flat_duplicates WHERE flat_id = \$1 ORDER BY duplicate_id`).
+			WithArgs(9).
+			WillReturnRows(sqlmock.NewRows([]string{"duplicate_id"}).AddRow(4).AddRow(7))
+
+		recorder := serve(`{"id": 9, "status": "on moderation"}`)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var response struct {
+			Flat models.Flat `json:"flat"`
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("error decoding response: %v", err)
+		}
+		assert.Equal(t, []int{4, 7}, response.Flat.DuplicateOf)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleUpdateFlatStatusDeal(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	ownerID := uuid.New()
+	r := gin.Default()
+	r.POST("/flat/update", func(c *gin.Context) {
+		c.Set("userType", c.GetHeader("userType"))
+		if id, err := uuid.Parse(c.GetHeader("userID")); err == nil {
+			c.Set("userID", id)
+		}
+	}, (&Handler{store: NewStore(db)}).handleUpdateFlatStatus)
+
+	serve := func(body, userType string, userID uuid.UUID) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/flat/update", bytes.NewBufferString(body))
+		req.Header.Set("userType", userType)
+		req.Header.Set("userID", userID.String())
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	flatColumns := []string{"id", "house_id", "price", "rooms", "status", "owner_id", "archived_at", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}
+	closedAt := time.Date(2024, 9, 10, 9, 0, 0, 0, time.UTC)
+	expectGetFlat := func(status string) {
+		mock.ExpectQuery(`FROM flat WHERE id = \$1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(flatColumns).AddRow(1, 1, 100000, 3, status, ownerID, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", nil, nil, nil))
+	}
+	expectLock := func(status string) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT status, moderator_id, archived_at IS NOT NULL, owner_id, listing_type, price FROM flat WHERE id = \$1 FOR UPDATE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"status", "moderator_id", "archived", "owner_id", "listing_type", "price"}).AddRow(status, nil, false, ownerID, "sale", 100000))
+	}
+	moderatorID := uuid.New()
+	expectModerationLock := func(status string, archived bool) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT status, moderator_id, archived_at IS NOT NULL, owner_id, listing_type, price FROM flat WHERE id = \$1 FOR UPDATE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"status", "moderator_id", "archived", "owner_id", "listing_type", "price"}).AddRow(status, moderatorID, archived, ownerID, "sale", 100000))
+	}
+
+	t.Run("should let the owner mark a flat as sold with its final price", func(t *testing.T) {
+		expectGetFlat(models.StatusApproved)
+		expectLock(models.StatusApproved)
+		mock.ExpectExec(`UPDATE flat SET status = \$1, final_price = \$2, closed_at = \$3, expires_at = NULL WHERE id = \$4`).
+			WithArgs(models.StatusSold, 95000, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery(`FROM flat WHERE id = \$1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(flatColumns).AddRow(1, 1, 100000, 3, models.StatusSold, ownerID, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", nil, 95000, closedAt))
+
+		recorder := serve(`{"id": 1, "status": "sold", "final_price": 95000}`, "client", ownerID)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"flat": {"id": 1, "house_id": 1, "price": 100000, "rooms": 3, "status": "sold", "listing_type": "sale",
+			"final_price": 95000, "closed_at": "2024-09-10T09:00:00Z"}}`, recorder.Body.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should not let a flat for sale be rented", func(t *testing.T) {
+		expectGetFlat(models.StatusApproved)
+		expectLock(models.StatusApproved)
+		mock.ExpectRollback()
+
+		recorder := serve(`{"id": 1, "status": "rented"}`, "client", ownerID)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should forbid closing a flat of another user", func(t *testing.T) {
+		expectGetFlat(models.StatusApproved)
+		expectLock(models.StatusApproved)
+		mock.ExpectRollback()
+
+		recorder := serve(`{"id": 1, "status": "sold"}`, "moderator", uuid.New())
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return conflict for a flat that is not listed", func(t *testing.T) {
+		expectGetFlat(models.StatusOnModeration)
+		expectLock(models.StatusOnModeration)
+		mock.ExpectRollback()
+
+		recorder := serve(`{"id": 1, "status": "sold"}`, "client", ownerID)
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return conflict for an archived flat", func(t *testing.T) {
+		expectGetFlat(models.StatusOnModeration)
+		expectModerationLock(models.StatusOnModeration, true)
+		mock.ExpectRollback()
+
+		recorder := serve(`{"id": 1, "status": "approved"}`, "moderator", moderatorID)
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should not approve a flat that is not on moderation", func(t *testing.T) {
+		expectGetFlat(models.StatusCreated)
+		expectModerationLock(models.StatusCreated, false)
+		mock.ExpectRollback()
+
+		recorder := serve(`{"id": 1, "status": "approved"}`, "moderator", moderatorID)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should forbid moderating a flat taken by another moderator", func(t *testing.T) {
+		expectGetFlat(models.StatusOnModeration)
+		expectModerationLock(models.StatusOnModeration, false)
+		mock.ExpectRollback()
+
+		recorder := serve(`{"id": 1, "status": "declined"}`, "moderator", uuid.New())
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should forbid clients to moderate", func(t *testing.T) {
+		recorder := serve(`{"id": 1, "status": "approved"}`, "client", ownerID)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("should return not found for unknown flat", func(t *testing.T) {
+		mock.ExpectQuery(`FROM flat WHERE id = \$1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(flatColumns))
+
+		recorder := serve(`{"id": 1, "status": "approved"}`, "moderator", uuid.New())
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleCreateFlatPremoderation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	expectHouse := func(floors any, archived bool) {
+		mock.ExpectQuery(`SELECT floors, archived_at IS NOT NULL FROM house WHERE id = \$1 FOR SHARE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"floors", "archived"}).AddRow(floors, archived))
+	}
+
+	store := NewStore(db).WithRules(moderation.Rules{
+		PriceMinRatio:     0.1,
+		PriceMaxRatio:     10,
+		MinComparables:    3,
+		PriceAction:       models.RuleActionDecline,
+		RoomsMax:          10,
+		RoomsAction:       models.RuleActionDecline,
+		BannedWords:       []string{"предоплата"},
+		BannedWordsAction: models.RuleActionHighRisk,
+	})
+	r := gin.Default()
+	r.POST("/flats", func(c *gin.Context) {
+		c.Set("userType", c.GetHeader("userType"))
+	}, (&Handler{store: store}).handleCreateFlat)
+
+	serve := func(body, userType string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/flats", bytes.NewBufferString(body))
+		req.Header.Set("userType", userType)
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	columns := []string{"id", "house_id", "price", "rooms", "status", "owner_id", "archived_at", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}
+	expectMedian := func() {
+		mock.ExpectQuery(`SELECT COALESCE\(percentile_cont\(0.5\) WITHIN GROUP \(ORDER BY price\), 0\), COUNT\(\*\) FROM flat WHERE house_id = \$1 AND listing_type = \$2 AND COALESCE\(rent_period, ''\) = \$3 AND status = 'approved' AND archived_at IS NULL`).
+			WithArgs(1, "sale", "").
+			WillReturnRows(sqlmock.NewRows([]string{"median", "count"}).AddRow(12500000.0, 5))
+	}
+
+	t.Run("should decline an obviously wrong flat with a reason", func(t *testing.T) {
+		reason := "price 1 is below 10% of the median price 12500000 of the house; 50 rooms, at most 10 allowed"
+		mock.ExpectBegin()
+		expectHouse(nil, false)
+		expectMedian()
+		mock.ExpectQuery(`INSERT INTO flat`).
+			WithArgs(1, 1, 50, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, models.StatusDeclined, "", false, sqlmock.AnyArg(), reason).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 1, 1, 50, models.StatusDeclined, nil, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil,
+				"", false, []byte(`[{"rule": "price", "action": "decline", "reason": "price 1 is below 10% of the median price 12500000 of the house"}, {"rule": "rooms", "action": "decline", "reason": "50 rooms, at most 10 allowed"}]`), reason, nil, nil, nil))
+		mock.ExpectExec(`INSERT INTO flat_duplicates`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`UPDATE house SET updated_at`).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		recorder := serve(`{"house_id": 1, "price": 1, "rooms": 50}`, "client")
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.JSONEq(t, `{"id": 1, "house_id": 1, "price": 1, "rooms": 50, "status": "declined", "listing_type": "sale",
+			"decline_reason": "`+reason+`"}`, recorder.Body.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should show high risk hits to a moderator", func(t *testing.T) {
+		hits := `[{"rule": "banned_words", "action": "high_risk", "reason": "description contains \"предоплата\""}]`
+		mock.ExpectBegin()
+		expectHouse(nil, false)
+		expectMedian()
+		mock.ExpectQuery(`INSERT INTO flat`).
+			WithArgs(1, 12000000, 3, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, models.StatusCreated, "Только предоплата", true, sqlmock.AnyArg(), "").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 1, 12000000, 3, models.StatusCreated, nil, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil,
+				"Только предоплата", true, []byte(hits), "", nil, nil, nil))
+		mock.ExpectExec(`INSERT INTO flat_duplicates`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`UPDATE house SET updated_at`).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		recorder := serve(`{"house_id": 1, "price": 12000000, "rooms": 3, "description": " Только предоплата "}`, "moderator")
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.JSONEq(t, `{"id": 1, "house_id": 1, "price": 12000000, "rooms": 3, "status": "created", "listing_type": "sale",
+			"description": "Только предоплата", "high_risk": true, "rule_hits": `+hits+`}`, recorder.Body.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
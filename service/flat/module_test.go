@@ -7,6 +7,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/delapaska/avito-rent/models"
+	"github.com/google/uuid"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -18,6 +19,12 @@ func TestCreateFlat(t *testing.T) {
 	}
 	defer db.Close()
 
+	expectHouse := func(floors any, archived bool) {
+		mock.ExpectQuery(`SELECT floors, archived_at IS NOT NULL FROM house WHERE id = \$1 FOR SHARE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"floors", "archived"}).AddRow(floors, archived))
+	}
+
 	store := NewStore(db)
 
 	t.Run("should return error when starting transaction fails", func(t *testing.T) {
@@ -39,8 +46,9 @@ func TestCreateFlat(t *testing.T) {
 
 	t.Run("should return error when insert query fails", func(t *testing.T) {
 		mock.ExpectBegin()
-		mock.ExpectQuery(`INSERT INTO flat \(house_id, price, rooms, status\) VALUES \(\$1, \$2, \$3, 'created'\) RETURNING id, house_id, price, rooms, status`).
-			WithArgs(1, 100000, 3).
+		expectHouse(nil, false)
+		mock.ExpectQuery(`INSERT INTO flat \(house_id, price, rooms, status, owner_id, number, floor, total_area, living_area, balcony, listing_type, rent_period, deposit, commission, utilities_included, description, high_risk, rule_hits, decline_reason\)`).
+			WithArgs(1, 100000, 3, nil, nil, nil, nil, nil, nil, "", "", nil, nil, nil, "created", "", false, nil, "").
 			WillReturnError(fmt.Errorf("insert query error"))
 		mock.ExpectRollback()
 
@@ -58,13 +66,40 @@ func TestCreateFlat(t *testing.T) {
 		assert.Equal(t, "insert query error", err.Error())
 	})
 
+	t.Run("should not insert a flat into a missing house", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT floors, archived_at IS NOT NULL FROM house WHERE id = \$1 FOR SHARE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"floors", "archived"}))
+		mock.ExpectRollback()
+
+		_, err := store.CreateFlat(models.Flat{House_id: 1, Price: 100000, Rooms: 3})
+
+		assert.ErrorIs(t, err, models.ErrHouseNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should not insert a flat into an archived house", func(t *testing.T) {
+		mock.ExpectBegin()
+		expectHouse(nil, true)
+		mock.ExpectRollback()
+
+		_, err := store.CreateFlat(models.Flat{House_id: 1, Price: 100000, Rooms: 3})
+
+		assert.ErrorIs(t, err, models.ErrHouseArchived)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 	t.Run("should return error when update query fails", func(t *testing.T) {
 		currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
 		mock.ExpectBegin()
-		mock.ExpectQuery(`INSERT INTO flat \(house_id, price, rooms, status\) VALUES \(\$1, \$2, \$3, 'created'\) RETURNING id, house_id, price, rooms, status`).
-			WithArgs(1, 100000, 3).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "price", "rooms", "status"}).
-				AddRow(1, 1, 100000, 3, "created"))
+		expectHouse(nil, false)
+		mock.ExpectQuery(`INSERT INTO flat \(house_id, price, rooms, status, owner_id, number, floor, total_area, living_area, balcony, listing_type, rent_period, deposit, commission, utilities_included, description, high_risk, rule_hits, decline_reason\)`).
+			WithArgs(1, 100000, 3, nil, nil, nil, nil, nil, nil, "", "", nil, nil, nil, "created", "", false, nil, "").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "price", "rooms", "status", "owner_id", "archived_at", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}).
+				AddRow(1, 1, 100000, 3, "created", nil, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", nil, nil, nil))
+		mock.ExpectExec(`INSERT INTO flat_duplicates`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
 		mock.ExpectExec(`UPDATE house SET updated_at = \$1 WHERE id = \$2`).
 			WithArgs(currentTime, 1).
 			WillReturnError(fmt.Errorf("update query error"))
@@ -87,10 +122,14 @@ func TestCreateFlat(t *testing.T) {
 	t.Run("should successfully create flat and update house", func(t *testing.T) {
 		currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
 		mock.ExpectBegin()
-		mock.ExpectQuery(`INSERT INTO flat \(house_id, price, rooms, status\) VALUES \(\$1, \$2, \$3, 'created'\) RETURNING id, house_id, price, rooms, status`).
-			WithArgs(1, 100000, 3).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "price", "rooms", "status"}).
-				AddRow(1, 1, 100000, 3, "created"))
+		expectHouse(nil, false)
+		mock.ExpectQuery(`INSERT INTO flat \(house_id, price, rooms, status, owner_id, number, floor, total_area, living_area, balcony, listing_type, rent_period, deposit, commission, utilities_included, description, high_risk, rule_hits, decline_reason\)`).
+			WithArgs(1, 100000, 3, nil, nil, nil, nil, nil, nil, "", "", nil, nil, nil, "created", "", false, nil, "").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "price", "rooms", "status", "owner_id", "archived_at", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}).
+				AddRow(1, 1, 100000, 3, "created", nil, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", nil, nil, nil))
+		mock.ExpectExec(`INSERT INTO flat_duplicates \(flat_id, duplicate_id\) SELECT \$1, id FROM flat WHERE house_id = \$2 AND id <> \$1 AND archived_at IS NULL AND rooms = \$3 AND listing_type = \$4 AND price BETWEEN \$5 AND \$6`).
+			WithArgs(1, 1, 3, "sale", 90000, 110000, nil, nil, nil, nil, 5).
+			WillReturnResult(sqlmock.NewResult(0, 2))
 		mock.ExpectExec(`UPDATE house SET updated_at = \$1 WHERE id = \$2`).
 			WithArgs(currentTime, 1).
 			WillReturnResult(sqlmock.NewResult(1, 1))
@@ -108,13 +147,104 @@ func TestCreateFlat(t *testing.T) {
 		}
 
 		expectedFlat := models.Flat{
-			Id:       1,
-			House_id: 1,
-			Price:    100000,
-			Rooms:    3,
-			Status:   "created",
+			Id:          1,
+			House_id:    1,
+			Price:       100000,
+			Rooms:       3,
+			Status:      "created",
+			ListingType: models.ListingSale,
 		}
 
 		assert.Equal(t, expectedFlat, createdFlat)
 	})
 }
+
+func TestFlatExpiration(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(db).WithListingTTL(30 * 24 * time.Hour)
+	moderatorID := uuid.New()
+	columns := []string{"id", "house_id", "price", "rooms", "status", "owner_id", "archived_at", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}
+
+	t.Run("should set expires_at when approving a flat", func(t *testing.T) {
+		expiresAt := time.Now().UTC().Add(30 * 24 * time.Hour)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT status, moderator_id, archived_at IS NOT NULL, owner_id, listing_type, price FROM flat WHERE id = \$1 FOR UPDATE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"status", "moderator_id", "archived", "owner_id", "listing_type", "price"}).AddRow(models.StatusOnModeration, moderatorID, false, nil, "sale", 100000))
+		mock.ExpectExec(`UPDATE flat SET status = \$1, expires_at = \$3, expiry_reminder_sent_at = NULL WHERE id = \$2`).
+			WithArgs(models.StatusApproved, 1, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery(`SELECT .* FROM flat WHERE id = \$1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 1, 100000, 3, models.StatusApproved, nil, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", expiresAt, nil, nil))
+
+		flat, err := store.UpdateFlatStatus(moderatorID, models.UpdateStatusPayload{Id: 1, Status: models.StatusApproved})
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, flat.ExpiresAt) {
+			assert.Equal(t, expiresAt, *flat.ExpiresAt)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should move expired approved flats to expired", func(t *testing.T) {
+		now := time.Date(2024, 9, 25, 9, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`UPDATE flat SET status = \$1 WHERE status = \$2 AND expires_at <= \$3 AND archived_at IS NULL RETURNING`).
+			WithArgs(models.StatusExpired, models.StatusApproved, now).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, 1, 100000, 3, models.StatusExpired, nil, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", now, nil, nil).
+				AddRow(2, 1, 150000, 4, models.StatusExpired, nil, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", now, nil, nil))
+
+		flats, err := store.ExpireFlats(now)
+
+		assert.NoError(t, err)
+		assert.Len(t, flats, 2)
+		assert.Equal(t, models.StatusExpired, flats[1].Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should schedule the expiry of approved flats without one", func(t *testing.T) {
+		now := time.Date(2024, 9, 25, 9, 0, 0, 0, time.UTC)
+		mock.ExpectExec(`UPDATE flat SET expires_at = \$1 WHERE status = \$2 AND expires_at IS NULL`).
+			WithArgs(now.Add(30*24*time.Hour), models.StatusApproved).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+
+		n, err := store.ScheduleExpiry(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), n)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should not schedule expiry without a listing ttl", func(t *testing.T) {
+		n, err := NewStore(db).ScheduleExpiry(time.Now())
+
+		assert.NoError(t, err)
+		assert.Zero(t, n)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should claim reminders with the emails of the owners", func(t *testing.T) {
+		now := time.Date(2024, 9, 22, 9, 0, 0, 0, time.UTC)
+		ownerID := uuid.New()
+		mock.ExpectQuery(`WITH claimed AS \( UPDATE flat SET expiry_reminder_sent_at = \$1 WHERE status = 'approved' .* JOIN users u ON u.user_id = claimed.owner_id`).
+			WithArgs(now, now.Add(72*time.Hour)).
+			WillReturnRows(sqlmock.NewRows(append(columns, "email")).
+				AddRow(1, 1, 100000, 3, models.StatusApproved, ownerID, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", now.Add(48*time.Hour), nil, nil, "owner@example.com"))
+
+		flats, err := store.ClaimExpiryReminders(now, now.Add(72*time.Hour))
+
+		assert.NoError(t, err)
+		if assert.Len(t, flats, 1) {
+			assert.Equal(t, "owner@example.com", flats[0].Email)
+			assert.Equal(t, &ownerID, flats[0].Flat.OwnerID)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
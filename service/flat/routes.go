@@ -1,10 +1,15 @@
 package flat
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/delapaska/avito-rent/middleware"
 	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/notification"
 	"github.com/delapaska/avito-rent/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -12,11 +17,12 @@ import (
 )
 
 type Handler struct {
-	store models.FlatStore
+	store    models.FlatStore
+	notifier *notification.Notifier
 }
 
-func NewHandler(store models.FlatStore) *Handler {
-	return &Handler{store: store}
+func NewHandler(store models.FlatStore, notifier *notification.Notifier) *Handler {
+	return &Handler{store: store, notifier: notifier}
 }
 
 func (h *Handler) RegisterRoutes(router *gin.Engine) {
@@ -25,18 +31,17 @@ func (h *Handler) RegisterRoutes(router *gin.Engine) {
 	allUsers.Use(middleware.AuthMiddleware("moderator", "client"))
 	{
 		allUsers.POST("/flat/create", h.handleCreateFlat)
-
-	}
-	moderationsOnly := router.Group("/")
-	moderationsOnly.Use(middleware.AuthMiddleware("moderator"))
-	{
-		moderationsOnly.POST("/flat/update", h.handleUpdateFlatStatus)
+		allUsers.GET("/flat/:id", h.handleGetFlat)
+		allUsers.POST("/flat/:id/archive", h.handleArchiveFlat)
+		allUsers.POST("/flat/:id/restore", h.handleRestoreFlat)
+		allUsers.POST("/flat/:id/renew", h.handleRenewFlat)
+		allUsers.POST("/flat/update", h.handleUpdateFlatStatus)
 	}
 
 }
 
 // @Summary Create Flat
-// @Description Create a new flat with provided details. Requires authorization for both moderator and client.
+// @Description Create a new flat with provided details. Number, floor, areas and balcony are optional; the number has to be unique within the house, the floor cannot be higher than the floors of the house and the living area cannot exceed the total area. Flats are for sale unless listing_type is rent; rentals need a rent_period and may have a deposit, a commission and utilities_included, which sales cannot have. Active flats of the house that look like the new one are recorded as its likely duplicates for moderators. New flats are checked against the pre-moderation rules: a flat breaking a declining rule is created declined with a decline_reason, one breaking a high risk rule is shown to moderators first. Requires authorization for both moderator and client.
 // @Tags Flat
 // @Accept json
 // @Produce json
@@ -45,6 +50,8 @@ func (h *Handler) RegisterRoutes(router *gin.Engine) {
 // @Success 201 {object} models.Flat "Flat created"
 // @Failure 400 {object} utils.ErrorResponse "Bad request"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "House not found"
+// @Failure 409 {object} utils.ErrorResponse "Flat number is already taken in the house or the house is archived"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /flat/create [post]
 func (h *Handler) handleCreateFlat(c *gin.Context) {
@@ -71,12 +78,46 @@ func (h *Handler) handleCreateFlat(c *gin.Context) {
 		})
 		return
 	}
+	if err := payload.Check(); err != nil {
+		utils.WriteError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	flat, err := h.store.CreateFlat(models.Flat{
-		House_id: payload.House_id,
-		Price:    payload.Price,
-		Rooms:    payload.Rooms,
-	})
+	newFlat := models.Flat{
+		House_id:          payload.House_id,
+		Price:             payload.Price,
+		Rooms:             payload.Rooms,
+		Number:            payload.Number,
+		Floor:             payload.Floor,
+		TotalArea:         payload.TotalArea,
+		LivingArea:        payload.LivingArea,
+		Balcony:           payload.Balcony,
+		ListingType:       payload.ListingType,
+		RentPeriod:        payload.RentPeriod,
+		Deposit:           payload.Deposit,
+		Commission:        payload.Commission,
+		UtilitiesIncluded: payload.UtilitiesIncluded,
+		Description:       strings.TrimSpace(payload.Description),
+	}
+	if userID, ok := c.Get("userID"); ok {
+		if ownerID, ok := userID.(uuid.UUID); ok {
+			newFlat.OwnerID = &ownerID
+		}
+	}
+
+	flat, err := h.store.CreateFlat(newFlat)
+	if errors.Is(err, models.ErrFloorAboveHouse) {
+		utils.WriteError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, models.ErrHouseNotFound) {
+		utils.WriteError(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if errors.Is(err, models.ErrFlatNumberTaken) || errors.Is(err, models.ErrHouseArchived) {
+		utils.WriteError(c, http.StatusConflict, err.Error())
+		return
+	}
 	if err != nil {
 		c.Header("Retry-After", "30")
 		utils.WriteJSON(c, http.StatusInternalServerError, gin.H{
@@ -87,13 +128,68 @@ func (h *Handler) handleCreateFlat(c *gin.Context) {
 		return
 	}
 
+	middleware.RecordAudit(c.Request.Context(), models.AuditFlatCreate, "flat", flat.Id, nil, flat)
+	if c.GetString("userType") != "moderator" {
+		flat.HideModeration()
+	}
 	utils.WriteJSON(c, http.StatusCreated, flat)
 }
 
+// @Summary Get Flat
+// @Description Get a flat with the summary of its house. Clients see approved flats that are not archived and their own flats in any status; moderators see every flat. Flats the user cannot see are reported as not found. Requires authorization for both moderator and client.
+// @Tags Flat
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Flat ID"
+// @Success 200 {object} models.FlatWithHouse "Flat with its house"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Flat not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /flat/{id} [get]
+func (h *Handler) handleGetFlat(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		utils.WriteError(c, http.StatusBadRequest, "invalid flat id")
+		return
+	}
+
+	flat, err := h.store.GetFlat(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.WriteError(c, http.StatusNotFound, "flat not found")
+		return
+	}
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Hidden flats are reported as missing, so clients cannot probe for them.
+	isModerator := c.GetString("userType") == "moderator"
+	isOwner := flat.OwnerID != nil && *flat.OwnerID == userID
+	listed := flat.Status == models.StatusApproved && flat.ArchivedAt == nil
+	if !isModerator && !isOwner && !listed {
+		utils.WriteError(c, http.StatusNotFound, "flat not found")
+		return
+	}
+
+	house, err := h.store.GetHouseSummary(flat.House_id)
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if !isModerator {
+		flat.HideModeration()
+	}
+	utils.WriteJSON(c, http.StatusOK, models.FlatWithHouse{Flat: flat, House: house})
+}
+
 // handleUpdateFlatStatus updates the status of a flat
 // @Summary Update Flat Status
 // @Tags Flat
-// @Description Update the status of a flat. Requires moderator access.
+// @Description Update the status of a flat. Taking a flat into moderation also returns duplicate_of, the flats of the same house that looked like the same flat when it was created. Approving a flat sets expires_at, after which the flat gets the expired status until its owner renews it. Moderation statuses require moderator access. The owner of an approved or expired flat marks it as rented, for rentals, or sold, for sales, with an optional final_price that defaults to the listed price; such flats are hidden from clients.
 // @Accept json
 // @Produce json
 // @Security Bearer
@@ -101,6 +197,9 @@ func (h *Handler) handleCreateFlat(c *gin.Context) {
 // @Success 200 {object} models.Flat "Flat status updated"
 // @Failure 400 {object} utils.ErrorResponse "Bad request"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Not a moderator, not the assigned moderator or not the owner of the flat"
+// @Failure 404 {object} utils.ErrorResponse "Flat not found"
+// @Failure 409 {object} utils.ErrorResponse "Flat is archived or neither approved nor expired"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /flat/update [post]
 func (h *Handler) handleUpdateFlatStatus(c *gin.Context) {
@@ -135,18 +234,193 @@ func (h *Handler) handleUpdateFlatStatus(c *gin.Context) {
 		return
 	}
 
-	flat, err := h.store.UpdateFlatStatus(userIDUUID, payload)
+	closing := payload.Status == models.StatusRented || payload.Status == models.StatusSold
+	if !closing && c.GetString("userType") != "moderator" {
+		utils.WriteError(c, http.StatusForbidden, "only moderators can moderate flats")
+		return
+	}
+
+	before, err := h.store.GetFlat(payload.Id)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.WriteError(c, http.StatusNotFound, "flat not found")
+		return
+	}
 	if err != nil {
-		c.Header("Retry-After", "30")
-		utils.WriteJSON(c, http.StatusInternalServerError, gin.H{
-			"message":    err.Error(),
-			"request_id": requestId,
-			"code":       http.StatusInternalServerError,
-		})
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	flat, err := h.store.UpdateFlatStatus(userIDUUID, payload)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		utils.WriteError(c, http.StatusNotFound, "flat not found")
+		return
+	case errors.Is(err, models.ErrNotFlatOwner), errors.Is(err, models.ErrNotAssignedModerator):
+		utils.WriteError(c, http.StatusForbidden, err.Error())
+		return
+	case errors.Is(err, models.ErrArchived):
+		utils.WriteError(c, http.StatusConflict, "cannot change the status of an archived flat")
+		return
+	case errors.Is(err, models.ErrNotListed):
+		utils.WriteError(c, http.StatusConflict, err.Error())
+		return
+	case errors.Is(err, models.ErrDealStatus), errors.Is(err, models.ErrInvalidFinalPrice), errors.Is(err, models.ErrInvalidTransition):
+		utils.WriteError(c, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	action := models.AuditFlatModerate
+	if closing {
+		action = models.AuditFlatClose
+	}
+	middleware.RecordAudit(c.Request.Context(), action, "flat", flat.Id, before, flat)
+	if c.GetString("userType") != "moderator" {
+		flat.HideModeration()
+	}
 	utils.WriteJSON(c, http.StatusOK, gin.H{
 		"flat": flat,
 	})
+
+	if flat.Status == models.StatusApproved {
+		go h.notifier.NotifyFlatApproved(flat)
+	}
+}
+
+// @Summary Archive Flat
+// @Description Withdraw a flat from listings. Archived flats are hidden from GET /house/{id} and cannot be moderated. Available to the client who created the flat and to moderators.
+// @Tags Flat
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Flat ID"
+// @Success 200 {object} models.Flat "Flat archived"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Not the owner of the flat"
+// @Failure 404 {object} utils.ErrorResponse "Flat not found"
+// @Failure 409 {object} utils.ErrorResponse "Flat is already archived"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /flat/{id}/archive [post]
+func (h *Handler) handleArchiveFlat(c *gin.Context) {
+	h.setArchived(c, models.AuditFlatArchive, h.store.ArchiveFlat)
+}
+
+// @Summary Restore Flat
+// @Description Return an archived flat to listings with the status it had. Flats of an archived house can only be restored together with the house. Available to the client who created the flat and to moderators.
+// @Tags Flat
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Flat ID"
+// @Success 200 {object} models.Flat "Flat restored"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Not the owner of the flat"
+// @Failure 404 {object} utils.ErrorResponse "Flat not found"
+// @Failure 409 {object} utils.ErrorResponse "Flat is not archived or its house is archived"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /flat/{id}/restore [post]
+func (h *Handler) handleRestoreFlat(c *gin.Context) {
+	h.setArchived(c, models.AuditFlatRestore, h.store.RestoreFlat)
+}
+
+// @Summary Renew Flat
+// @Description Send a flat whose listing expired back to moderation. The flat gets the created status and is listed again once a moderator approves it. Available to the client who created the flat.
+// @Tags Flat
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Flat ID"
+// @Success 200 {object} models.Flat "Flat sent to moderation"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Not the owner of the flat"
+// @Failure 404 {object} utils.ErrorResponse "Flat not found"
+// @Failure 409 {object} utils.ErrorResponse "Listing of the flat has not expired or the flat is archived"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /flat/{id}/renew [post]
+func (h *Handler) handleRenewFlat(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		utils.WriteError(c, http.StatusBadRequest, "invalid flat id")
+		return
+	}
+
+	flat, err := h.store.GetFlat(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.WriteError(c, http.StatusNotFound, "flat not found")
+		return
+	}
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if flat.OwnerID == nil || *flat.OwnerID != userID {
+		utils.WriteError(c, http.StatusForbidden, "only the owner of the flat can renew it")
+		return
+	}
+
+	renewed, err := h.store.RenewFlat(id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		utils.WriteError(c, http.StatusNotFound, "flat not found")
+	case errors.Is(err, models.ErrNotExpired):
+		utils.WriteError(c, http.StatusConflict, err.Error())
+	case errors.Is(err, models.ErrArchived):
+		utils.WriteError(c, http.StatusConflict, "flat is archived")
+	case err != nil:
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+	default:
+		middleware.RecordAudit(c.Request.Context(), models.AuditFlatRenew, "flat", id, flat, renewed)
+		renewed.HideModeration()
+		utils.WriteJSON(c, http.StatusOK, gin.H{"flat": renewed})
+	}
+}
+
+// setArchived checks that the user may manage the flat of the :id path
+// parameter and applies update to it.
+func (h *Handler) setArchived(c *gin.Context, action string, update func(id int) (models.Flat, error)) {
+	userID, _ := c.Get("userID")
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		utils.WriteError(c, http.StatusBadRequest, "invalid flat id")
+		return
+	}
+
+	flat, err := h.store.GetFlat(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.WriteError(c, http.StatusNotFound, "flat not found")
+		return
+	}
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	isOwner := flat.OwnerID != nil && *flat.OwnerID == userID
+	if c.GetString("userType") != "moderator" && !isOwner {
+		utils.WriteError(c, http.StatusForbidden, "only moderators and the owner of the flat can archive or restore it")
+		return
+	}
+
+	updated, err := update(id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		utils.WriteError(c, http.StatusNotFound, "flat not found")
+	case errors.Is(err, models.ErrArchived):
+		utils.WriteError(c, http.StatusConflict, "flat is already archived")
+	case errors.Is(err, models.ErrNotArchived):
+		utils.WriteError(c, http.StatusConflict, "flat is not archived")
+	case errors.Is(err, models.ErrHouseArchived):
+		utils.WriteError(c, http.StatusConflict, "the house of the flat is archived, restore the house first")
+	case err != nil:
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+	default:
+		if c.GetString("userType") != "moderator" {
+			flat.HideModeration()
+			updated.HideModeration()
+		}
+		middleware.RecordAudit(c.Request.Context(), action, "flat", id, flat, updated)
+		utils.WriteJSON(c, http.StatusOK, gin.H{"flat": updated})
+	}
 }
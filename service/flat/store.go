@@ -1,22 +1,58 @@
 package flat
 
 import (
+	"cmp"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/moderation"
+	"github.com/delapaska/avito-rent/utils"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type Store struct {
-	db *sql.DB
+	db    *sql.DB
+	rules moderation.Rules
+	ttl   time.Duration
 }
 
 func NewStore(db *sql.DB) *Store {
 	return &Store{db: db}
 }
+
+// WithRules makes CreateFlat check new flats against rules. Without them
+// every new flat waits for a moderator.
+func (s *Store) WithRules(rules moderation.Rules) *Store {
+	s.rules = rules
+	return s
+}
+
+// WithListingTTL makes approved flats expire ttl after approval. Without it
+// they stay listed until archived.
+func (s *Store) WithListingTTL(ttl time.Duration) *Store {
+	s.ttl = ttl
+	return s
+}
+
+// A flat is taken for a duplicate of a flat of the same house with as many
+// rooms when their prices and, if both are known, their total areas differ by
+// no more than these percentages.
+const (
+	duplicatePriceSpread = 10
+	duplicateAreaSpread  = 5
+	maxDuplicates        = 5
+)
+
+// flatColumns are the columns scanFlat reads.
+const flatColumns = `id, house_id, price, rooms, status, owner_id, archived_at, number, floor, total_area, living_area, balcony,
+	listing_type, COALESCE(rent_period, ''), deposit, commission, utilities_included, price_per_m2,
+	COALESCE(description, ''), high_risk, rule_hits, COALESCE(decline_reason, ''), expires_at, final_price, closed_at`
+
 func (s *Store) CreateFlat(flat models.Flat) (models.Flat, error) {
 
 	tx, err := s.db.Begin()
@@ -26,24 +62,60 @@ func (s *Store) CreateFlat(flat models.Flat) (models.Flat, error) {
 	}
 	defer tx.Rollback()
 
+	// The house is locked so that it cannot be archived before the flat is added.
+	var floors sql.NullInt64
+	var houseArchived bool
+	queryHouse := `SELECT floors, archived_at IS NOT NULL FROM house WHERE id = $1 FOR SHARE`
+	err = tx.QueryRow(queryHouse, flat.House_id).Scan(&floors, &houseArchived)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Flat{}, models.ErrHouseNotFound
+	}
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return models.Flat{}, err
+	}
+	if houseArchived {
+		return models.Flat{}, models.ErrHouseArchived
+	}
+	if flat.Floor != nil && floors.Valid && int64(*flat.Floor) > floors.Int64 {
+		return models.Flat{}, models.ErrFloorAboveHouse
+	}
+
+	verdict, err := s.premoderate(tx, flat)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return models.Flat{}, err
+	}
+	status, declineReason := models.StatusCreated, ""
+	if verdict.Action == models.RuleActionDecline {
+		status, declineReason = models.StatusDeclined, verdict.Reason()
+	}
+
 	queryInsert := `
-		INSERT INTO flat (house_id, price, rooms, status)
-		VALUES ($1, $2, $3, 'created')
-		RETURNING id, house_id, price, rooms, status`
-
-	var insertedFlat models.Flat
-	err = tx.QueryRow(queryInsert, flat.House_id, flat.Price, flat.Rooms).Scan(
-		&insertedFlat.Id,
-		&insertedFlat.House_id,
-		&insertedFlat.Price,
-		&insertedFlat.Rooms,
-		&insertedFlat.Status,
-	)
+		INSERT INTO flat (house_id, price, rooms, status, owner_id, number, floor, total_area, living_area, balcony,
+			listing_type, rent_period, deposit, commission, utilities_included, description, high_risk, rule_hits, decline_reason)
+		VALUES ($1, $2, $3, $15, $4, $5, $6, $7, $8, $9, COALESCE(NULLIF($10, ''), 'sale'), NULLIF($11, ''), $12, $13, $14,
+			NULLIF($16, ''), $17, $18, NULLIF($19, ''))
+		RETURNING ` + flatColumns
+
+	insertedFlat, err := scanFlat(tx.QueryRow(queryInsert, flat.House_id, flat.Price, flat.Rooms, flat.OwnerID,
+		flat.Number, flat.Floor, flat.TotalArea, flat.LivingArea, flat.Balcony,
+		flat.ListingType, flat.RentPeriod, flat.Deposit, flat.Commission, flat.UtilitiesIncluded,
+		status, flat.Description, verdict.Action == models.RuleActionHighRisk, verdict.Hits, declineReason))
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return models.Flat{}, models.ErrFlatNumberTaken
+	}
 	if err != nil {
 		log.Printf("Error executing insert query: %v\n", err)
 		return models.Flat{}, err
 	}
 
+	if err := flagDuplicates(tx, insertedFlat); err != nil {
+		log.Printf("Error executing insert query: %v\n", err)
+		return models.Flat{}, err
+	}
+
 	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
 
 	queryUpdateHouse := `
@@ -73,22 +145,56 @@ func (s *Store) UpdateFlatStatus(userID uuid.UUID, flat models.UpdateStatusPaylo
 	}
 	defer tx.Rollback()
 
-	var currentStatus string
+	var currentStatus, listingType string
 	var currentModeratorID uuid.UUID
+	var ownerID uuid.NullUUID
+	var price int
+	var archived bool
 	queryGetStatus := `
-		SELECT status, moderator_id
+		SELECT status, moderator_id, archived_at IS NOT NULL, owner_id, listing_type, price
 		FROM flat
 		WHERE id = $1
 		FOR UPDATE`
-	err = tx.QueryRow(queryGetStatus, flat.Id).Scan(&currentStatus, &currentModeratorID)
+	err = tx.QueryRow(queryGetStatus, flat.Id).Scan(&currentStatus, &currentModeratorID, &archived, &ownerID, &listingType, &price)
 	if err != nil {
 		log.Printf("Error fetching current status: %v\n", err)
 		return models.Flat{}, err
 	}
+	if archived {
+		return models.Flat{}, models.ErrArchived
+	}
 
-	if flat.Status == models.StatusOnModeration {
+	if flat.Status == models.StatusRented || flat.Status == models.StatusSold {
+		// Closing a deal is up to the owner, not to moderators.
+		if !ownerID.Valid || ownerID.UUID != userID {
+			return models.Flat{}, models.ErrNotFlatOwner
+		}
+		if currentStatus != models.StatusApproved && currentStatus != models.StatusExpired {
+			return models.Flat{}, models.ErrNotListed
+		}
+		if (flat.Status == models.StatusRented) != (listingType == models.ListingRent) {
+			return models.Flat{}, models.ErrDealStatus
+		}
+		finalPrice := price
+		if flat.FinalPrice != nil {
+			if *flat.FinalPrice <= 0 {
+				return models.Flat{}, models.ErrInvalidFinalPrice
+			}
+			finalPrice = *flat.FinalPrice
+		}
+		currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+		queryClose := `
+			UPDATE flat
+			SET status = $1, final_price = $2, closed_at = $3, expires_at = NULL
+			WHERE id = $4`
+		_, err = tx.Exec(queryClose, flat.Status, finalPrice, currentTime, flat.Id)
+		if err != nil {
+			log.Printf("Error executing update query: %v\n", err)
+			return models.Flat{}, err
+		}
+	} else if flat.Status == models.StatusOnModeration {
 		if currentStatus != models.StatusCreated {
-			return models.Flat{}, fmt.Errorf("cannot put flat into moderation from status %s", currentStatus)
+			return models.Flat{}, fmt.Errorf("%w: cannot put flat into moderation from status %s", models.ErrInvalidTransition, currentStatus)
 		}
 		updateModeratorQuery := `
 			UPDATE flat
@@ -101,19 +207,24 @@ func (s *Store) UpdateFlatStatus(userID uuid.UUID, flat models.UpdateStatusPaylo
 		}
 	} else {
 		if currentStatus != models.StatusOnModeration {
-			return models.Flat{}, fmt.Errorf("flat must be in status 'on moderation' to be approved or declined")
+			return models.Flat{}, fmt.Errorf("%w: flat must be in status 'on moderation' to be approved or declined", models.ErrInvalidTransition)
 		}
 		if flat.Status != models.StatusApproved && flat.Status != models.StatusDeclined {
-			return models.Flat{}, fmt.Errorf("invalid status change: %s", flat.Status)
+			return models.Flat{}, fmt.Errorf("%w: %s", models.ErrInvalidTransition, flat.Status)
 		}
 		if currentModeratorID != userID {
-			return models.Flat{}, fmt.Errorf("only the assigned moderator can change the status")
+			return models.Flat{}, models.ErrNotAssignedModerator
+		}
+		var expiresAt *time.Time
+		if flat.Status == models.StatusApproved && s.ttl > 0 {
+			t := time.Now().UTC().Add(s.ttl)
+			expiresAt = &t
 		}
 		queryUpdate := `
 			UPDATE flat
-			SET status = $1
+			SET status = $1, expires_at = $3, expiry_reminder_sent_at = NULL
 			WHERE id = $2`
-		_, err = tx.Exec(queryUpdate, flat.Status, flat.Id)
+		_, err = tx.Exec(queryUpdate, flat.Status, flat.Id, expiresAt)
 		if err != nil {
 			log.Printf("Error executing update query: %v\n", err)
 			return models.Flat{}, err
@@ -125,22 +236,339 @@ func (s *Store) UpdateFlatStatus(userID uuid.UUID, flat models.UpdateStatusPaylo
 		return models.Flat{}, err
 	}
 
-	var updatedFlat models.Flat
-	queryGetUpdatedFlat := `
-		SELECT id, house_id, price, rooms, status
-		FROM flat
-		WHERE id = $1`
-	err = s.db.QueryRow(queryGetUpdatedFlat, flat.Id).Scan(
-		&updatedFlat.Id,
-		&updatedFlat.House_id,
-		&updatedFlat.Price,
-		&updatedFlat.Rooms,
-		&updatedFlat.Status,
-	)
+	updatedFlat, err := s.GetFlat(flat.Id)
 	if err != nil {
 		log.Printf("Error fetching updated flat: %v\n", err)
 		return models.Flat{}, err
 	}
 
+	if updatedFlat.Status == models.StatusOnModeration {
+		updatedFlat.DuplicateOf, err = s.getDuplicates(flat.Id)
+		if err != nil {
+			log.Printf("Error fetching duplicates: %v\n", err)
+			return models.Flat{}, err
+		}
+	}
+
 	return updatedFlat, nil
 }
+
+// premoderate checks flat against the rules of the store. The median price
+// is taken over the approved active flats of the house listed the same way.
+func (s *Store) premoderate(tx *sql.Tx, flat models.Flat) (moderation.Verdict, error) {
+	var median float64
+	var comparables int
+	if s.rules.ChecksPrice() {
+		query := `
+			SELECT COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY price), 0), COUNT(*)
+			FROM flat
+			WHERE house_id = $1 AND listing_type = $2 AND COALESCE(rent_period, '') = $3
+				AND status = 'approved' AND archived_at IS NULL`
+		listingType := cmp.Or(flat.ListingType, models.ListingSale)
+		if err := tx.QueryRow(query, flat.House_id, listingType, flat.RentPeriod).Scan(&median, &comparables); err != nil {
+			return moderation.Verdict{}, err
+		}
+	}
+	return s.rules.Check(flat, median, comparables), nil
+}
+
+// flagDuplicates records the active flats of the house that look like flat.
+// Flats that both have a number are told apart by it, and floors only have
+// to match when both are known.
+func flagDuplicates(tx *sql.Tx, flat models.Flat) error {
+	priceDelta := flat.Price * duplicatePriceSpread / 100
+	var areaMin, areaMax *float64
+	if flat.TotalArea != nil {
+		areaDelta := *flat.TotalArea * duplicateAreaSpread / 100
+		lo, hi := *flat.TotalArea-areaDelta, *flat.TotalArea+areaDelta
+		areaMin, areaMax = &lo, &hi
+	}
+
+	query := `
+		INSERT INTO flat_duplicates (flat_id, duplicate_id)
+		SELECT $1, id
+		FROM flat
+		WHERE house_id = $2 AND id <> $1 AND archived_at IS NULL
+			AND rooms = $3 AND listing_type = $4
+			AND price BETWEEN $5 AND $6
+			AND (number IS NULL OR $7::int IS NULL)
+			AND (floor IS NULL OR $8::int IS NULL OR floor = $8)
+			AND (total_area IS NULL OR $9::numeric IS NULL OR total_area BETWEEN $9 AND $10)
+		ORDER BY id DESC
+		LIMIT $11`
+	_, err := tx.Exec(query, flat.Id, flat.House_id, flat.Rooms, flat.ListingType, flat.Price-priceDelta, flat.Price+priceDelta,
+		flat.Number, flat.Floor, areaMin, areaMax, maxDuplicates)
+	return err
+}
+
+// getDuplicates returns the ids of the flats flagDuplicates found for the flat.
+func (s *Store) getDuplicates(id int) ([]int, error) {
+	rows, err := s.db.Query(`SELECT duplicate_id FROM flat_duplicates WHERE flat_id = $1 ORDER BY duplicate_id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var duplicateID int
+		if err := rows.Scan(&duplicateID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, duplicateID)
+	}
+	return ids, rows.Err()
+}
+
+func (s *Store) GetFlat(id int) (models.Flat, error) {
+	query := `
+		SELECT ` + flatColumns + `
+		FROM flat
+		WHERE id = $1`
+	return scanFlat(s.db.QueryRow(query, id))
+}
+
+// GetHouseSummary returns the summary of the house with the given id.
+func (s *Store) GetHouseSummary(id int) (models.HouseSummary, error) {
+	query := `
+		SELECT h.id, h.address, h.year, COALESCE(d.name, ''), h.floors, COALESCE(h.material, '')
+		FROM house h
+		LEFT JOIN developers d ON d.id = h.developer_id
+		WHERE h.id = $1`
+
+	var house models.HouseSummary
+	err := s.db.QueryRow(query, id).Scan(&house.Id, &house.Address, &house.Year, &house.Developer, &house.Floors, &house.Material)
+	return house, err
+}
+
+// ArchiveFlat withdraws the flat from listings. It returns models.ErrArchived
+// when the flat is already archived.
+func (s *Store) ArchiveFlat(id int) (models.Flat, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v\n", err)
+		return models.Flat{}, err
+	}
+	defer tx.Rollback()
+
+	var archived bool
+	if err := tx.QueryRow(`SELECT archived_at IS NOT NULL FROM flat WHERE id = $1 FOR UPDATE`, id).Scan(&archived); err != nil {
+		return models.Flat{}, err
+	}
+	if archived {
+		return models.Flat{}, models.ErrArchived
+	}
+
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	return setFlatArchivedAt(tx, id, currentTime)
+}
+
+// RestoreFlat returns an archived flat to listings. Flats of an archived
+// house return models.ErrHouseArchived; the house has to be restored first.
+func (s *Store) RestoreFlat(id int) (models.Flat, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v\n", err)
+		return models.Flat{}, err
+	}
+	defer tx.Rollback()
+
+	var archived, houseArchived bool
+	query := `
+		SELECT f.archived_at IS NOT NULL, h.archived_at IS NOT NULL
+		FROM flat f
+		JOIN house h ON h.id = f.house_id
+		WHERE f.id = $1
+		FOR UPDATE OF f`
+	if err := tx.QueryRow(query, id).Scan(&archived, &houseArchived); err != nil {
+		return models.Flat{}, err
+	}
+	if !archived {
+		return models.Flat{}, models.ErrNotArchived
+	}
+	if houseArchived {
+		return models.Flat{}, models.ErrHouseArchived
+	}
+
+	return setFlatArchivedAt(tx, id, nil)
+}
+
+// RenewFlat sends an expired flat back to moderation. It returns
+// models.ErrNotExpired for flats in any other status and models.ErrArchived
+// for archived ones.
+func (s *Store) RenewFlat(id int) (models.Flat, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v\n", err)
+		return models.Flat{}, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	var archived bool
+	query := `SELECT status, archived_at IS NOT NULL FROM flat WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRow(query, id).Scan(&status, &archived); err != nil {
+		return models.Flat{}, err
+	}
+	if archived {
+		return models.Flat{}, models.ErrArchived
+	}
+	if status != models.StatusExpired {
+		return models.Flat{}, models.ErrNotExpired
+	}
+
+	queryUpdate := `
+		UPDATE flat
+		SET status = $1, moderator_id = NULL, expires_at = NULL, expiry_reminder_sent_at = NULL
+		WHERE id = $2
+		RETURNING ` + flatColumns
+
+	flat, err := scanFlat(tx.QueryRow(queryUpdate, models.StatusCreated, id))
+	if err != nil {
+		log.Printf("Error executing update query: %v\n", err)
+		return models.Flat{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v\n", err)
+		return models.Flat{}, err
+	}
+
+	return flat, nil
+}
+
+// ExpireFlats moves the approved flats whose listing expired by now to
+// models.StatusExpired and returns them.
+func (s *Store) ExpireFlats(now time.Time) ([]models.Flat, error) {
+	query := `
+		UPDATE flat
+		SET status = $1
+		WHERE status = $2 AND expires_at <= $3 AND archived_at IS NULL
+		RETURNING ` + flatColumns
+
+	rows, err := s.db.Query(query, models.StatusExpired, models.StatusApproved, now)
+	if err != nil {
+		log.Printf("Error executing update query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flats []models.Flat
+	for rows.Next() {
+		flat, err := scanFlat(rows)
+		if err != nil {
+			return nil, err
+		}
+		flats = append(flats, flat)
+	}
+	return flats, rows.Err()
+}
+
+// ScheduleExpiry gives the approved flats without an expiry date the listing
+// TTL from now and returns how many there were. Such flats were approved
+// while the TTL was off; the flat-expiration migration already gave the flats
+// approved before it 30 days. Without a TTL it does nothing.
+func (s *Store) ScheduleExpiry(now time.Time) (int64, error) {
+	if s.ttl <= 0 {
+		return 0, nil
+	}
+	query := `
+		UPDATE flat
+		SET expires_at = $1
+		WHERE status = $2 AND expires_at IS NULL`
+
+	result, err := s.db.Exec(query, now.Add(s.ttl), models.StatusApproved)
+	if err != nil {
+		log.Printf("Error executing update query: %v\n", err)
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ClaimExpiryReminders marks the approved flats expiring between from and to
+// as reminded and returns them with the emails of their owners. Flats without
+// an owner are skipped. A flat is claimed once per approval, so running the
+// reminders on every instance sends each of them once.
+func (s *Store) ClaimExpiryReminders(from, to time.Time) ([]models.ExpiringFlat, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE flat
+			SET expiry_reminder_sent_at = $1
+			WHERE status = 'approved' AND archived_at IS NULL AND expiry_reminder_sent_at IS NULL
+				AND expires_at > $1 AND expires_at <= $2
+				AND owner_id IS NOT NULL
+			RETURNING *
+		)
+		SELECT ` + flatColumns + `, u.email
+		FROM claimed
+		JOIN users u ON u.user_id = claimed.owner_id
+		ORDER BY expires_at`
+
+	rows, err := s.db.Query(query, from, to)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flats []models.ExpiringFlat
+	for rows.Next() {
+		var email string
+		flat, err := scanFlat(rows, &email)
+		if err != nil {
+			return nil, err
+		}
+		flats = append(flats, models.ExpiringFlat{Flat: flat, Email: email})
+	}
+	return flats, rows.Err()
+}
+
+// scanFlat reads flatColumns and then extra, the columns selected after them.
+func scanFlat(row utils.Scanner, extra ...any) (models.Flat, error) {
+	var flat models.Flat
+	var ownerID uuid.NullUUID
+	var archivedAt, expiresAt, closedAt sql.NullTime
+	dest := []any{&flat.Id, &flat.House_id, &flat.Price, &flat.Rooms, &flat.Status, &ownerID, &archivedAt,
+		&flat.Number, &flat.Floor, &flat.TotalArea, &flat.LivingArea, &flat.Balcony,
+		&flat.ListingType, &flat.RentPeriod, &flat.Deposit, &flat.Commission, &flat.UtilitiesIncluded, &flat.PricePerM2,
+		&flat.Description, &flat.HighRisk, &flat.RuleHits, &flat.DeclineReason, &expiresAt,
+		&flat.FinalPrice, &closedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return models.Flat{}, err
+	}
+	if ownerID.Valid {
+		flat.OwnerID = &ownerID.UUID
+	}
+	if archivedAt.Valid {
+		flat.ArchivedAt = &archivedAt.Time
+	}
+	if expiresAt.Valid {
+		flat.ExpiresAt = &expiresAt.Time
+	}
+	if closedAt.Valid {
+		flat.ClosedAt = &closedAt.Time
+	}
+	return flat, nil
+}
+
+// setFlatArchivedAt updates archived_at of the flat and commits tx.
+func setFlatArchivedAt(tx *sql.Tx, id int, archivedAt any) (models.Flat, error) {
+	query := `
+		UPDATE flat
+		SET archived_at = $1
+		WHERE id = $2
+		RETURNING ` + flatColumns
+
+	flat, err := scanFlat(tx.QueryRow(query, archivedAt, id))
+	if err != nil {
+		log.Printf("Error executing update query: %v\n", err)
+		return models.Flat{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v\n", err)
+		return models.Flat{}, err
+	}
+
+	return flat, nil
+}
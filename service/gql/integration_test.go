@@ -0,0 +1,266 @@
+package gql
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/delapaska/avito-rent/notification"
+	"github.com/delapaska/avito-rent/service/auth"
+	"github.com/delapaska/avito-rent/service/flat"
+	"github.com/delapaska/avito-rent/service/house"
+	"github.com/delapaska/avito-rent/service/search"
+	"github.com/delapaska/avito-rent/service/viewing"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleGraphQL(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	houseStore := house.NewStore(db)
+	handler := NewHandler(houseStore, flat.NewStore(db), auth.NewStore(db), notification.NewNotifier(houseStore, search.NewStore(db), viewing.NewStore(db)))
+
+	newRouter := func(userType string) *gin.Engine {
+		r := gin.Default()
+		r.POST("/graphql", func(c *gin.Context) {
+			c.Set("userID", uuid.New())
+			c.Set("userType", userType)
+		}, handler.handleGraphQL)
+		return r
+	}
+
+	post := func(r *gin.Engine, body string) map[string]interface{} {
+		req, err := http.NewRequest("POST", "/graphql", bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var response map[string]interface{}
+		if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+			t.Fatalf("error decoding response: %v", err)
+		}
+		return response
+	}
+
+	flatColumns := []string{"id", "house_id", "price", "rooms", "status", "number", "floor", "total_area", "living_area", "balcony",
+		"price_per_m2", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "expires_at", "final_price"}
+	statsColumns := []string{"house_id", "flats", "created", "on_moderation", "approved", "declined", "expired", "rented", "sold",
+		"min_price", "max_price", "avg_price", "min_price_per_m2", "max_price_per_m2", "avg_price_per_m2", "avg_sold_price", "avg_monthly_rent"}
+	// Flats and stats are loaded by separate loaders, in no particular order.
+	mock.MatchExpectationsInOrder(false)
+
+	t.Run("should batch flats of several houses into one query", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery(`SELECT h.id, h.address, h.year, COALESCE\(d.name, ''\), h.developer_id, h.floors, COALESCE\(h.material, ''\), h.elevator, h.parking, h.created_at, h.updated_at, h.archived_at FROM house h LEFT JOIN developers d ON d.id = h.developer_id WHERE h.id = ANY\(\$1\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "address", "year", "developer", "developer_id", "floors", "material", "elevator", "parking", "created_at", "updated_at", "archived_at"}).
+				AddRow(1, "Лесная улица, 7", 2003, "ПИК", 3, 9, "panel", true, false, now, now, nil).
+				AddRow(2, "Тверская улица, 1", 1990, "", nil, nil, "", nil, nil, now, now, nil))
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, number, floor, total_area, living_area, balcony, price_per_m2, listing_type, COALESCE\(rent_period, ''\), deposit, commission, utilities_included, expires_at, final_price FROM flat WHERE house_id = ANY\(\$1\) AND archived_at IS NULL AND status = 'approved' ORDER BY id`).
+			WillReturnRows(sqlmock.NewRows(flatColumns).
+				AddRow(1, 1, 100000, 3, "approved", 12, 4, []byte("80.00"), []byte("52.50"), true, []byte("1250.00"), "sale", "", nil, nil, nil, nil, nil).
+				AddRow(2, 1, 200000, 4, "approved", nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, nil).
+				AddRow(3, 2, 150000, 2, "approved", nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, nil))
+		mock.ExpectQuery(`SELECT house_id, COUNT\(\*\), .* FROM flat WHERE house_id = ANY\(\$1\) AND archived_at IS NULL GROUP BY house_id`).
+			WillReturnRows(sqlmock.NewRows(statsColumns).
+				AddRow(1, 3, 1, 0, 2, 0, 0, 0, 0, 100000, 200000, 150000.0, []byte("1250.00"), []byte("1250.00"), []byte("1250.00"), nil, nil).
+				AddRow(2, 1, 0, 0, 1, 0, 0, 0, 0, 150000, 150000, 150000.0, nil, nil, nil, nil, nil))
+
+		response := post(newRouter("client"), `{"query":"{ houses(ids: [\"1\", \"2\"]) { id floors material elevator flats { id status number floor totalArea livingArea balcony pricePerM2 } stats { flats approved avgPrice avgPricePerM2 } } }"}`)
+		assert.Nil(t, response["errors"])
+
+		houses := response["data"].(map[string]interface{})["houses"].([]interface{})
+		assert.Len(t, houses, 2)
+
+		first := houses[0].(map[string]interface{})
+		assert.Equal(t, "1", first["id"])
+		assert.Equal(t, float64(9), first["floors"])
+		assert.Equal(t, "PANEL", first["material"])
+		assert.Equal(t, true, first["elevator"])
+		assert.Len(t, first["flats"], 2)
+		flat := first["flats"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "APPROVED", flat["status"])
+		assert.Equal(t, float64(12), flat["number"])
+		assert.Equal(t, float64(4), flat["floor"])
+		assert.Equal(t, 80.0, flat["totalArea"])
+		assert.Equal(t, 52.5, flat["livingArea"])
+		assert.Equal(t, true, flat["balcony"])
+		stats := first["stats"].(map[string]interface{})
+		assert.Equal(t, float64(3), stats["flats"])
+		assert.Equal(t, float64(2), stats["approved"])
+		assert.Equal(t, float64(150000), stats["avgPrice"])
+		assert.Equal(t, 1250.0, stats["avgPricePerM2"])
+		assert.Nil(t, first["flats"].([]interface{})[1].(map[string]interface{})["pricePerM2"])
+
+		second := houses[1].(map[string]interface{})
+		assert.Nil(t, second["floors"])
+		assert.Nil(t, second["material"])
+		assert.Len(t, second["flats"], 1)
+		assert.Nil(t, second["stats"].(map[string]interface{})["avgPricePerM2"])
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("should count closed deals in the stats of moderators", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery(`FROM house h LEFT JOIN developers d ON d.id = h.developer_id WHERE h.id = ANY\(\$1\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "address", "year", "developer", "developer_id", "floors", "material", "elevator", "parking", "created_at", "updated_at", "archived_at"}).
+				AddRow(1, "Лесная улица, 7", 2003, "", nil, nil, "", nil, nil, now, now, nil))
+		mock.ExpectQuery(`FROM flat WHERE house_id = ANY\(\$1\) AND archived_at IS NULL ORDER BY id`).
+			WillReturnRows(sqlmock.NewRows(flatColumns).
+				AddRow(1, 1, 100000, 3, "sold", nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, 90000).
+				AddRow(2, 1, 200000, 4, "sold", nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, 210000).
+				AddRow(3, 1, 40000, 2, "rented", nil, nil, nil, nil, nil, nil, "rent", "monthly", nil, nil, nil, nil, 38000).
+				AddRow(4, 1, 3000, 1, "rented", nil, nil, nil, nil, nil, nil, "rent", "daily", nil, nil, nil, nil, 2500))
+		mock.ExpectQuery(`FROM flat WHERE house_id = ANY\(\$1\) AND archived_at IS NULL GROUP BY house_id`).
+			WillReturnRows(sqlmock.NewRows(statsColumns).
+				AddRow(1, 4, 0, 0, 0, 0, 0, 2, 2, nil, nil, nil, nil, nil, nil, []byte("150000.0000000000000000"), []byte("38000.0000000000000000")))
+
+		response := post(newRouter("moderator"), `{"query":"{ house(id: \"1\") { flats { status finalPrice } stats { rented sold avgSoldPrice avgMonthlyRent } } }"}`)
+		assert.Nil(t, response["errors"])
+
+		house := response["data"].(map[string]interface{})["house"].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{"status": "SOLD", "finalPrice": float64(90000)}, house["flats"].([]interface{})[0])
+		assert.Equal(t, map[string]interface{}{"rented": float64(2), "sold": float64(2), "avgSoldPrice": float64(150000), "avgMonthlyRent": float64(38000)}, house["stats"])
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("should count flats clients cannot see in their stats", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery(`FROM house h LEFT JOIN developers d ON d.id = h.developer_id WHERE h.id = ANY\(\$1\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "address", "year", "developer", "developer_id", "floors", "material", "elevator", "parking", "created_at", "updated_at", "archived_at"}).
+				AddRow(1, "Лесная улица, 7", 2003, "", nil, nil, "", nil, nil, now, now, nil))
+		mock.ExpectQuery(`FROM flat WHERE house_id = ANY\(\$1\) AND archived_at IS NULL AND status = 'approved' ORDER BY id`).
+			WillReturnRows(sqlmock.NewRows(flatColumns))
+		mock.ExpectQuery(`FROM flat WHERE house_id = ANY\(\$1\) AND archived_at IS NULL GROUP BY house_id`).
+			WillReturnRows(sqlmock.NewRows(statsColumns).
+				AddRow(1, 3, 1, 0, 0, 0, 0, 1, 1, nil, nil, nil, nil, nil, nil, []byte("90000.0000000000000000"), []byte("38000.0000000000000000")))
+
+		response := post(newRouter("client"), `{"query":"{ house(id: \"1\") { flats { id } stats { flats created rented sold avgPrice avgSoldPrice avgMonthlyRent } } }"}`)
+		assert.Nil(t, response["errors"])
+
+		house := response["data"].(map[string]interface{})["house"].(map[string]interface{})
+		assert.Empty(t, house["flats"])
+		assert.Equal(t, map[string]interface{}{"flats": float64(3), "created": float64(1), "rented": float64(1), "sold": float64(1),
+			"avgPrice": nil, "avgSoldPrice": float64(90000), "avgMonthlyRent": float64(38000)}, house["stats"])
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("should filter flats by listing type", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery(`FROM house h LEFT JOIN developers d ON d.id = h.developer_id WHERE h.id = ANY\(\$1\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "address", "year", "developer", "developer_id", "floors", "material", "elevator", "parking", "created_at", "updated_at", "archived_at"}).
+				AddRow(1, "Лесная улица, 7", 2003, "", nil, nil, "", nil, nil, now, now, nil))
+		mock.ExpectQuery(`FROM flat WHERE house_id = ANY\(\$1\) AND archived_at IS NULL AND status = 'approved' ORDER BY id`).
+			WillReturnRows(sqlmock.NewRows(flatColumns).
+				AddRow(1, 1, 10000000, 3, "approved", nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, nil).
+				AddRow(2, 1, 45000, 2, "approved", nil, nil, nil, nil, nil, nil, "rent", "monthly", 45000, 50, true, nil, nil))
+		mock.ExpectQuery(`FROM flat WHERE house_id = ANY\(\$1\) AND archived_at IS NULL GROUP BY house_id`).
+			WillReturnRows(sqlmock.NewRows(statsColumns).
+				AddRow(1, 2, 0, 0, 2, 0, 0, 0, 0, 45000, 10000000, 5022500.0, nil, nil, nil, nil, nil))
+
+		response := post(newRouter("client"), `{"query":"{ house(id: \"1\") { flats(listingType: RENT) { id listingType rentPeriod deposit commission utilitiesIncluded } stats { flats } } }"}`)
+		assert.Nil(t, response["errors"])
+
+		house := response["data"].(map[string]interface{})["house"].(map[string]interface{})
+		assert.Equal(t, []interface{}{map[string]interface{}{
+			"id": "2", "listingType": "RENT", "rentPeriod": "MONTHLY", "deposit": float64(45000), "commission": float64(50), "utilitiesIncluded": true,
+		}}, house["flats"])
+		assert.Equal(t, map[string]interface{}{"flats": float64(2)}, house["stats"])
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("should only show clients their own subscription", func(t *testing.T) {
+		now := time.Now()
+		houseColumns := []string{"id", "address", "year", "developer", "developer_id", "floors", "material", "elevator", "parking", "created_at", "updated_at", "archived_at"}
+		userColumns := []string{"user_id", "email", "password", "user_type"}
+		subscriptionColumns := []string{"id", "house_id", "email", "locale", "delivery", "created_at"}
+
+		mock.ExpectQuery(`FROM house h LEFT JOIN developers d ON d.id = h.developer_id WHERE h.id = ANY\(\$1\)`).
+			WillReturnRows(sqlmock.NewRows(houseColumns).AddRow(1, "Лесная улица, 7", 2003, "", nil, nil, "", nil, nil, now, now, nil))
+		mock.ExpectQuery(`SELECT user_id, email, password, user_type FROM users WHERE user_id = \$1`).
+			WillReturnRows(sqlmock.NewRows(userColumns).AddRow(uuid.New(), "client@example.com", "hash", "client"))
+
+		response := post(newRouter("client"), `{"query":"{ house(id: \"1\") { subscription(email: \"someone@example.com\") { email } } }"}`)
+		assert.Nil(t, response["errors"])
+		assert.Equal(t, map[string]interface{}{"subscription": nil}, response["data"].(map[string]interface{})["house"])
+
+		mock.ExpectQuery(`FROM house h LEFT JOIN developers d ON d.id = h.developer_id WHERE h.id = ANY\(\$1\)`).
+			WillReturnRows(sqlmock.NewRows(houseColumns).AddRow(1, "Лесная улица, 7", 2003, "", nil, nil, "", nil, nil, now, now, nil))
+		mock.ExpectQuery(`FROM subscriptions WHERE email = \$1 AND house_id = ANY\(\$2\)`).
+			WithArgs("someone@example.com", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows(subscriptionColumns).AddRow(1, 1, "someone@example.com", "ru", "instant", now))
+
+		response = post(newRouter("moderator"), `{"query":"{ house(id: \"1\") { subscription(email: \"someone@example.com\") { email } } }"}`)
+		assert.Nil(t, response["errors"])
+		assert.Equal(t, map[string]interface{}{"subscription": map[string]interface{}{"email": "someone@example.com"}}, response["data"].(map[string]interface{})["house"])
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unmet expectations: %v", err)
+		}
+	})
+
+	t.Run("should reject createHouse for clients", func(t *testing.T) {
+		response := post(newRouter("client"), `{"query":"mutation { createHouse(input: {address: \"Лесная улица, 7\", year: 2003}) { id } }"}`)
+
+		errs := response["errors"].([]interface{})
+		assert.Len(t, errs, 1)
+		assert.Contains(t, errs[0].(map[string]interface{})["message"], "not enough rights")
+	})
+
+	t.Run("should reject a living area larger than the total area", func(t *testing.T) {
+		response := post(newRouter("client"), `{"query":"mutation { createFlat(input: {houseId: \"1\", price: 100000, rooms: 2, totalArea: 40, livingArea: 45}) { id } }"}`)
+
+		errs := response["errors"].([]interface{})
+		assert.Len(t, errs, 1)
+		assert.Contains(t, errs[0].(map[string]interface{})["message"], "living_area cannot be larger than total_area")
+	})
+
+	t.Run("should check the terms of rentals and sales", func(t *testing.T) {
+		for input, message := range map[string]string{
+			`listingType: RENT`:                    "rent_period is required for rentals",
+			`deposit: 45000`:                       "only allowed for rentals",
+			`listingType: SALE, rentPeriod: DAILY`: "only allowed for rentals",
+		} {
+			response := post(newRouter("client"), `{"query":"mutation { createFlat(input: {houseId: \"1\", price: 45000, rooms: 2, `+input+`}) { id } }"}`)
+
+			errs := response["errors"].([]interface{})
+			assert.Len(t, errs, 1)
+			assert.Contains(t, errs[0].(map[string]interface{})["message"], message)
+		}
+	})
+
+	t.Run("should return bad request without query", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/graphql", bytes.NewBufferString(`{}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		recorder := httptest.NewRecorder()
+		newRouter("client").ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
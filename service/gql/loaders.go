@@ -0,0 +1,147 @@
+package gql
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/delapaska/avito-rent/models"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+type subscriptionKey struct {
+	houseID int
+	email   string
+}
+
+// loaders batch the lookups made by field resolvers within a single request,
+// so a query over N houses costs one query per relation instead of N.
+type loaders struct {
+	houses        *dataloader.Loader[int, *models.House]
+	flats         *dataloader.Loader[int, []models.Flat]
+	stats         *dataloader.Loader[int, *models.HouseStats]
+	subscriptions *dataloader.Loader[subscriptionKey, *models.Subscription]
+
+	// currentEmail resolves the email of the requesting user once per request.
+	currentEmail func() (string, error)
+}
+
+func newLoaders(store models.HouseStore, users models.UserStore, u user) *loaders {
+	return &loaders{
+		houses:        dataloader.NewBatchedLoader(batchHouses(store)),
+		flats:         dataloader.NewBatchedLoader(batchFlats(store, u.userType)),
+		stats:         dataloader.NewBatchedLoader(batchStats(store)),
+		subscriptions: dataloader.NewBatchedLoader(batchSubscriptions(store)),
+		currentEmail: sync.OnceValues(func() (string, error) {
+			found, err := users.GetUserById(u.userID)
+			if err != nil {
+				return "", err
+			}
+			return found.Email, nil
+		}),
+	}
+}
+
+func batchHouses(store models.HouseStore) dataloader.BatchFunc[int, *models.House] {
+	return func(ctx context.Context, ids []int) []*dataloader.Result[*models.House] {
+		results := make([]*dataloader.Result[*models.House], len(ids))
+
+		houses, err := store.GetHousesByIDs(ids)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[*models.House]{Error: err}
+			}
+			return results
+		}
+
+		byID := make(map[int]*models.House, len(houses))
+		for i := range houses {
+			byID[houses[i].Id] = &houses[i]
+		}
+		for i, id := range ids {
+			results[i] = &dataloader.Result[*models.House]{Data: byID[id]}
+		}
+		return results
+	}
+}
+
+func batchFlats(store models.HouseStore, userType string) dataloader.BatchFunc[int, []models.Flat] {
+	return func(ctx context.Context, houseIDs []int) []*dataloader.Result[[]models.Flat] {
+		results := make([]*dataloader.Result[[]models.Flat], len(houseIDs))
+
+		flats, err := store.GetFlatsByHouseIDs(houseIDs, userType)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[[]models.Flat]{Error: err}
+			}
+			return results
+		}
+
+		byHouse := make(map[int][]models.Flat, len(houseIDs))
+		for _, flat := range flats {
+			byHouse[flat.House_id] = append(byHouse[flat.House_id], flat)
+		}
+		for i, id := range houseIDs {
+			results[i] = &dataloader.Result[[]models.Flat]{Data: byHouse[id]}
+		}
+		return results
+	}
+}
+
+// batchStats gives houses without active flats empty stats.
+func batchStats(store models.HouseStore) dataloader.BatchFunc[int, *models.HouseStats] {
+	return func(ctx context.Context, houseIDs []int) []*dataloader.Result[*models.HouseStats] {
+		results := make([]*dataloader.Result[*models.HouseStats], len(houseIDs))
+
+		stats, err := store.GetHouseStats(houseIDs)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[*models.HouseStats]{Error: err}
+			}
+			return results
+		}
+
+		byHouse := make(map[int]*models.HouseStats, len(stats))
+		for i := range stats {
+			byHouse[stats[i].HouseID] = &stats[i]
+		}
+		for i, id := range houseIDs {
+			st, ok := byHouse[id]
+			if !ok {
+				st = &models.HouseStats{HouseID: id}
+			}
+			results[i] = &dataloader.Result[*models.HouseStats]{Data: st}
+		}
+		return results
+	}
+}
+
+func batchSubscriptions(store models.HouseStore) dataloader.BatchFunc[subscriptionKey, *models.Subscription] {
+	return func(ctx context.Context, keys []subscriptionKey) []*dataloader.Result[*models.Subscription] {
+		results := make([]*dataloader.Result[*models.Subscription], len(keys))
+
+		houseIDs := make(map[string][]int)
+		for _, key := range keys {
+			houseIDs[key.email] = append(houseIDs[key.email], key.houseID)
+		}
+
+		found := make(map[subscriptionKey]*models.Subscription)
+		errs := make(map[string]error)
+		for email, ids := range houseIDs {
+			subscriptions, err := store.GetSubscriptions(ids, email)
+			if err != nil {
+				errs[email] = err
+				continue
+			}
+			for i := range subscriptions {
+				houseID, _ := strconv.Atoi(subscriptions[i].HouseID)
+				found[subscriptionKey{houseID: houseID, email: email}] = &subscriptions[i]
+			}
+		}
+
+		for i, key := range keys {
+			results[i] = &dataloader.Result[*models.Subscription]{Data: found[key], Error: errs[key.email]}
+		}
+		return results
+	}
+}
@@ -0,0 +1,528 @@
+package gql
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/notification"
+	"github.com/delapaska/avito-rent/utils"
+	"github.com/graph-gophers/graphql-go"
+)
+
+var flatStatuses = map[string]string{
+	models.StatusCreated:      "CREATED",
+	models.StatusOnModeration: "ON_MODERATION",
+	models.StatusApproved:     "APPROVED",
+	models.StatusDeclined:     "DECLINED",
+	models.StatusExpired:      "EXPIRED",
+	models.StatusRented:       "RENTED",
+	models.StatusSold:         "SOLD",
+}
+
+var materials = map[string]string{
+	models.MaterialBrick:         "BRICK",
+	models.MaterialPanel:         "PANEL",
+	models.MaterialMonolith:      "MONOLITH",
+	models.MaterialMonolithBrick: "MONOLITH_BRICK",
+	models.MaterialBlock:         "BLOCK",
+	models.MaterialWood:          "WOOD",
+}
+
+var listingTypes = map[string]string{
+	models.ListingSale: "SALE",
+	models.ListingRent: "RENT",
+}
+
+var rentPeriods = map[string]string{
+	models.RentMonthly: "MONTHLY",
+	models.RentDaily:   "DAILY",
+}
+
+var locales = map[string]string{
+	models.LocaleRU: "RU",
+	models.LocaleEN: "EN",
+}
+
+var deliveries = map[string]string{
+	models.DeliveryInstant: "INSTANT",
+	models.DeliveryDaily:   "DAILY",
+}
+
+type resolver struct {
+	houses   models.HouseStore
+	flats    models.FlatStore
+	users    models.UserStore
+	notifier *notification.Notifier
+}
+
+func parseID(id graphql.ID) (int, error) {
+	n, err := strconv.Atoi(string(id))
+	if err != nil {
+		return 0, fmt.Errorf("invalid id %q", id)
+	}
+	return n, nil
+}
+
+func formatID(id int) graphql.ID {
+	return graphql.ID(strconv.Itoa(id))
+}
+
+// fromEnum returns the model value of a GraphQL enum value, "" when there is
+// none.
+func fromEnum(values map[string]string, value string) string {
+	for name, v := range values {
+		if v == value {
+			return name
+		}
+	}
+	return ""
+}
+
+func intFromInput(n *int32) *int {
+	if n == nil {
+		return nil
+	}
+	v := int(*n)
+	return &v
+}
+
+func int32FromModel(n *int) *int32 {
+	if n == nil {
+		return nil
+	}
+	v := int32(*n)
+	return &v
+}
+
+func requireRole(ctx context.Context, roles ...string) error {
+	userType := userFrom(ctx).userType
+	for _, role := range roles {
+		if userType == role {
+			return nil
+		}
+	}
+	return fmt.Errorf("you have not enough rights to use this operation")
+}
+
+func (r *resolver) House(ctx context.Context, args struct{ ID graphql.ID }) (*houseResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := loadersFrom(ctx).houses.Load(ctx, id)()
+	if err != nil || h == nil {
+		return nil, err
+	}
+	return &houseResolver{house: *h, root: r}, nil
+}
+
+func (r *resolver) Houses(ctx context.Context, args struct{ IDs []graphql.ID }) ([]*houseResolver, error) {
+	ids := make([]int, len(args.IDs))
+	for i, id := range args.IDs {
+		n, err := parseID(id)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = n
+	}
+
+	houses, errs := loadersFrom(ctx).houses.LoadMany(ctx, ids)()
+	result := make([]*houseResolver, 0, len(houses))
+	for i, h := range houses {
+		if len(errs) > i && errs[i] != nil {
+			return nil, errs[i]
+		}
+		if h != nil {
+			result = append(result, &houseResolver{house: *h, root: r})
+		}
+	}
+	return result, nil
+}
+
+type houseInput struct {
+	Address   string
+	Year      int32
+	Developer *string
+	Floors    *int32
+	Material  *string
+	Elevator  *bool
+	Parking   *bool
+}
+
+func (r *resolver) CreateHouse(ctx context.Context, args struct{ Input houseInput }) (*houseResolver, error) {
+	if err := requireRole(ctx, "moderator"); err != nil {
+		return nil, err
+	}
+
+	payload := models.HousePayload{
+		Address:  args.Input.Address,
+		Year:     int(args.Input.Year),
+		Floors:   intFromInput(args.Input.Floors),
+		Elevator: args.Input.Elevator,
+		Parking:  args.Input.Parking,
+	}
+	if args.Input.Developer != nil {
+		payload.Developer = *args.Input.Developer
+	}
+	if args.Input.Material != nil {
+		payload.Material = fromEnum(materials, *args.Input.Material)
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(payload.Address) == "" {
+		return nil, fmt.Errorf("address cannot be empty")
+	}
+	if payload.Developer != "" && strings.TrimSpace(payload.Developer) == "" {
+		return nil, fmt.Errorf("developer cannot be empty")
+	}
+	if payload.Year < 0 {
+		return nil, fmt.Errorf("year must be more or equal than 0")
+	}
+
+	h, err := r.houses.CreateHouse(models.House{
+		Address:   payload.Address,
+		Year:      payload.Year,
+		Developer: payload.Developer,
+		Floors:    payload.Floors,
+		Material:  payload.Material,
+		Elevator:  payload.Elevator,
+		Parking:   payload.Parking,
+	})
+	if err != nil {
+		return nil, err
+	}
+	middleware.RecordAudit(ctx, models.AuditHouseCreate, "house", h.Id, nil, h)
+	return &houseResolver{house: h, root: r}, nil
+}
+
+type flatInput struct {
+	HouseID           graphql.ID
+	Price             int32
+	Rooms             int32
+	Number            *int32
+	Floor             *int32
+	TotalArea         *float64
+	LivingArea        *float64
+	Balcony           *bool
+	ListingType       string
+	RentPeriod        *string
+	Deposit           *int32
+	Commission        *int32
+	UtilitiesIncluded *bool
+}
+
+func (r *resolver) CreateFlat(ctx context.Context, args struct{ Input flatInput }) (*flatResolver, error) {
+	houseID, err := parseID(args.Input.HouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := models.FlatPayload{
+		House_id:          houseID,
+		Price:             int(args.Input.Price),
+		Rooms:             int(args.Input.Rooms),
+		Number:            intFromInput(args.Input.Number),
+		Floor:             intFromInput(args.Input.Floor),
+		TotalArea:         args.Input.TotalArea,
+		LivingArea:        args.Input.LivingArea,
+		Balcony:           args.Input.Balcony,
+		ListingType:       fromEnum(listingTypes, args.Input.ListingType),
+		Deposit:           intFromInput(args.Input.Deposit),
+		Commission:        intFromInput(args.Input.Commission),
+		UtilitiesIncluded: args.Input.UtilitiesIncluded,
+	}
+	if args.Input.RentPeriod != nil {
+		payload.RentPeriod = fromEnum(rentPeriods, *args.Input.RentPeriod)
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		return nil, err
+	}
+	if err := payload.Check(); err != nil {
+		return nil, err
+	}
+
+	ownerID := userFrom(ctx).userID
+	flat, err := r.flats.CreateFlat(models.Flat{
+		House_id:          payload.House_id,
+		Price:             payload.Price,
+		Rooms:             payload.Rooms,
+		Number:            payload.Number,
+		Floor:             payload.Floor,
+		TotalArea:         payload.TotalArea,
+		LivingArea:        payload.LivingArea,
+		Balcony:           payload.Balcony,
+		ListingType:       payload.ListingType,
+		RentPeriod:        payload.RentPeriod,
+		Deposit:           payload.Deposit,
+		Commission:        payload.Commission,
+		UtilitiesIncluded: payload.UtilitiesIncluded,
+		OwnerID:           &ownerID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	middleware.RecordAudit(ctx, models.AuditFlatCreate, "flat", flat.Id, nil, flat)
+	return &flatResolver{flat: flat, root: r}, nil
+}
+
+type updateFlatStatusInput struct {
+	ID         graphql.ID
+	Status     string
+	FinalPrice *int32
+}
+
+func (r *resolver) UpdateFlatStatus(ctx context.Context, args struct{ Input updateFlatStatusInput }) (*flatResolver, error) {
+	closing := args.Input.Status == flatStatuses[models.StatusRented] || args.Input.Status == flatStatuses[models.StatusSold]
+	roles := []string{"moderator"}
+	if closing {
+		roles = append(roles, "client")
+	}
+	if err := requireRole(ctx, roles...); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(args.Input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := fromEnum(flatStatuses, args.Input.Status)
+
+	before, err := r.flats.GetFlat(id)
+	if err != nil {
+		return nil, err
+	}
+	payload := models.UpdateStatusPayload{Id: id, Status: status, FinalPrice: intFromInput(args.Input.FinalPrice)}
+	flat, err := r.flats.UpdateFlatStatus(userFrom(ctx).userID, payload)
+	if err != nil {
+		return nil, err
+	}
+	action := models.AuditFlatModerate
+	if closing {
+		action = models.AuditFlatClose
+	}
+	middleware.RecordAudit(ctx, action, "flat", flat.Id, before, flat)
+	if flat.Status == models.StatusApproved {
+		go r.notifier.NotifyFlatApproved(flat)
+	}
+	return &flatResolver{flat: flat, root: r}, nil
+}
+
+func (r *resolver) Subscribe(ctx context.Context, args struct {
+	HouseID  graphql.ID
+	Email    string
+	Locale   string
+	Delivery string
+}) (*subscriptionResolver, error) {
+	houseID, err := parseID(args.HouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := models.SubscribePayload{
+		Email:    args.Email,
+		Locale:   fromEnum(locales, args.Locale),
+		Delivery: fromEnum(deliveries, args.Delivery),
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		return nil, err
+	}
+
+	sub := models.Subscription{
+		HouseID:   strconv.Itoa(houseID),
+		Email:     payload.Email,
+		Locale:    payload.Locale,
+		Delivery:  payload.Delivery,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := r.houses.AddSubscription(sub); err != nil {
+		return nil, fmt.Errorf("failed to save subscription")
+	}
+	middleware.RecordAudit(ctx, models.AuditHouseSubscribe, "house", houseID, nil, sub)
+
+	go r.notifier.NotifySubscribed(sub)
+
+	return &subscriptionResolver{sub: sub}, nil
+}
+
+type houseResolver struct {
+	house models.House
+	root  *resolver
+}
+
+func (h *houseResolver) ID() graphql.ID          { return formatID(h.house.Id) }
+func (h *houseResolver) Address() string         { return h.house.Address }
+func (h *houseResolver) Year() int32             { return int32(h.house.Year) }
+func (h *houseResolver) Developer() string       { return h.house.Developer }
+func (h *houseResolver) Floors() *int32          { return int32FromModel(h.house.Floors) }
+func (h *houseResolver) Elevator() *bool         { return h.house.Elevator }
+func (h *houseResolver) Parking() *bool          { return h.house.Parking }
+func (h *houseResolver) CreatedAt() graphql.Time { return graphql.Time{Time: h.house.Created_at} }
+func (h *houseResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: h.house.Updated_at} }
+
+func (h *houseResolver) Material() *string {
+	if h.house.Material == "" {
+		return nil
+	}
+	material := materials[h.house.Material]
+	return &material
+}
+
+// Flats filters the flats of the loader rather than the query, so houses
+// share one batch whatever listing type each field asks for.
+func (h *houseResolver) Flats(ctx context.Context, args struct{ ListingType *string }) ([]*flatResolver, error) {
+	flats, err := loadersFrom(ctx).flats.Load(ctx, h.house.Id)()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*flatResolver, 0, len(flats))
+	for _, flat := range flats {
+		if args.ListingType != nil && listingTypes[flat.ListingType] != *args.ListingType {
+			continue
+		}
+		result = append(result, &flatResolver{flat: flat, root: h.root})
+	}
+	return result, nil
+}
+
+// Stats come from their own query rather than from the flats of the loader,
+// which only hold what the user may see.
+func (h *houseResolver) Stats(ctx context.Context) (*statsResolver, error) {
+	stats, err := loadersFrom(ctx).stats.Load(ctx, h.house.Id)()
+	if err != nil {
+		return nil, err
+	}
+	return &statsResolver{stats: *stats}, nil
+}
+
+func (h *houseResolver) Subscription(ctx context.Context, args struct{ Email *string }) (*subscriptionResolver, error) {
+	l := loadersFrom(ctx)
+
+	var email string
+	if args.Email != nil && userFrom(ctx).userType == "moderator" {
+		email = *args.Email
+	} else {
+		current, err := l.currentEmail()
+		if err != nil || current == "" {
+			return nil, nil
+		}
+		// Other users only see their own subscription, otherwise the field
+		// would tell anyone whether an address is subscribed.
+		if args.Email != nil && !strings.EqualFold(*args.Email, current) {
+			return nil, nil
+		}
+		email = current
+	}
+
+	sub, err := l.subscriptions.Load(ctx, subscriptionKey{houseID: h.house.Id, email: email})()
+	if err != nil || sub == nil {
+		return nil, err
+	}
+	return &subscriptionResolver{sub: *sub}, nil
+}
+
+type statsResolver struct {
+	stats models.HouseStats
+}
+
+func (s *statsResolver) Flats() int32        { return int32(s.stats.Flats) }
+func (s *statsResolver) Created() int32      { return int32(s.stats.Created) }
+func (s *statsResolver) OnModeration() int32 { return int32(s.stats.OnModeration) }
+func (s *statsResolver) Approved() int32     { return int32(s.stats.Approved) }
+func (s *statsResolver) Declined() int32     { return int32(s.stats.Declined) }
+func (s *statsResolver) Expired() int32      { return int32(s.stats.Expired) }
+func (s *statsResolver) Rented() int32       { return int32(s.stats.Rented) }
+func (s *statsResolver) Sold() int32         { return int32(s.stats.Sold) }
+func (s *statsResolver) MinPrice() *int32    { return int32FromModel(s.stats.MinPrice) }
+func (s *statsResolver) MaxPrice() *int32    { return int32FromModel(s.stats.MaxPrice) }
+func (s *statsResolver) AvgPrice() *float64  { return s.stats.AvgPrice }
+
+func (s *statsResolver) MinPricePerM2() *float64 { return s.stats.MinPricePerM2 }
+func (s *statsResolver) MaxPricePerM2() *float64 { return s.stats.MaxPricePerM2 }
+func (s *statsResolver) AvgPricePerM2() *float64 { return s.stats.AvgPricePerM2 }
+
+func (s *statsResolver) AvgSoldPrice() *float64   { return s.stats.AvgSoldPrice }
+func (s *statsResolver) AvgMonthlyRent() *float64 { return s.stats.AvgMonthlyRent }
+
+type flatResolver struct {
+	flat models.Flat
+	root *resolver
+}
+
+func (f *flatResolver) ID() graphql.ID      { return formatID(f.flat.Id) }
+func (f *flatResolver) HouseID() graphql.ID { return formatID(f.flat.House_id) }
+func (f *flatResolver) Price() int32        { return int32(f.flat.Price) }
+func (f *flatResolver) Rooms() int32        { return int32(f.flat.Rooms) }
+func (f *flatResolver) Status() string      { return flatStatuses[f.flat.Status] }
+
+func (f *flatResolver) Number() *int32       { return int32FromModel(f.flat.Number) }
+func (f *flatResolver) Floor() *int32        { return int32FromModel(f.flat.Floor) }
+func (f *flatResolver) TotalArea() *float64  { return f.flat.TotalArea }
+func (f *flatResolver) LivingArea() *float64 { return f.flat.LivingArea }
+func (f *flatResolver) Balcony() *bool       { return f.flat.Balcony }
+func (f *flatResolver) PricePerM2() *float64 { return f.flat.PricePerM2 }
+
+func (f *flatResolver) ListingType() string {
+	return listingTypes[cmp.Or(f.flat.ListingType, models.ListingSale)]
+}
+
+func (f *flatResolver) RentPeriod() *string {
+	if f.flat.RentPeriod == "" {
+		return nil
+	}
+	period := rentPeriods[f.flat.RentPeriod]
+	return &period
+}
+
+func (f *flatResolver) Deposit() *int32          { return int32FromModel(f.flat.Deposit) }
+func (f *flatResolver) Commission() *int32       { return int32FromModel(f.flat.Commission) }
+func (f *flatResolver) UtilitiesIncluded() *bool { return f.flat.UtilitiesIncluded }
+
+func (f *flatResolver) DuplicateOf() *[]graphql.ID {
+	if f.flat.DuplicateOf == nil {
+		return nil
+	}
+	ids := make([]graphql.ID, len(f.flat.DuplicateOf))
+	for i, id := range f.flat.DuplicateOf {
+		ids[i] = formatID(id)
+	}
+	return &ids
+}
+
+func (f *flatResolver) ExpiresAt() *graphql.Time {
+	if f.flat.ExpiresAt == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *f.flat.ExpiresAt}
+}
+
+func (f *flatResolver) FinalPrice() *int32 { return int32FromModel(f.flat.FinalPrice) }
+
+func (f *flatResolver) House(ctx context.Context) (*houseResolver, error) {
+	h, err := loadersFrom(ctx).houses.Load(ctx, f.flat.House_id)()
+	if err != nil || h == nil {
+		return nil, err
+	}
+	return &houseResolver{house: *h, root: f.root}, nil
+}
+
+type subscriptionResolver struct {
+	sub models.Subscription
+}
+
+func (s *subscriptionResolver) HouseID() graphql.ID { return graphql.ID(s.sub.HouseID) }
+func (s *subscriptionResolver) Email() string       { return s.sub.Email }
+func (s *subscriptionResolver) Locale() string      { return locales[cmp.Or(s.sub.Locale, models.LocaleRU)] }
+func (s *subscriptionResolver) Delivery() string {
+	return deliveries[cmp.Or(s.sub.Delivery, models.DeliveryInstant)]
+}
+func (s *subscriptionResolver) CreatedAt() graphql.Time { return graphql.Time{Time: s.sub.CreatedAt} }
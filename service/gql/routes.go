@@ -0,0 +1,95 @@
+package gql
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/notification"
+	"github.com/delapaska/avito-rent/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type contextKey int
+
+const (
+	userKey contextKey = iota
+	loadersKey
+)
+
+type user struct {
+	userID   uuid.UUID
+	userType string
+}
+
+func userFrom(ctx context.Context) user {
+	u, _ := ctx.Value(userKey).(user)
+	return u
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey).(*loaders)
+}
+
+type Handler struct {
+	schema *graphql.Schema
+	houses models.HouseStore
+	users  models.UserStore
+}
+
+func NewHandler(houses models.HouseStore, flats models.FlatStore, users models.UserStore, notifier *notification.Notifier) *Handler {
+	r := &resolver{houses: houses, flats: flats, users: users, notifier: notifier}
+	return &Handler{
+		schema: graphql.MustParseSchema(schema, r),
+		houses: houses,
+		users:  users,
+	}
+}
+
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+
+	allUsers := router.Group("/")
+	allUsers.Use(middleware.AuthMiddleware("moderator", "client"))
+	{
+		allUsers.POST("/graphql", h.handleGraphQL)
+	}
+}
+
+// @Summary GraphQL
+// @Description Run a GraphQL query or mutation over houses, flats and subscriptions. Requires authorization for both moderator and client; clients only see approved flats.
+// @Tags GraphQL
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.GraphQLPayload true "GraphQL request"
+// @Success 200 {object} object "GraphQL response with data and errors"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Router /graphql [post]
+func (h *Handler) handleGraphQL(c *gin.Context) {
+	var payload models.GraphQLPayload
+	if err := utils.ParseJSON(c, &payload); err != nil {
+		utils.WriteError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		errors := err.(validator.ValidationErrors)
+		utils.WriteError(c, http.StatusBadRequest, utils.FormatValidationError(errors))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	u := user{userType: c.GetString("userType")}
+	u.userID, _ = userID.(uuid.UUID)
+
+	ctx := context.WithValue(c.Request.Context(), userKey, u)
+	ctx = context.WithValue(ctx, loadersKey, newLoaders(h.houses, h.users, u))
+
+	response := h.schema.Exec(ctx, payload.Query, payload.OperationName, payload.Variables)
+	utils.WriteJSON(c, http.StatusOK, response)
+}
@@ -0,0 +1,187 @@
+package gql
+
+const schema = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+scalar Time
+
+enum FlatStatus {
+	CREATED
+	ON_MODERATION
+	APPROVED
+	DECLINED
+	EXPIRED
+	RENTED
+	SOLD
+}
+
+type Query {
+	house(id: ID!): House
+	houses(ids: [ID!]!): [House!]!
+}
+
+type Mutation {
+	createHouse(input: HouseInput!): House!
+	createFlat(input: FlatInput!): Flat!
+	updateFlatStatus(input: UpdateFlatStatusInput!): Flat!
+	subscribe(houseId: ID!, email: String!, locale: Locale = RU, delivery: Delivery = INSTANT): HouseSubscription!
+}
+
+type House {
+	id: ID!
+	address: String!
+	year: Int!
+	developer: String!
+	# Null when not given, as are material, elevator and parking.
+	floors: Int
+	material: HouseMaterial
+	elevator: Boolean
+	parking: Boolean
+	createdAt: Time!
+	updatedAt: Time!
+	# Clients only see approved flats. All flats when listingType is omitted.
+	flats(listingType: ListingType): [Flat!]!
+	stats: HouseStats!
+	# Subscription of the current user. Moderators may pass any email, other
+	# users only their own; null for anyone else's.
+	subscription(email: String): HouseSubscription
+}
+
+# Material of the walls of a house.
+enum HouseMaterial {
+	BRICK
+	PANEL
+	MONOLITH
+	MONOLITH_BRICK
+	BLOCK
+	WOOD
+}
+
+# Whether a flat is for sale or for rent.
+enum ListingType {
+	SALE
+	RENT
+}
+
+# Period the rent of a flat is charged for.
+enum RentPeriod {
+	MONTHLY
+	DAILY
+}
+
+# Stats cover every active flat of the house, whoever asks.
+type HouseStats {
+	flats: Int!
+	created: Int!
+	onModeration: Int!
+	approved: Int!
+	declined: Int!
+	expired: Int!
+	rented: Int!
+	sold: Int!
+	# Prices of the approved flats.
+	minPrice: Int
+	maxPrice: Int
+	avgPrice: Float
+	# Price per square meter over the approved flats with a known total area.
+	minPricePerM2: Float
+	maxPricePerM2: Float
+	avgPricePerM2: Float
+	# Average final price of the sold flats and of the flats rented out monthly.
+	avgSoldPrice: Float
+	avgMonthlyRent: Float
+}
+
+type Flat {
+	id: ID!
+	houseId: ID!
+	house: House
+	price: Int!
+	rooms: Int!
+	status: FlatStatus!
+	# Null when not given, as are floor, the areas and balcony.
+	number: Int
+	floor: Int
+	totalArea: Float
+	livingArea: Float
+	balcony: Boolean
+	listingType: ListingType!
+	# Only set for rentals, whose price is the rent per this period.
+	rentPeriod: RentPeriod
+	deposit: Int
+	# Agent commission in percent of the rent.
+	commission: Int
+	utilitiesIncluded: Boolean
+	# Null when the total area is unknown.
+	pricePerM2: Float
+	# Flats of the house that look like the same flat, only set when a
+	# moderator takes the flat into moderation.
+	duplicateOf: [ID!]
+	# When the listing of an approved flat expires, null when it does not.
+	expiresAt: Time
+	# Price the flat was rented out or sold for.
+	finalPrice: Int
+}
+
+type HouseSubscription {
+	houseId: ID!
+	email: String!
+	locale: Locale!
+	delivery: Delivery!
+	createdAt: Time!
+}
+
+# Language of the notification emails.
+enum Locale {
+	RU
+	EN
+}
+
+# How often the subscriber is notified: an email per approved flat or one
+# digest a day.
+enum Delivery {
+	INSTANT
+	DAILY
+}
+
+input HouseInput {
+	address: String!
+	year: Int!
+	developer: String
+	floors: Int
+	material: HouseMaterial
+	elevator: Boolean
+	parking: Boolean
+}
+
+input FlatInput {
+	houseId: ID!
+	price: Int!
+	rooms: Int!
+	# Unique within the house.
+	number: Int
+	# Not higher than the floors of the house.
+	floor: Int
+	totalArea: Float
+	# Not larger than the total area.
+	livingArea: Float
+	balcony: Boolean
+	listingType: ListingType = SALE
+	# Required for rentals. Rental terms are not allowed for sales.
+	rentPeriod: RentPeriod
+	deposit: Int
+	commission: Int
+	utilitiesIncluded: Boolean
+}
+
+input UpdateFlatStatusInput {
+	id: ID!
+	status: FlatStatus!
+	# Only for RENTED and SOLD, which the owner of the flat sets; the listed
+	# price when omitted.
+	finalPrice: Int
+}
+`
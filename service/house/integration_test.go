@@ -7,8 +7,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/delapaska/avito-rent/models"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
@@ -27,7 +29,7 @@ func TestHandleGetHouseFlats(t *testing.T) {
 	r.GET("/houses/:id/flats", handler.handleGetHouseFlats)
 
 	t.Run("should return internal server error when database query fails", func(t *testing.T) {
-		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status FROM flat WHERE house_id = \$1 AND status = 'approved'`).
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, number, floor, total_area, living_area, balcony, listing_type, COALESCE\(rent_period, ''\), deposit, commission, utilities_included, price_per_m2, archived_at, COALESCE\(description, ''\), high_risk, rule_hits, COALESCE\(decline_reason, ''\), expires_at, final_price, closed_at FROM flat WHERE house_id = \$1 AND status = 'approved'`).
 			WithArgs("1").
 			WillReturnError(fmt.Errorf("database query error"))
 
@@ -54,10 +56,10 @@ func TestHandleGetHouseFlats(t *testing.T) {
 	})
 
 	t.Run("should return flats when query succeeds", func(t *testing.T) {
-		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status FROM flat WHERE house_id = \$1 AND status = 'approved'`).
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, number, floor, total_area, living_area, balcony, listing_type, COALESCE\(rent_period, ''\), deposit, commission, utilities_included, price_per_m2, archived_at, COALESCE\(description, ''\), high_risk, rule_hits, COALESCE\(decline_reason, ''\), expires_at, final_price, closed_at FROM flat WHERE house_id = \$1 AND status = 'approved'`).
 			WithArgs("1").
-			WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "price", "rooms", "status"}).
-				AddRow(1, 1, 100000, 3, "approved"))
+			WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "price", "rooms", "status", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "archived_at", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}).
+				AddRow(1, 1, 100000, 3, "approved", nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, nil, "", false, nil, "", nil, nil, nil))
 
 		req, err := http.NewRequest("GET", "/houses/1/flats", nil)
 		if err != nil {
@@ -88,9 +90,9 @@ func TestHandleGetHouseFlats(t *testing.T) {
 	})
 
 	t.Run("should handle empty result set correctly", func(t *testing.T) {
-		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status FROM flat WHERE house_id = \$1 AND status = 'approved'`).
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, number, floor, total_area, living_area, balcony, listing_type, COALESCE\(rent_period, ''\), deposit, commission, utilities_included, price_per_m2, archived_at, COALESCE\(description, ''\), high_risk, rule_hits, COALESCE\(decline_reason, ''\), expires_at, final_price, closed_at FROM flat WHERE house_id = \$1 AND status = 'approved'`).
 			WithArgs("1").
-			WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "price", "rooms", "status"}))
+			WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "price", "rooms", "status", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "archived_at", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}))
 
 		req, err := http.NewRequest("GET", "/houses/1/flats", nil)
 		if err != nil {
@@ -114,10 +116,10 @@ func TestHandleGetHouseFlats(t *testing.T) {
 	})
 
 	t.Run("should handle moderator role correctly", func(t *testing.T) {
-		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status FROM flat WHERE house_id = \$1`).
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, number, floor, total_area, living_area, balcony, listing_type, COALESCE\(rent_period, ''\), deposit, commission, utilities_included, price_per_m2, archived_at, COALESCE\(description, ''\), high_risk, rule_hits, COALESCE\(decline_reason, ''\), expires_at, final_price, closed_at FROM flat WHERE house_id = \$1`).
 			WithArgs("1").
-			WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "price", "rooms", "status"}).
-				AddRow(2, 1, 150000, 4, "pending"))
+			WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "price", "rooms", "status", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "archived_at", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}).
+				AddRow(2, 1, 150000, 4, "pending", nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, nil, "", false, nil, "", nil, nil, nil))
 
 		req, err := http.NewRequest("GET", "/houses/1/flats", nil)
 		if err != nil {
@@ -147,3 +149,239 @@ func TestHandleGetHouseFlats(t *testing.T) {
 		assert.Equal(t, "pending", flat["status"])
 	})
 }
+
+func TestHandleGetHouseFlatsIncludeArchived(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("userType", c.GetHeader("userType"))
+	})
+	handler := &Handler{store: NewStore(db)}
+	r.GET("/house/:id", handler.handleGetHouseFlats)
+
+	t.Run("should forbid archived flats for clients", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/house/1?include_archived=true", nil)
+		req.Header.Set("userType", "client")
+
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return archived flats to moderators", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, number, floor, total_area, living_area, balcony, listing_type, COALESCE\(rent_period, ''\), deposit, commission, utilities_included, price_per_m2, archived_at, COALESCE\(description, ''\), high_risk, rule_hits, COALESCE\(decline_reason, ''\), expires_at, final_price, closed_at FROM flat WHERE house_id = \$1 ORDER BY id`).
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "price", "rooms", "status", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "archived_at", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}).
+				AddRow(1, 1, 100000, 3, "approved", nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, nil, "", false, nil, "", nil, nil, nil).
+				AddRow(2, 1, 150000, 4, "approved", nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, time.Date(2024, 8, 18, 9, 0, 0, 0, time.UTC), "", false, nil, "", nil, nil, nil))
+
+		req, _ := http.NewRequest("GET", "/house/1?include_archived=true", nil)
+		req.Header.Set("userType", "moderator")
+
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var response map[string][]map[string]interface{}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("error decoding response: %v", err)
+		}
+		if assert.Len(t, response["flats"], 2) {
+			assert.NotContains(t, response["flats"][0], "archived_at")
+			assert.Equal(t, "2024-08-18T09:00:00Z", response["flats"][1]["archived_at"])
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleGetHouseFlatsListingType(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("userType", c.GetHeader("userType"))
+	})
+	handler := &Handler{store: NewStore(db)}
+	r.GET("/house/:id", handler.handleGetHouseFlats)
+
+	serve := func(path, userType string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("userType", userType)
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	columns := []string{"id", "house_id", "price", "rooms", "status", "number", "floor", "total_area", "living_area", "balcony",
+		"listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "archived_at", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}
+
+	t.Run("should only return rentals to a client", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE house_id = \$1 AND status = 'approved' AND archived_at IS NULL AND listing_type = \$2`).
+			WithArgs("1", "rent").
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(3, 1, 45000, 2, "approved", nil, nil, nil, nil, nil, "rent", "daily", nil, nil, true, nil, nil, "", false, nil, "", nil, nil, nil))
+
+		recorder := serve("/house/1?listing_type=rent", "client")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"flats": [{"id": 3, "house_id": 1, "price": 45000, "rooms": 2, "status": "approved",
+			"listing_type": "rent", "rent_period": "daily", "utilities_included": true}]}`, recorder.Body.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should filter archived flats by type for a moderator", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE house_id = \$1 AND listing_type = \$2 ORDER BY id`).
+			WithArgs("1", "sale").
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		recorder := serve("/house/1?include_archived=true&listing_type=sale", "moderator")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject an unknown type", func(t *testing.T) {
+		recorder := serve("/house/1?listing_type=lease", "client")
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleGetHouseFlatsPricePerM2(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("userType", c.GetHeader("userType"))
+	})
+	handler := &Handler{store: NewStore(db)}
+	r.GET("/house/:id", handler.handleGetHouseFlats)
+
+	serve := func(path, userType string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("userType", userType)
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	columns := []string{"id", "house_id", "price", "rooms", "status", "number", "floor", "total_area", "living_area", "balcony",
+		"listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "archived_at", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}
+
+	t.Run("should filter and sort by price per square meter", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE house_id = \$1 AND status = 'approved' AND archived_at IS NULL AND price_per_m2 >= \$2 AND price_per_m2 <= \$3 ORDER BY price_per_m2 DESC NULLS LAST, id`).
+			WithArgs("1", 1000.0, 2000.5).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(3, 1, 100000, 3, "approved", nil, nil, []byte("64.50"), nil, nil, "sale", "", nil, nil, nil, []byte("1550.39"), nil, "", false, nil, "", nil, nil, nil))
+
+		recorder := serve("/house/1?price_per_m2_min=1000&price_per_m2_max=2000.5&sort=-price_per_m2", "client")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"flats": [{"id": 3, "house_id": 1, "price": 100000, "rooms": 3, "status": "approved",
+			"total_area": 64.5, "price_per_m2": 1550.39, "listing_type": "sale"}]}`, recorder.Body.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should sort archived flats for a moderator", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE house_id = \$1 ORDER BY price_per_m2 ASC NULLS LAST, id`).
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		recorder := serve("/house/1?include_archived=true&sort=price_per_m2", "moderator")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject an invalid range or order", func(t *testing.T) {
+		for _, path := range []string{
+			"/house/1?price_per_m2_min=abc",
+			"/house/1?price_per_m2_max=-1",
+			"/house/1?price_per_m2_min=2000&price_per_m2_max=1000",
+			"/house/1?sort=price",
+		} {
+			recorder := serve(path, "client")
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, path)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleGetHouseFlatsHighRisk(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	r := gin.Default()
+	r.Use(func(c *gin.Context) {
+		c.Set("userType", c.GetHeader("userType"))
+	})
+	handler := &Handler{store: NewStore(db)}
+	r.GET("/house/:id", handler.handleGetHouseFlats)
+
+	serve := func(path, userType string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("userType", userType)
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	columns := []string{"id", "house_id", "price", "rooms", "status", "number", "floor", "total_area", "living_area", "balcony",
+		"listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "archived_at", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}
+	hits := []byte(`[{"rule": "banned_words", "action": "high_risk", "reason": "description contains \"предоплата\""}]`)
+
+	t.Run("should list high risk flats first to a moderator", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE house_id = \$1 AND archived_at IS NULL ORDER BY high_risk DESC, id`).
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(4, 1, 100000, 3, "created", nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, nil, "", true, hits, "", nil, nil, nil).
+				AddRow(2, 1, 150000, 4, "approved", nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, nil, "", false, nil, "", nil, nil, nil))
+
+		recorder := serve("/house/1", "moderator")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var response struct {
+			Flats []models.Flat `json:"flats"`
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("error decoding response: %v", err)
+		}
+		if assert.Len(t, response.Flats, 2) {
+			assert.True(t, response.Flats[0].HighRisk)
+			assert.Equal(t, "banned_words", response.Flats[0].RuleHits[0].Rule)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should hide rule hits from a client", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE house_id = \$1 AND status = 'approved' AND archived_at IS NULL`).
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(4, 1, 100000, 3, "approved", nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, nil, "", true, hits, "", nil, nil, nil))
+
+		recorder := serve("/house/1", "client")
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"flats": [{"id": 4, "house_id": 1, "price": 100000, "rooms": 3, "status": "approved", "listing_type": "sale"}]}`, recorder.Body.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -2,6 +2,7 @@ package house
 
 import (
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/delapaska/avito-rent/models"
@@ -18,20 +19,20 @@ func TestGetHouseFlats(t *testing.T) {
 
 	store := NewStore(db)
 
-	mock.ExpectQuery(`SELECT id, house_id, price, rooms, status FROM flat WHERE house_id = \$1`).
+	mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, number, floor, total_area, living_area, balcony, listing_type, COALESCE\(rent_period, ''\), deposit, commission, utilities_included, price_per_m2, archived_at, COALESCE\(description, ''\), high_risk, rule_hits, COALESCE\(decline_reason, ''\), expires_at, final_price, closed_at FROM flat WHERE house_id = \$1`).
 		WithArgs("1").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "price", "rooms", "status"}).
-			AddRow(1, "1", 100000, 3, "approved").
-			AddRow(2, "1", 150000, 4, "approved"))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "price", "rooms", "status", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "archived_at", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}).
+			AddRow(1, "1", 100000, 3, "approved", nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, nil, "", false, nil, "", nil, nil, nil).
+			AddRow(2, "1", 150000, 4, "approved", nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, nil, "", false, nil, "", nil, nil, nil))
 
-	flats, err := store.GetHouseFlats("1", "moderator")
+	flats, err := store.GetHouseFlats("1", "moderator", models.FlatFilter{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
 	expectedFlats := []models.Flat{
-		{Id: 1, House_id: 1, Price: 100000, Rooms: 3, Status: "approved"},
-		{Id: 2, House_id: 1, Price: 150000, Rooms: 4, Status: "approved"},
+		{Id: 1, House_id: 1, Price: 100000, Rooms: 3, Status: "approved", ListingType: "sale"},
+		{Id: 2, House_id: 1, Price: 150000, Rooms: 4, Status: "approved", ListingType: "sale"},
 	}
 	assert.Equal(t, expectedFlats, flats)
 
@@ -49,18 +50,18 @@ func TestGetHouseFlatsWithUserRole(t *testing.T) {
 
 	store := NewStore(db)
 
-	mock.ExpectQuery(`SELECT id, house_id, price, rooms, status FROM flat WHERE house_id = \$1 AND status = 'approved'`).
+	mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, number, floor, total_area, living_area, balcony, listing_type, COALESCE\(rent_period, ''\), deposit, commission, utilities_included, price_per_m2, archived_at, COALESCE\(description, ''\), high_risk, rule_hits, COALESCE\(decline_reason, ''\), expires_at, final_price, closed_at FROM flat WHERE house_id = \$1 AND status = 'approved'`).
 		WithArgs("1").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "price", "rooms", "status"}).
-			AddRow(1, "1", 100000, 3, "approved"))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "house_id", "price", "rooms", "status", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "archived_at", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}).
+			AddRow(1, "1", 100000, 3, "approved", nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, nil, "", false, nil, "", nil, nil, nil))
 
-	flats, err := store.GetHouseFlats("1", "user")
+	flats, err := store.GetHouseFlats("1", "user", models.FlatFilter{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
 	expectedFlats := []models.Flat{
-		{Id: 1, House_id: 1, Price: 100000, Rooms: 3, Status: "approved"},
+		{Id: 1, House_id: 1, Price: 100000, Rooms: 3, Status: "approved", ListingType: "sale"},
 	}
 	assert.Equal(t, expectedFlats, flats)
 
@@ -68,3 +69,113 @@ func TestGetHouseFlatsWithUserRole(t *testing.T) {
 		t.Errorf("there were unmet expectations: %v", err)
 	}
 }
+
+func TestArchiveHouse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(db)
+	houseColumns := []string{"id", "address", "year", "developer", "developer_id", "floors", "material", "elevator", "parking", "created_at", "updated_at", "archived_at"}
+	createdAt := time.Date(2024, 8, 1, 9, 0, 0, 0, time.UTC)
+	archivedAt := time.Date(2024, 8, 18, 9, 0, 0, 0, time.UTC)
+
+	t.Run("should archive house together with its active flats", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT archived_at FROM house WHERE id = \$1 FOR UPDATE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"archived_at"}).AddRow(nil))
+		mock.ExpectExec(`UPDATE flat SET archived_at = \$1 WHERE house_id = \$2 AND archived_at IS NULL`).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectQuery(`UPDATE house SET archived_at = \$1 WHERE id = \$2 RETURNING`).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnRows(sqlmock.NewRows(houseColumns).AddRow(1, "Лесная, 7", 2010, "", nil, nil, "", nil, nil, createdAt, createdAt, archivedAt))
+		mock.ExpectCommit()
+
+		house, err := store.ArchiveHouse(1)
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, house.ArchivedAt) {
+			assert.Equal(t, archivedAt, *house.ArchivedAt)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return ErrArchived for archived house", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT archived_at FROM house WHERE id = \$1 FOR UPDATE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"archived_at"}).AddRow(archivedAt))
+		mock.ExpectRollback()
+
+		_, err := store.ArchiveHouse(1)
+
+		assert.ErrorIs(t, err, models.ErrArchived)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should restore only flats archived together with the house", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT archived_at FROM house WHERE id = \$1 FOR UPDATE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"archived_at"}).AddRow(archivedAt))
+		mock.ExpectExec(`UPDATE flat SET archived_at = NULL WHERE house_id = \$1 AND archived_at = \$2`).
+			WithArgs(1, archivedAt).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectQuery(`UPDATE house SET archived_at = \$1 WHERE id = \$2 RETURNING`).
+			WithArgs(nil, 1).
+			WillReturnRows(sqlmock.NewRows(houseColumns).AddRow(1, "Лесная, 7", 2010, "", nil, nil, "", nil, nil, createdAt, createdAt, nil))
+		mock.ExpectCommit()
+
+		house, err := store.RestoreHouse(1)
+
+		assert.NoError(t, err)
+		assert.Nil(t, house.ArchivedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return ErrNotArchived for active house", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT archived_at FROM house WHERE id = \$1 FOR UPDATE`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"archived_at"}).AddRow(nil))
+		mock.ExpectRollback()
+
+		_, err := store.RestoreHouse(1)
+
+		assert.ErrorIs(t, err, models.ErrNotArchived)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetDigestEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(db)
+	from := time.Date(2024, 8, 11, 0, 0, 0, 0, time.UTC)
+	created := time.Date(2024, 8, 4, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT DISTINCT ON \(\(flat->>'id'\)::int\) house_id, \(flat->>'id'\)::int AS flat_id FROM house_events WHERE type = \$2 AND created_at >= \$3 AND created_at < \$4 ORDER BY \(flat->>'id'\)::int, id DESC`).
+		WithArgs(models.DeliveryDaily, models.EventFlatApproved, "2024-08-11T00:00:00Z", "2024-08-12T00:00:00Z", models.StatusApproved).
+		WillReturnRows(sqlmock.NewRows([]string{"email", "locale", "id", "address", "year", "developer", "developer_id", "created_at", "updated_at", "id", "house_id", "price", "rooms", "status"}).
+			AddRow("a@example.com", "ru", 1, "Лесная улица, 7", 2003, "", nil, created, created, 10, 1, 100000, 3, "approved"))
+
+	entries, err := store.GetDigestEntries(from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assert.Len(t, entries, 1)
+	assert.Equal(t, models.Flat{Id: 10, House_id: 1, Price: 100000, Rooms: 3, Status: "approved"}, entries[0].Flat)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unmet expectations: %v", err)
+	}
+}
@@ -1,25 +1,29 @@
 package house
 
 import (
-	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/delapaska/avito-rent/middleware"
 	"github.com/delapaska/avito-rent/models"
-	"github.com/delapaska/avito-rent/sender"
+	"github.com/delapaska/avito-rent/notification"
 	"github.com/delapaska/avito-rent/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
 type Handler struct {
-	store models.HouseStore
+	store    models.HouseStore
+	notifier *notification.Notifier
 }
 
-func NewHandler(store models.HouseStore) *Handler {
-	return &Handler{store: store}
+func NewHandler(store models.HouseStore, notifier *notification.Notifier) *Handler {
+	return &Handler{store: store, notifier: notifier}
 }
 
 func (h *Handler) RegisterRoutes(router *gin.Engine) {
@@ -28,6 +32,8 @@ func (h *Handler) RegisterRoutes(router *gin.Engine) {
 	moderationsOnly.Use(middleware.AuthMiddleware("moderator"))
 	{
 		moderationsOnly.POST("/house/create", h.handleCreateHouse)
+		moderationsOnly.POST("/house/:id/archive", h.handleArchiveHouse)
+		moderationsOnly.POST("/house/:id/restore", h.handleRestoreHouse)
 	}
 	allUsers := router.Group("/")
 	allUsers.Use(middleware.AuthMiddleware("moderator", "client"))
@@ -40,7 +46,7 @@ func (h *Handler) RegisterRoutes(router *gin.Engine) {
 // handleCreateHouse creates a new house
 // @Summary Create House
 // @Tags House
-// @Description Create a new house. Requires moderator access.
+// @Description Create a new house. The developer is given by developer_id or by name; a name that is not in /developers yet adds a developer. Floors, material, elevator and parking are optional. Requires moderator access.
 // @Accept json
 // @Produce json
 // @Security Bearer
@@ -98,11 +104,24 @@ func (h *Handler) handleCreateHouse(c *gin.Context) {
 		})
 		return
 	}
+	if payload.Developer != "" && payload.DeveloperID != nil {
+		utils.WriteError(c, http.StatusBadRequest, "pass either developer or developer_id")
+		return
+	}
 	house, err := h.store.CreateHouse(models.House{
-		Address:   payload.Address,
-		Year:      payload.Year,
-		Developer: payload.Developer,
+		Address:     payload.Address,
+		Year:        payload.Year,
+		Developer:   payload.Developer,
+		DeveloperID: payload.DeveloperID,
+		Floors:      payload.Floors,
+		Material:    payload.Material,
+		Elevator:    payload.Elevator,
+		Parking:     payload.Parking,
 	})
+	if errors.Is(err, models.ErrDeveloperNotFound) {
+		utils.WriteError(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		c.Header("Retry-After", "30")
 		utils.WriteJSON(c, http.StatusInternalServerError, gin.H{
@@ -113,19 +132,60 @@ func (h *Handler) handleCreateHouse(c *gin.Context) {
 		return
 	}
 
+	middleware.RecordAudit(c.Request.Context(), models.AuditHouseCreate, "house", house.Id, nil, house)
 	utils.WriteJSON(c, http.StatusCreated, house)
 }
 
+// parseFlatFilter reads the filter and the order of a house flat listing
+// from the query string.
+func parseFlatFilter(c *gin.Context) (models.FlatFilter, error) {
+	filter := models.FlatFilter{
+		ListingType: c.Query("listing_type"),
+		Sort:        c.Query("sort"),
+	}
+	if filter.ListingType != "" && filter.ListingType != models.ListingSale && filter.ListingType != models.ListingRent {
+		return filter, fmt.Errorf("listing_type must be sale or rent")
+	}
+	if filter.Sort != "" && filter.Sort != models.SortPricePerM2 && filter.Sort != models.SortPricePerM2Desc {
+		return filter, fmt.Errorf("sort must be price_per_m2 or -price_per_m2")
+	}
+
+	for name, bound := range map[string]**float64{
+		"price_per_m2_min": &filter.PricePerM2Min,
+		"price_per_m2_max": &filter.PricePerM2Max,
+	} {
+		s := c.Query(name)
+		if s == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+			return filter, fmt.Errorf("%s must be a non-negative number", name)
+		}
+		*bound = &v
+	}
+	if filter.PricePerM2Min != nil && filter.PricePerM2Max != nil && *filter.PricePerM2Min > *filter.PricePerM2Max {
+		return filter, fmt.Errorf("price_per_m2_min cannot be greater than price_per_m2_max")
+	}
+	return filter, nil
+}
+
 // @Summary Get House Flats
-// @Description Retrieve flats for a specific house. Requires authorization for both moderator and client.
+// @Description Retrieve flats for a specific house, optionally only those for sale or for rent or within a price per square meter range, and optionally sorted by price per square meter. Flats without an area are left out of the range and come last in the order. Archived flats are left out unless a moderator asks for them with include_archived. Requires authorization for both moderator and client.
 // @Tags House
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path string true "House ID"
+// @Param include_archived query bool false "Also return archived flats, moderators only"
+// @Param listing_type query string false "Only return flats for sale or for rent" Enums(sale, rent)
+// @Param price_per_m2_min query number false "Only return flats with at least this price per square meter"
+// @Param price_per_m2_max query number false "Only return flats with at most this price per square meter"
+// @Param sort query string false "Sort by price per square meter, ascending or, with a minus, descending" Enums(price_per_m2, -price_per_m2)
 // @Success 200 {object} utils.FlatsResponse "Flats retrieved"
 // @Failure 400 {object} utils.ErrorResponse "Bad request"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Archived flats requested by a client"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /house/{id} [get]
 func (h *Handler) handleGetHouseFlats(c *gin.Context) {
@@ -134,7 +194,22 @@ func (h *Handler) handleGetHouseFlats(c *gin.Context) {
 
 	userType := c.GetString("userType")
 
-	flats, err := h.store.GetHouseFlats(houseID, userType)
+	filter, err := parseFlatFilter(c)
+	if err != nil {
+		utils.WriteError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var flats []models.Flat
+	if c.Query("include_archived") == "true" {
+		if userType != "moderator" {
+			utils.WriteError(c, http.StatusForbidden, "only moderators can see archived flats")
+			return
+		}
+		flats, err = h.store.GetAllHouseFlats(houseID, filter)
+	} else {
+		flats, err = h.store.GetHouseFlats(houseID, userType, filter)
+	}
 	if err != nil {
 		c.Header("Retry-After", "30")
 		utils.WriteJSON(c, http.StatusInternalServerError, gin.H{
@@ -145,6 +220,11 @@ func (h *Handler) handleGetHouseFlats(c *gin.Context) {
 
 		return
 	}
+	if userType != "moderator" {
+		for i := range flats {
+			flats[i].HideModeration()
+		}
+	}
 
 	utils.WriteJSON(c, http.StatusOK,
 		gin.H{"flats": flats})
@@ -190,7 +270,13 @@ func (h *Handler) handleSubscribeHouse(c *gin.Context) {
 		return
 	}
 
-	err := h.store.AddSubscription(houseID, payload.Email)
+	subscription := models.Subscription{
+		HouseID:  houseID,
+		Email:    payload.Email,
+		Locale:   payload.Locale,
+		Delivery: payload.Delivery,
+	}
+	err := h.store.AddSubscription(subscription)
 	if err != nil {
 		c.Header("Retry-After", "30")
 		utils.WriteJSON(c, http.StatusInternalServerError, gin.H{
@@ -201,23 +287,81 @@ func (h *Handler) handleSubscribeHouse(c *gin.Context) {
 		return
 	}
 
+	middleware.RecordAudit(c.Request.Context(), models.AuditHouseSubscribe, "house", houseID, nil, subscription)
 	utils.WriteJSON(c, http.StatusCreated, gin.H{
 		"message":    "Subscription successful",
 		"request_id": requestId,
 		"code":       http.StatusCreated,
 	})
 
-	go h.notifyUser(houseID, payload.Email)
+	go h.notifier.NotifySubscribed(subscription)
 }
 
-func (h *Handler) notifyUser(houseID string, email string) {
-	ctx := context.Background()
-	sender := sender.New()
+// @Summary Archive House
+// @Description Archive a house together with its active flats. Archived flats are hidden from GET /house/{id} and cannot be moderated. Requires moderator access.
+// @Tags House
+// @Produce json
+// @Security Bearer
+// @Param id path int true "House ID"
+// @Success 200 {object} models.House "House archived"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "House not found"
+// @Failure 409 {object} utils.ErrorResponse "House is already archived"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /house/{id}/archive [post]
+func (h *Handler) handleArchiveHouse(c *gin.Context) {
+	h.setArchived(c, models.AuditHouseArchive, h.store.ArchiveHouse)
+}
+
+// @Summary Restore House
+// @Description Restore an archived house and the flats archived together with it. Flats archived by their owners before stay archived. Requires moderator access.
+// @Tags House
+// @Produce json
+// @Security Bearer
+// @Param id path int true "House ID"
+// @Success 200 {object} models.House "House restored"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "House not found"
+// @Failure 409 {object} utils.ErrorResponse "House is not archived"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /house/{id}/restore [post]
+func (h *Handler) handleRestoreHouse(c *gin.Context) {
+	h.setArchived(c, models.AuditHouseRestore, h.store.RestoreHouse)
+}
 
-	message := fmt.Sprintf("New flats are available in house %s. Check them out now!", houseID)
+func (h *Handler) setArchived(c *gin.Context, action string, update func(id int) (models.House, error)) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		utils.WriteError(c, http.StatusBadRequest, "invalid house id")
+		return
+	}
 
-	err := sender.SendEmail(ctx, email, message)
+	before, err := h.store.GetHousesByIDs([]int{id})
 	if err != nil {
-		fmt.Printf("Failed to send email to %s: %v\n", email, err)
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	house, err := update(id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		utils.WriteError(c, http.StatusNotFound, "house not found")
+		return
+	case errors.Is(err, models.ErrArchived):
+		utils.WriteError(c, http.StatusConflict, "house is already archived")
+		return
+	case errors.Is(err, models.ErrNotArchived):
+		utils.WriteError(c, http.StatusConflict, "house is not archived")
+		return
+	case err != nil:
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if len(before) > 0 {
+		middleware.RecordAudit(c.Request.Context(), action, "house", id, before[0], house)
 	}
+	utils.WriteJSON(c, http.StatusOK, house)
 }
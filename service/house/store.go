@@ -2,10 +2,14 @@ package house
 
 import (
 	"database/sql"
+	"errors"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/delapaska/avito-rent/models"
+	"github.com/lib/pq"
 )
 
 type Store struct {
@@ -19,19 +23,35 @@ func NewStore(db *sql.DB) *Store {
 func (s *Store) CreateHouse(house models.House) (models.House, error) {
 	log.Println("Create House")
 
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v\n", err)
+		return models.House{}, err
+	}
+	defer tx.Rollback()
+
 	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
 
+	var insertedHouse models.House
+	insertedHouse.DeveloperID, insertedHouse.Developer, err = resolveDeveloper(tx, house, currentTime)
+	if err != nil {
+		return models.House{}, err
+	}
+
 	query := `
-		INSERT INTO house (address, year, developer, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $4)
-		RETURNING id, address, year, developer, created_at, updated_at`
+		INSERT INTO house (address, year, developer_id, floors, material, elevator, parking, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $8)
+		RETURNING id, address, year, floors, COALESCE(material, ''), elevator, parking, created_at, updated_at`
 
-	var insertedHouse models.House
-	err := s.db.QueryRow(query, house.Address, house.Year, house.Developer, currentTime).Scan(
+	err = tx.QueryRow(query, house.Address, house.Year, insertedHouse.DeveloperID,
+		house.Floors, house.Material, house.Elevator, house.Parking, currentTime).Scan(
 		&insertedHouse.Id,
 		&insertedHouse.Address,
 		&insertedHouse.Year,
-		&insertedHouse.Developer,
+		&insertedHouse.Floors,
+		&insertedHouse.Material,
+		&insertedHouse.Elevator,
+		&insertedHouse.Parking,
 		&insertedHouse.Created_at,
 		&insertedHouse.Updated_at,
 	)
@@ -40,26 +60,120 @@ func (s *Store) CreateHouse(house models.House) (models.House, error) {
 		return models.House{}, err
 	}
 
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v\n", err)
+		return models.House{}, err
+	}
+
 	return insertedHouse, nil
 }
 
-func (s *Store) GetHouseFlats(houseID string, userRole string) ([]models.Flat, error) {
-	var query string
+// resolveDeveloper finds the developer of the house by id or, when only a
+// name is given, by the name regardless of case, adding a developer with a
+// new name. Houses without a developer get a nil id.
+func resolveDeveloper(tx *sql.Tx, house models.House, currentTime string) (*int, string, error) {
+	var id int
+	var name string
+	switch {
+	case house.DeveloperID != nil:
+		err := tx.QueryRow(`SELECT id, name FROM developers WHERE id = $1`, *house.DeveloperID).Scan(&id, &name)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", models.ErrDeveloperNotFound
+		}
+		if err != nil {
+			log.Printf("Error executing query: %v\n", err)
+			return nil, "", err
+		}
+	case strings.TrimSpace(house.Developer) != "":
+		query := `
+			INSERT INTO developers (name, created_at, updated_at)
+			VALUES ($1, $2, $2)
+			ON CONFLICT ((lower(name))) DO UPDATE SET name = developers.name
+			RETURNING id, name`
+		if err := tx.QueryRow(query, strings.TrimSpace(house.Developer), currentTime).Scan(&id, &name); err != nil {
+			log.Printf("Error executing query: %v\n", err)
+			return nil, "", err
+		}
+	default:
+		return nil, "", nil
+	}
+	return &id, name, nil
+}
+
+// flatColumns are the columns scanFlat reads.
+const flatColumns = `id, house_id, price, rooms, status, number, floor, total_area, living_area, balcony,
+	listing_type, COALESCE(rent_period, ''), deposit, commission, utilities_included, price_per_m2, archived_at,
+	COALESCE(description, ''), high_risk, rule_hits, COALESCE(decline_reason, ''), expires_at, final_price, closed_at`
+
+func scanFlat(rows *sql.Rows) (models.Flat, error) {
+	var flat models.Flat
+	var archivedAt, expiresAt, closedAt sql.NullTime
+	err := rows.Scan(&flat.Id, &flat.House_id, &flat.Price, &flat.Rooms, &flat.Status,
+		&flat.Number, &flat.Floor, &flat.TotalArea, &flat.LivingArea, &flat.Balcony,
+		&flat.ListingType, &flat.RentPeriod, &flat.Deposit, &flat.Commission, &flat.UtilitiesIncluded, &flat.PricePerM2, &archivedAt,
+		&flat.Description, &flat.HighRisk, &flat.RuleHits, &flat.DeclineReason, &expiresAt,
+		&flat.FinalPrice, &closedAt)
+	if archivedAt.Valid {
+		flat.ArchivedAt = &archivedAt.Time
+	}
+	if expiresAt.Valid {
+		flat.ExpiresAt = &expiresAt.Time
+	}
+	if closedAt.Valid {
+		flat.ClosedAt = &closedAt.Time
+	}
+	return flat, err
+}
+
+// filterFlats adds the conditions and the order of filter to a flat query
+// that ends with its WHERE clause. defaultOrder is used when filter does not
+// sort.
+func filterFlats(query string, args []interface{}, filter models.FlatFilter, defaultOrder string) (string, []interface{}) {
+	if filter.ListingType != "" {
+		args = append(args, filter.ListingType)
+		query += ` AND listing_type = $` + strconv.Itoa(len(args))
+	}
+	if filter.PricePerM2Min != nil {
+		args = append(args, *filter.PricePerM2Min)
+		query += ` AND price_per_m2 >= $` + strconv.Itoa(len(args))
+	}
+	if filter.PricePerM2Max != nil {
+		args = append(args, *filter.PricePerM2Max)
+		query += ` AND price_per_m2 <= $` + strconv.Itoa(len(args))
+	}
+
+	switch filter.Sort {
+	case models.SortPricePerM2:
+		query += ` ORDER BY price_per_m2 ASC NULLS LAST, id`
+	case models.SortPricePerM2Desc:
+		query += ` ORDER BY price_per_m2 DESC NULLS LAST, id`
+	default:
+		query += defaultOrder
+	}
+	return query, args
+}
+
+// GetHouseFlats returns the active flats of the house. Moderators get flats
+// pre-moderation marked as high risk first.
+func (s *Store) GetHouseFlats(houseID string, userRole string, filter models.FlatFilter) ([]models.Flat, error) {
+	var query, defaultOrder string
 	var args []interface{}
 
 	if userRole == "moderator" {
 		query = `
-			SELECT id, house_id, price, rooms, status
+			SELECT ` + flatColumns + `
 			FROM flat
-			WHERE house_id = $1`
+			WHERE house_id = $1 AND archived_at IS NULL`
 		args = append(args, houseID)
+		defaultOrder = ` ORDER BY high_risk DESC, id`
 	} else {
 		query = `
-			SELECT id, house_id, price, rooms, status
+			SELECT ` + flatColumns + `
 			FROM flat
-			WHERE house_id = $1 AND status = 'approved'`
+			WHERE house_id = $1 AND status = 'approved' AND archived_at IS NULL`
 		args = append(args, houseID)
 	}
+	query, args = filterFlats(query, args, filter, defaultOrder)
 
 	rows, err := s.db.Query(query, args...)
 	if err != nil {
@@ -70,8 +184,8 @@ func (s *Store) GetHouseFlats(houseID string, userRole string) ([]models.Flat, e
 
 	var flats []models.Flat
 	for rows.Next() {
-		var flat models.Flat
-		if err := rows.Scan(&flat.Id, &flat.House_id, &flat.Price, &flat.Rooms, &flat.Status); err != nil {
+		flat, err := scanFlat(rows)
+		if err != nil {
 			log.Printf("Error scanning row: %v\n", err)
 			return nil, err
 		}
@@ -86,9 +200,422 @@ func (s *Store) GetHouseFlats(houseID string, userRole string) ([]models.Flat, e
 	return flats, nil
 }
 
-func (s *Store) AddSubscription(houseID, email string) error {
+// GetAllHouseFlats returns every flat of the house including archived ones,
+// for moderators.
+func (s *Store) GetAllHouseFlats(houseID string, filter models.FlatFilter) ([]models.Flat, error) {
+	query := `
+		SELECT ` + flatColumns + `
+		FROM flat
+		WHERE house_id = $1`
+	query, args := filterFlats(query, []interface{}{houseID}, filter, ` ORDER BY id`)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	flats := []models.Flat{}
+	for rows.Next() {
+		flat, err := scanFlat(rows)
+		if err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		flats = append(flats, flat)
+	}
+
+	return flats, rows.Err()
+}
+
+// ArchiveHouse archives the house together with its active flats. The flats
+// get the same archived_at as the house, which is how RestoreHouse tells them
+// from flats their owners archived before.
+func (s *Store) ArchiveHouse(id int) (models.House, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v\n", err)
+		return models.House{}, err
+	}
+	defer tx.Rollback()
+
+	var archivedAt sql.NullTime
+	if err := tx.QueryRow(`SELECT archived_at FROM house WHERE id = $1 FOR UPDATE`, id).Scan(&archivedAt); err != nil {
+		return models.House{}, err
+	}
+	if archivedAt.Valid {
+		return models.House{}, models.ErrArchived
+	}
 
 	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
-	_, err := s.db.Exec("INSERT INTO subscriptions (house_id, email, created_at) VALUES ($1, $2, $3)", houseID, email, currentTime)
+	_, err = tx.Exec(`UPDATE flat SET archived_at = $1 WHERE house_id = $2 AND archived_at IS NULL`, currentTime, id)
+	if err != nil {
+		log.Printf("Error executing update query: %v\n", err)
+		return models.House{}, err
+	}
+
+	return setHouseArchivedAt(tx, id, currentTime)
+}
+
+// RestoreHouse restores the house and the flats archived together with it.
+func (s *Store) RestoreHouse(id int) (models.House, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v\n", err)
+		return models.House{}, err
+	}
+	defer tx.Rollback()
+
+	var archivedAt sql.NullTime
+	if err := tx.QueryRow(`SELECT archived_at FROM house WHERE id = $1 FOR UPDATE`, id).Scan(&archivedAt); err != nil {
+		return models.House{}, err
+	}
+	if !archivedAt.Valid {
+		return models.House{}, models.ErrNotArchived
+	}
+
+	_, err = tx.Exec(`UPDATE flat SET archived_at = NULL WHERE house_id = $1 AND archived_at = $2`, id, archivedAt.Time)
+	if err != nil {
+		log.Printf("Error executing update query: %v\n", err)
+		return models.House{}, err
+	}
+
+	return setHouseArchivedAt(tx, id, nil)
+}
+
+// setHouseArchivedAt updates archived_at of the house and commits tx.
+func setHouseArchivedAt(tx *sql.Tx, id int, archivedAt any) (models.House, error) {
+	query := `
+		UPDATE house
+		SET archived_at = $1
+		WHERE id = $2
+		RETURNING id, address, year, COALESCE((SELECT name FROM developers WHERE id = house.developer_id), ''), developer_id,
+			floors, COALESCE(material, ''), elevator, parking, created_at, updated_at, archived_at`
+
+	var house models.House
+	var houseArchivedAt sql.NullTime
+	err := tx.QueryRow(query, archivedAt, id).Scan(
+		&house.Id, &house.Address, &house.Year, &house.Developer, &house.DeveloperID,
+		&house.Floors, &house.Material, &house.Elevator, &house.Parking, &house.Created_at, &house.Updated_at, &houseArchivedAt)
+	if err != nil {
+		log.Printf("Error executing update query: %v\n", err)
+		return models.House{}, err
+	}
+	if houseArchivedAt.Valid {
+		house.ArchivedAt = &houseArchivedAt.Time
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v\n", err)
+		return models.House{}, err
+	}
+
+	return house, nil
+}
+
+func (s *Store) AddSubscription(subscription models.Subscription) error {
+	if subscription.Locale == "" {
+		subscription.Locale = models.LocaleRU
+	}
+	if subscription.Delivery == "" {
+		subscription.Delivery = models.DeliveryInstant
+	}
+
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	_, err := s.db.Exec("INSERT INTO subscriptions (house_id, email, locale, delivery, created_at) VALUES ($1, $2, $3, $4, $5)",
+		subscription.HouseID, subscription.Email, subscription.Locale, subscription.Delivery, currentTime)
 	return err
 }
+
+func (s *Store) GetHousesByIDs(ids []int) ([]models.House, error) {
+	query := `
+		SELECT h.id, h.address, h.year, COALESCE(d.name, ''), h.developer_id,
+			h.floors, COALESCE(h.material, ''), h.elevator, h.parking, h.created_at, h.updated_at, h.archived_at
+		FROM house h
+		LEFT JOIN developers d ON d.id = h.developer_id
+		WHERE h.id = ANY($1)`
+
+	rows, err := s.db.Query(query, pq.Array(ids))
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var houses []models.House
+	for rows.Next() {
+		var house models.House
+		var archivedAt sql.NullTime
+		if err := rows.Scan(&house.Id, &house.Address, &house.Year, &house.Developer, &house.DeveloperID,
+			&house.Floors, &house.Material, &house.Elevator, &house.Parking, &house.Created_at, &house.Updated_at, &archivedAt); err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		if archivedAt.Valid {
+			house.ArchivedAt = &archivedAt.Time
+		}
+		houses = append(houses, house)
+	}
+
+	return houses, rows.Err()
+}
+
+func (s *Store) GetFlatsByHouseIDs(houseIDs []int, userRole string) ([]models.Flat, error) {
+	query := `
+		SELECT id, house_id, price, rooms, status, number, floor, total_area, living_area, balcony, price_per_m2,
+			listing_type, COALESCE(rent_period, ''), deposit, commission, utilities_included, expires_at, final_price
+		FROM flat
+		WHERE house_id = ANY($1) AND archived_at IS NULL`
+	if userRole != "moderator" {
+		query += ` AND status = 'approved'`
+	}
+	query += ` ORDER BY id`
+
+	rows, err := s.db.Query(query, pq.Array(houseIDs))
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flats []models.Flat
+	for rows.Next() {
+		var flat models.Flat
+		var expiresAt sql.NullTime
+		if err := rows.Scan(&flat.Id, &flat.House_id, &flat.Price, &flat.Rooms, &flat.Status,
+			&flat.Number, &flat.Floor, &flat.TotalArea, &flat.LivingArea, &flat.Balcony, &flat.PricePerM2,
+			&flat.ListingType, &flat.RentPeriod, &flat.Deposit, &flat.Commission, &flat.UtilitiesIncluded,
+			&expiresAt, &flat.FinalPrice); err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		if expiresAt.Valid {
+			flat.ExpiresAt = &expiresAt.Time
+		}
+		flats = append(flats, flat)
+	}
+
+	return flats, rows.Err()
+}
+
+func (s *Store) GetHouseStats(houseIDs []int) ([]models.HouseStats, error) {
+	query := `
+		SELECT house_id, COUNT(*),
+			COUNT(*) FILTER (WHERE status = 'created'),
+			COUNT(*) FILTER (WHERE status = 'on moderation'),
+			COUNT(*) FILTER (WHERE status = 'approved'),
+			COUNT(*) FILTER (WHERE status = 'declined'),
+			COUNT(*) FILTER (WHERE status = 'expired'),
+			COUNT(*) FILTER (WHERE status = 'rented'),
+			COUNT(*) FILTER (WHERE status = 'sold'),
+			MIN(price) FILTER (WHERE status = 'approved'),
+			MAX(price) FILTER (WHERE status = 'approved'),
+			AVG(price) FILTER (WHERE status = 'approved'),
+			MIN(price_per_m2) FILTER (WHERE status = 'approved'),
+			MAX(price_per_m2) FILTER (WHERE status = 'approved'),
+			ROUND(AVG(price_per_m2) FILTER (WHERE status = 'approved'), 2),
+			AVG(final_price) FILTER (WHERE status = 'sold'),
+			AVG(final_price) FILTER (WHERE status = 'rented' AND rent_period = 'monthly')
+		FROM flat
+		WHERE house_id = ANY($1) AND archived_at IS NULL
+		GROUP BY house_id`
+
+	rows, err := s.db.Query(query, pq.Array(houseIDs))
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []models.HouseStats
+	for rows.Next() {
+		var st models.HouseStats
+		if err := rows.Scan(&st.HouseID, &st.Flats, &st.Created, &st.OnModeration, &st.Approved, &st.Declined,
+			&st.Expired, &st.Rented, &st.Sold, &st.MinPrice, &st.MaxPrice, &st.AvgPrice,
+			&st.MinPricePerM2, &st.MaxPricePerM2, &st.AvgPricePerM2, &st.AvgSoldPrice, &st.AvgMonthlyRent); err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		stats = append(stats, st)
+	}
+
+	return stats, rows.Err()
+}
+
+func (s *Store) GetSubscriptions(houseIDs []int, email string) ([]models.Subscription, error) {
+	ids := make([]string, len(houseIDs))
+	for i, id := range houseIDs {
+		ids[i] = strconv.Itoa(id)
+	}
+
+	query := `
+		SELECT DISTINCT ON (house_id) id, house_id, email, locale, delivery, created_at
+		FROM subscriptions
+		WHERE email = $1 AND house_id = ANY($2)
+		ORDER BY house_id, created_at`
+
+	rows, err := s.db.Query(query, email, pq.Array(ids))
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []models.Subscription
+	for rows.Next() {
+		var sub models.Subscription
+		if err := rows.Scan(&sub.ID, &sub.HouseID, &sub.Email, &sub.Locale, &sub.Delivery, &sub.CreatedAt); err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		subscriptions = append(subscriptions, sub)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// GetHouseSubscribers returns one subscription per email, with the locale of
+// the latest one.
+func (s *Store) GetHouseSubscribers(houseID int) ([]models.Subscription, error) {
+	query := `
+		SELECT DISTINCT ON (email) id, house_id, email, locale, delivery, created_at
+		FROM subscriptions
+		WHERE house_id = $1
+		ORDER BY email, created_at DESC`
+
+	rows, err := s.db.Query(query, strconv.Itoa(houseID))
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []models.Subscription
+	for rows.Next() {
+		var sub models.Subscription
+		if err := rows.Scan(&sub.ID, &sub.HouseID, &sub.Email, &sub.Locale, &sub.Delivery, &sub.CreatedAt); err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		subscriptions = append(subscriptions, sub)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (s *Store) GetLastDigestDay() (*time.Time, error) {
+	var day sql.NullTime
+	if err := s.db.QueryRow("SELECT max(day) FROM subscription_digests").Scan(&day); err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	if !day.Valid {
+		return nil, nil
+	}
+	return &day.Time, nil
+}
+
+// ClaimDigest takes over a claim older than lease, left by an instance that
+// stopped before sending the digest.
+func (s *Store) ClaimDigest(day time.Time, email string, lease time.Duration) (bool, error) {
+	now := time.Now().UTC()
+	query := `
+		INSERT INTO digest_deliveries (day, email, claimed_at) VALUES ($1, $2, $3)
+		ON CONFLICT (day, email) DO UPDATE SET claimed_at = EXCLUDED.claimed_at
+		WHERE digest_deliveries.sent_at IS NULL AND digest_deliveries.claimed_at < $4`
+
+	res, err := s.db.Exec(query, day.Format("2006-01-02"), email,
+		now.Format("2006-01-02T15:04:05Z"), now.Add(-lease).Format("2006-01-02T15:04:05Z"))
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (s *Store) MarkDigestSent(day time.Time, email string) error {
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	_, err := s.db.Exec("UPDATE digest_deliveries SET sent_at = $1 WHERE day = $2 AND email = $3",
+		currentTime, day.Format("2006-01-02"), email)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+	}
+	return err
+}
+
+func (s *Store) ReleaseDigest(day time.Time, email string) error {
+	_, err := s.db.Exec("DELETE FROM digest_deliveries WHERE day = $1 AND email = $2 AND sent_at IS NULL",
+		day.Format("2006-01-02"), email)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+	}
+	return err
+}
+
+// CompleteDigest only records the day once every digest of it has been sent,
+// by this instance or another one.
+func (s *Store) CompleteDigest(day time.Time, digests int) error {
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	query := `
+		INSERT INTO subscription_digests (day, created_at)
+		SELECT $1, $2
+		WHERE (SELECT count(*) FROM digest_deliveries WHERE day = $1 AND sent_at IS NOT NULL) >= $3
+		ON CONFLICT DO NOTHING`
+
+	_, err := s.db.Exec(query, day.Format("2006-01-02"), currentTime, digests)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+	}
+	return err
+}
+
+// GetDigestEntries returns flats approved in [from, to) for every daily
+// subscriber of their house, ordered by email, house and flat. A flat
+// approved several times is taken once, from its latest event.
+func (s *Store) GetDigestEntries(from, to time.Time) ([]models.DigestEntry, error) {
+	query := `
+		SELECT sub.email, sub.locale, h.id, h.address, h.year, COALESCE(d.name, ''), h.developer_id, h.created_at, h.updated_at,
+			f.id, f.house_id, f.price, f.rooms, f.status
+		FROM (
+			SELECT DISTINCT ON (house_id, email) house_id, email, locale, delivery
+			FROM subscriptions
+			ORDER BY house_id, email, created_at DESC
+		) sub
+		JOIN (
+			SELECT DISTINCT ON ((flat->>'id')::int) house_id, (flat->>'id')::int AS flat_id
+			FROM house_events
+			WHERE type = $2 AND created_at >= $3 AND created_at < $4
+			ORDER BY (flat->>'id')::int, id DESC
+		) e ON e.house_id::varchar = sub.house_id
+		JOIN house h ON h.id = e.house_id
+		LEFT JOIN developers d ON d.id = h.developer_id
+		JOIN flat f ON f.id = e.flat_id
+		WHERE sub.delivery = $1
+			AND f.status = $5
+			AND f.archived_at IS NULL
+		ORDER BY sub.email, h.id, f.id`
+
+	rows, err := s.db.Query(query, models.DeliveryDaily, models.EventFlatApproved,
+		from.UTC().Format("2006-01-02T15:04:05Z"), to.UTC().Format("2006-01-02T15:04:05Z"), models.StatusApproved)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.DigestEntry
+	for rows.Next() {
+		var e models.DigestEntry
+		if err := rows.Scan(&e.Email, &e.Locale,
+			&e.House.Id, &e.House.Address, &e.House.Year, &e.House.Developer, &e.House.DeveloperID, &e.House.Created_at, &e.House.Updated_at,
+			&e.Flat.Id, &e.Flat.House_id, &e.Flat.Price, &e.Flat.Rooms, &e.Flat.Status); err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
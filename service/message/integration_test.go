@@ -0,0 +1,248 @@
+package message
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/notification"
+	"github.com/delapaska/avito-rent/service/auth"
+	"github.com/delapaska/avito-rent/service/flat"
+	"github.com/delapaska/avito-rent/service/house"
+	"github.com/delapaska/avito-rent/service/search"
+	"github.com/delapaska/avito-rent/service/viewing"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	flatRows         = []string{"id", "house_id", "price", "rooms", "status", "owner_id", "archived_at", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}
+	conversationRows = []string{"id", "client_id", "owner_id", "created_at", "last_message_at", "reported_at", "report_reason",
+		"flat_id", "house_id", "price", "rooms", "status", "unread"}
+	messageRows = []string{"id", "conversation_id", "sender_id", "body", "created_at", "read_at"}
+)
+
+// newHandler builds a handler whose background emails run against a separate
+// mock database, so they do not consume expectations.
+func newHandler(t *testing.T) (*Handler, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	notifyDB, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	t.Cleanup(func() { notifyDB.Close() })
+
+	handler := NewHandler(NewStore(db), flat.NewStore(db), auth.NewStore(notifyDB),
+		notification.NewNotifier(house.NewStore(notifyDB), search.NewStore(notifyDB), viewing.NewStore(notifyDB)))
+	return handler, mock
+}
+
+func newRouter(handler *Handler, userID uuid.UUID, userType string) *gin.Engine {
+	r := gin.Default()
+	setUser := func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("userType", userType)
+	}
+	r.POST("/flat/:id/messages", setUser, handler.handleContactOwner)
+	r.GET("/conversations", setUser, handler.handleGetConversations)
+	r.GET("/conversations/:id/messages", setUser, handler.handleGetMessages)
+	r.POST("/conversations/:id/messages", setUser, handler.handleSendMessage)
+	return r
+}
+
+func serve(t *testing.T, r *gin.Engine, method, path string, payload any) *httptest.ResponseRecorder {
+	var body []byte
+	if payload != nil {
+		body, _ = json.Marshal(payload)
+	}
+	req, err := http.NewRequest(method, path, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestHandleContactOwner(t *testing.T) {
+	handler, mock := newHandler(t)
+	ownerID, clientID := uuid.New(), uuid.New()
+
+	t.Run("should start a conversation with the owner", func(t *testing.T) {
+		r := newRouter(handler, clientID, "client")
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, .* FROM flat WHERE id = \$1`).
+			WithArgs(10).
+			WillReturnRows(flatRow(models.StatusApproved, ownerID))
+		mock.ExpectQuery(`INSERT INTO conversations \(flat_id, client_id, owner_id, created_at, last_message_at\)`).
+			WithArgs(10, clientID, ownerID, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO messages \(conversation_id, sender_id, body, created_at\)`).
+			WithArgs(1, clientID, "Квартира ещё сдаётся?", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "conversation_id", "sender_id", "body", "created_at"}).
+				AddRow(42, 1, clientID, "Квартира ещё сдаётся?", time.Now()))
+		mock.ExpectExec(`UPDATE conversations\s+SET last_message_at = \$1`).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery(`FROM conversations c\s+JOIN flat f ON f.id = c.flat_id\s+WHERE c.id = \$2`).
+			WithArgs(ownerID, 1).
+			WillReturnRows(sqlmock.NewRows(conversationRows).
+				AddRow(1, clientID, ownerID, time.Now(), time.Now(), nil, "", 10, 1, 12500000, 3, models.StatusApproved, 1))
+
+		recorder := serve(t, r, "POST", "/flat/10/messages", models.MessagePayload{Body: "  Квартира ещё сдаётся?  "})
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		var message models.Message
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &message))
+		assert.Equal(t, int64(42), message.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should hide flats that are not approved from clients", func(t *testing.T) {
+		r := newRouter(handler, clientID, "client")
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, .* FROM flat WHERE id = \$1`).
+			WithArgs(10).
+			WillReturnRows(flatRow(models.StatusCreated, ownerID))
+
+		recorder := serve(t, r, "POST", "/flat/10/messages", models.MessagePayload{Body: "Здравствуйте"})
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject flats without an owner and messages to yourself", func(t *testing.T) {
+		for _, owner := range []any{nil, clientID} {
+			r := newRouter(handler, clientID, "client")
+			mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, .* FROM flat WHERE id = \$1`).
+				WithArgs(10).
+				WillReturnRows(flatRow(models.StatusApproved, owner))
+
+			recorder := serve(t, r, "POST", "/flat/10/messages", models.MessagePayload{Body: "Здравствуйте"})
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleGetMessages(t *testing.T) {
+	handler, mock := newHandler(t)
+	ownerID, clientID := uuid.New(), uuid.New()
+	conversation := func(userID uuid.UUID, reportedAt any, unread int) {
+		mock.ExpectQuery(`FROM conversations c\s+JOIN flat f ON f.id = c.flat_id\s+WHERE c.id = \$2`).
+			WithArgs(userID, 1).
+			WillReturnRows(sqlmock.NewRows(conversationRows).
+				AddRow(1, clientID, ownerID, time.Now(), time.Now(), reportedAt, "", 10, 1, 12500000, 3, models.StatusApproved, unread))
+	}
+
+	t.Run("should page messages and mark them as read", func(t *testing.T) {
+		r := newRouter(handler, ownerID, "client")
+		conversation(ownerID, nil, 2)
+		mock.ExpectQuery(`FROM messages\s+WHERE conversation_id = \$1 AND \(\$2 = 0 OR id < \$2\)\s+ORDER BY id DESC\s+LIMIT \$3`).
+			WithArgs(1, int64(40), 2).
+			WillReturnRows(sqlmock.NewRows(messageRows).
+				AddRow(39, 1, clientID, "Когда можно посмотреть?", time.Now(), nil).
+				AddRow(38, 1, clientID, "Здравствуйте", time.Now(), nil))
+		mock.ExpectExec(`UPDATE messages\s+SET read_at = \$1`).
+			WithArgs(sqlmock.AnyArg(), 1, ownerID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		recorder := serve(t, r, "GET", "/conversations/1/messages?limit=2&before=40", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var response struct {
+			Messages []models.Message `json:"messages"`
+		}
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		assert.Len(t, response.Messages, 2)
+		assert.Equal(t, int64(39), response.Messages[0].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should hide conversations of other users", func(t *testing.T) {
+		stranger := uuid.New()
+		r := newRouter(handler, stranger, "client")
+		conversation(stranger, nil, 0)
+
+		recorder := serve(t, r, "GET", "/conversations/1/messages", nil)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should let moderators read reported conversations without marking them", func(t *testing.T) {
+		moderatorID := uuid.New()
+		r := newRouter(handler, moderatorID, "moderator")
+		conversation(moderatorID, time.Now(), 3)
+		mock.ExpectQuery(`FROM messages`).
+			WithArgs(1, int64(0), defaultMessageLimit).
+			WillReturnRows(sqlmock.NewRows(messageRows))
+
+		recorder := serve(t, r, "GET", "/conversations/1/messages", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should hide unreported conversations from moderators", func(t *testing.T) {
+		moderatorID := uuid.New()
+		r := newRouter(handler, moderatorID, "moderator")
+		conversation(moderatorID, nil, 3)
+
+		recorder := serve(t, r, "GET", "/conversations/1/messages", nil)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject an invalid limit", func(t *testing.T) {
+		r := newRouter(handler, ownerID, "client")
+
+		recorder := serve(t, r, "GET", "/conversations/1/messages?limit=500", nil)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestHandleGetConversations(t *testing.T) {
+	handler, mock := newHandler(t)
+	userID := uuid.New()
+	r := newRouter(handler, userID, "client")
+
+	t.Run("should sum unread messages", func(t *testing.T) {
+		mock.ExpectQuery(`WHERE c.client_id = \$1 OR c.owner_id = \$1`).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(conversationRows).
+				AddRow(1, userID, uuid.New(), time.Now(), time.Now(), nil, "", 10, 1, 12500000, 3, models.StatusApproved, 2).
+				AddRow(2, uuid.New(), userID, time.Now(), time.Now(), nil, "", 11, 1, 9000000, 2, models.StatusApproved, 1))
+
+		recorder := serve(t, r, "GET", "/conversations", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var response struct {
+			Conversations []models.Conversation `json:"conversations"`
+			Unread        int                   `json:"unread"`
+		}
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		assert.Len(t, response.Conversations, 2)
+		assert.Equal(t, 3, response.Unread)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// flatRow is flat 10 of house 1 as the flat store selects it.
+func flatRow(status string, ownerID any) *sqlmock.Rows {
+	return sqlmock.NewRows(flatRows).
+		AddRow(10, 1, 12500000, 3, status, ownerID, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", nil, nil, nil)
+}
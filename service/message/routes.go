@@ -0,0 +1,407 @@
+package message
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/notification"
+	"github.com/delapaska/avito-rent/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultMessageLimit = 50
+	maxMessageLimit     = 200
+)
+
+type Handler struct {
+	store    models.MessageStore
+	flats    models.FlatStore
+	users    models.UserStore
+	notifier *notification.Notifier
+}
+
+func NewHandler(store models.MessageStore, flats models.FlatStore, users models.UserStore, notifier *notification.Notifier) *Handler {
+	return &Handler{store: store, flats: flats, users: users, notifier: notifier}
+}
+
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+
+	moderationsOnly := router.Group("/")
+	moderationsOnly.Use(middleware.AuthMiddleware("moderator"))
+	{
+		moderationsOnly.GET("/conversations/reported", h.handleGetReportedConversations)
+	}
+
+	allUsers := router.Group("/")
+	allUsers.Use(middleware.AuthMiddleware("moderator", "client"))
+	{
+		allUsers.POST("/flat/:id/messages", h.handleContactOwner)
+		allUsers.GET("/conversations", h.handleGetConversations)
+		allUsers.GET("/conversations/:id/messages", h.handleGetMessages)
+		allUsers.POST("/conversations/:id/messages", h.handleSendMessage)
+		allUsers.POST("/conversations/:id/report", h.handleReportConversation)
+	}
+}
+
+// pathID parses the :id path parameter, writing 400 when it is invalid.
+func pathID(c *gin.Context, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		utils.WriteError(c, http.StatusBadRequest, "invalid "+name+" id")
+		return 0, false
+	}
+	return id, true
+}
+
+func isParticipant(conversation models.Conversation, userID uuid.UUID) bool {
+	return conversation.ClientID == userID || conversation.OwnerID == userID
+}
+
+// parseMessage reads and validates the payload, writing 400 when it is invalid.
+func parseMessage(c *gin.Context) (string, bool) {
+	var payload models.MessagePayload
+	if err := utils.ParseJSON(c, &payload); err != nil {
+		utils.WriteError(c, http.StatusBadRequest, err.Error())
+		return "", false
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		errors := err.(validator.ValidationErrors)
+		utils.WriteError(c, http.StatusBadRequest, utils.FormatValidationError(errors))
+		return "", false
+	}
+
+	body := strings.TrimSpace(payload.Body)
+	if body == "" {
+		utils.WriteError(c, http.StatusBadRequest, "body cannot be empty")
+		return "", false
+	}
+	return body, true
+}
+
+// recordMessage adds the message to the audit log without its text.
+func recordMessage(c *gin.Context, message models.Message) {
+	middleware.RecordAudit(c.Request.Context(), models.AuditMessageSend, "message", message.ID, nil, gin.H{
+		"id":              message.ID,
+		"conversation_id": message.ConversationID,
+		"sender_id":       message.SenderID,
+	})
+}
+
+// participantConversation loads the conversation of the :id path parameter.
+// Conversations the user does not take part in are reported as missing,
+// except reported ones for moderators when allowModerators is set.
+func (h *Handler) participantConversation(c *gin.Context, userID uuid.UUID, allowModerators bool) (models.Conversation, bool) {
+	id, ok := pathID(c, "conversation")
+	if !ok {
+		return models.Conversation{}, false
+	}
+
+	conversation, err := h.store.GetConversation(id, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return models.Conversation{}, false
+	}
+	moderating := allowModerators && c.GetString("userType") == "moderator" && conversation.ReportedAt != nil
+	if errors.Is(err, sql.ErrNoRows) || (!isParticipant(conversation, userID) && !moderating) {
+		utils.WriteError(c, http.StatusNotFound, "conversation not found")
+		return models.Conversation{}, false
+	}
+	return conversation, true
+}
+
+// notifyRecipient emails the recipient about a message in the background when
+// it is the only one they have not read, so an active conversation does not
+// flood their inbox. Users from /dummyLogin have no email and are skipped.
+func (h *Handler) notifyRecipient(conversationID int, recipientID uuid.UUID, message models.Message) {
+	conversation, err := h.store.GetConversation(conversationID, recipientID)
+	if err != nil {
+		log.Printf("Failed to get conversation %d: %v\n", conversationID, err)
+		return
+	}
+	if conversation.Unread != 1 {
+		return
+	}
+
+	go func() {
+		user, err := h.users.GetUserById(recipientID)
+		if err != nil || user == nil || user.Email == "" {
+			return
+		}
+		h.notifier.NotifyMessageReceived(user.Email, conversation, message)
+	}()
+}
+
+// @Summary Contact Owner
+// @Description Send a message to the client who created the flat. The first message starts a conversation about the flat, later ones are added to it. Clients can only contact owners of approved flats. Requires authorization for both moderator and client.
+// @Tags Messages
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Flat ID"
+// @Param request body models.MessagePayload true "Message"
+// @Success 201 {object} models.Message "Message sent"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Flat not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /flat/{id}/messages [post]
+func (h *Handler) handleContactOwner(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := pathID(c, "flat")
+	if !ok {
+		return
+	}
+
+	flat, err := h.flats.GetFlat(id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && flat.ArchivedAt != nil) ||
+		(err == nil && c.GetString("userType") != "moderator" && flat.Status != models.StatusApproved) {
+		utils.WriteError(c, http.StatusNotFound, "flat not found")
+		return
+	}
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if flat.OwnerID == nil {
+		utils.WriteError(c, http.StatusBadRequest, "the flat has no owner to contact")
+		return
+	}
+	if *flat.OwnerID == userID {
+		utils.WriteError(c, http.StatusBadRequest, "owners reply in the conversations of their flats")
+		return
+	}
+
+	body, ok := parseMessage(c)
+	if !ok {
+		return
+	}
+
+	conversationID, err := h.store.StartConversation(flat.Id, userID, *flat.OwnerID)
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	message, err := h.store.AddMessage(models.Message{ConversationID: conversationID, SenderID: userID, Body: body})
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	recordMessage(c, message)
+	utils.WriteJSON(c, http.StatusCreated, message)
+
+	h.notifyRecipient(conversationID, *flat.OwnerID, message)
+}
+
+// @Summary Get Conversations
+// @Description Conversations the current user takes part in as a client or as the owner of the flat, latest activity first, with the number of unread messages in each and in total. Requires authorization for both moderator and client.
+// @Tags Messages
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} utils.ConversationsResponse "Conversations retrieved"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /conversations [get]
+func (h *Handler) handleGetConversations(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	conversations, err := h.store.GetConversations(userID)
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	unread := 0
+	for _, conversation := range conversations {
+		unread += conversation.Unread
+	}
+
+	utils.WriteJSON(c, http.StatusOK, gin.H{"conversations": conversations, "unread": unread})
+}
+
+// @Summary Get Reported Conversations
+// @Description Conversations reported for abuse, latest report first. Unread counts the messages their recipient has not read yet. Requires moderator access.
+// @Tags Messages
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} utils.ConversationsResponse "Conversations retrieved"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /conversations/reported [get]
+func (h *Handler) handleGetReportedConversations(c *gin.Context) {
+	conversations, err := h.store.GetReportedConversations()
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(c, http.StatusOK, gin.H{"conversations": conversations})
+}
+
+// @Summary Get Messages
+// @Description Messages of the conversation, newest first. Pass the id of the oldest received message as before to get the previous page. Reading marks the messages sent to the current user as read. Moderators can read conversations reported for abuse.
+// @Tags Messages
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Conversation ID"
+// @Param limit query int false "Maximum number of messages, 50 by default and at most 200"
+// @Param before query int false "Only return messages older than this message id"
+// @Success 200 {object} utils.MessagesResponse "Messages retrieved"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Conversation not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /conversations/{id}/messages [get]
+func (h *Handler) handleGetMessages(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	limit := defaultMessageLimit
+	if s := c.Query("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 || n > maxMessageLimit {
+			utils.WriteError(c, http.StatusBadRequest, "limit must be between 1 and 200")
+			return
+		}
+		limit = n
+	}
+	var before int64
+	if s := c.Query("before"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n <= 0 {
+			utils.WriteError(c, http.StatusBadRequest, "before must be a message id")
+			return
+		}
+		before = n
+	}
+
+	conversation, ok := h.participantConversation(c, userID, true)
+	if !ok {
+		return
+	}
+
+	messages, err := h.store.GetMessages(conversation.ID, before, limit)
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if isParticipant(conversation, userID) && conversation.Unread > 0 {
+		if err := h.store.MarkRead(conversation.ID, userID); err != nil {
+			utils.WriteError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	utils.WriteJSON(c, http.StatusOK, gin.H{"messages": messages})
+}
+
+// @Summary Send Message
+// @Description Reply in a conversation. Only its client and the owner of the flat can write to it.
+// @Tags Messages
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Conversation ID"
+// @Param request body models.MessagePayload true "Message"
+// @Success 201 {object} models.Message "Message sent"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Conversation not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /conversations/{id}/messages [post]
+func (h *Handler) handleSendMessage(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	conversation, ok := h.participantConversation(c, userID, false)
+	if !ok {
+		return
+	}
+	body, ok := parseMessage(c)
+	if !ok {
+		return
+	}
+
+	message, err := h.store.AddMessage(models.Message{ConversationID: conversation.ID, SenderID: userID, Body: body})
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	recordMessage(c, message)
+	utils.WriteJSON(c, http.StatusCreated, message)
+
+	recipientID := conversation.OwnerID
+	if recipientID == userID {
+		recipientID = conversation.ClientID
+	}
+	h.notifyRecipient(conversation.ID, recipientID, message)
+}
+
+// @Summary Report Conversation
+// @Description Report abuse in a conversation. Reported conversations become readable for moderators. Only the first report of a conversation is kept.
+// @Tags Messages
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Conversation ID"
+// @Param request body models.ReportPayload true "Report"
+// @Success 200 {object} utils.SubscriptionResponse "Conversation reported"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Conversation not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /conversations/{id}/report [post]
+func (h *Handler) handleReportConversation(c *gin.Context) {
+	requestId, _ := c.Get("RequestId")
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	conversation, ok := h.participantConversation(c, userID, false)
+	if !ok {
+		return
+	}
+
+	var payload models.ReportPayload
+	if err := utils.ParseJSON(c, &payload); err != nil {
+		utils.WriteError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		errors := err.(validator.ValidationErrors)
+		utils.WriteError(c, http.StatusBadRequest, utils.FormatValidationError(errors))
+		return
+	}
+
+	payload.Reason = strings.TrimSpace(payload.Reason)
+	if err := h.store.ReportConversation(conversation.ID, payload.Reason); err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	middleware.RecordAudit(c.Request.Context(), models.AuditConversationReport, "conversation", conversation.ID, nil, payload)
+	utils.WriteJSON(c, http.StatusOK, gin.H{
+		"message":    "Conversation reported",
+		"request_id": requestId,
+		"code":       http.StatusOK,
+	})
+}
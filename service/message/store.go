@@ -0,0 +1,219 @@
+package message
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/utils"
+	"github.com/google/uuid"
+)
+
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// conversationColumns ends with the number of messages unread by the user
+// passed as $1.
+const conversationColumns = `c.id, c.client_id, c.owner_id, c.created_at, c.last_message_at, c.reported_at, COALESCE(c.report_reason, ''),
+	f.id, f.house_id, f.price, f.rooms, f.status,
+	(SELECT COUNT(*) FROM messages m WHERE m.conversation_id = c.id AND m.sender_id <> $1 AND m.read_at IS NULL)`
+
+func scanConversation(row utils.Scanner) (models.Conversation, error) {
+	var c models.Conversation
+	var reportedAt sql.NullTime
+	err := row.Scan(&c.ID, &c.ClientID, &c.OwnerID, &c.CreatedAt, &c.LastMessageAt, &reportedAt, &c.ReportReason,
+		&c.Flat.Id, &c.Flat.House_id, &c.Flat.Price, &c.Flat.Rooms, &c.Flat.Status, &c.Unread)
+	if err != nil {
+		return models.Conversation{}, err
+	}
+	if reportedAt.Valid {
+		c.ReportedAt = &reportedAt.Time
+	}
+	return c, nil
+}
+
+func (s *Store) queryConversations(query string, args ...any) ([]models.Conversation, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	conversations := []models.Conversation{}
+	for rows.Next() {
+		conversation, err := scanConversation(rows)
+		if err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		conversations = append(conversations, conversation)
+	}
+
+	return conversations, rows.Err()
+}
+
+// StartConversation returns the id of the conversation between the client
+// and the owner about the flat, creating it on first contact.
+func (s *Store) StartConversation(flatID int, clientID, ownerID uuid.UUID) (int, error) {
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+	query := `
+		INSERT INTO conversations (flat_id, client_id, owner_id, created_at, last_message_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (flat_id, client_id) DO UPDATE SET flat_id = EXCLUDED.flat_id
+		RETURNING id`
+
+	var id int
+	if err := s.db.QueryRow(query, flatID, clientID, ownerID, currentTime).Scan(&id); err != nil {
+		log.Printf("Error executing insert query: %v\n", err)
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetConversation returns the conversation with the number of messages unread
+// by userID.
+func (s *Store) GetConversation(id int, userID uuid.UUID) (models.Conversation, error) {
+	query := `
+		SELECT ` + conversationColumns + `
+		FROM conversations c
+		JOIN flat f ON f.id = c.flat_id
+		WHERE c.id = $2`
+	return scanConversation(s.db.QueryRow(query, userID, id))
+}
+
+// GetConversations returns the conversations the user takes part in, latest
+// activity first.
+func (s *Store) GetConversations(userID uuid.UUID) ([]models.Conversation, error) {
+	query := `
+		SELECT ` + conversationColumns + `
+		FROM conversations c
+		JOIN flat f ON f.id = c.flat_id
+		WHERE c.client_id = $1 OR c.owner_id = $1
+		ORDER BY c.last_message_at DESC, c.id DESC`
+	return s.queryConversations(query, userID)
+}
+
+// GetReportedConversations returns the conversations reported for abuse,
+// latest report first.
+func (s *Store) GetReportedConversations() ([]models.Conversation, error) {
+	query := `
+		SELECT ` + conversationColumns + `
+		FROM conversations c
+		JOIN flat f ON f.id = c.flat_id
+		WHERE c.reported_at IS NOT NULL
+		ORDER BY c.reported_at DESC, c.id DESC`
+	return s.queryConversations(query, uuid.Nil)
+}
+
+func (s *Store) AddMessage(message models.Message) (models.Message, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v\n", err)
+		return models.Message{}, err
+	}
+	defer tx.Rollback()
+
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+	queryInsert := `
+		INSERT INTO messages (conversation_id, sender_id, body, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, conversation_id, sender_id, body, created_at`
+
+	var inserted models.Message
+	err = tx.QueryRow(queryInsert, message.ConversationID, message.SenderID, message.Body, currentTime).
+		Scan(&inserted.ID, &inserted.ConversationID, &inserted.SenderID, &inserted.Body, &inserted.CreatedAt)
+	if err != nil {
+		log.Printf("Error executing insert query: %v\n", err)
+		return models.Message{}, err
+	}
+
+	queryUpdate := `
+		UPDATE conversations
+		SET last_message_at = $1
+		WHERE id = $2`
+	if _, err := tx.Exec(queryUpdate, currentTime, message.ConversationID); err != nil {
+		log.Printf("Error executing update query: %v\n", err)
+		return models.Message{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v\n", err)
+		return models.Message{}, err
+	}
+
+	return inserted, nil
+}
+
+// GetMessages returns up to limit messages of the conversation, newest first.
+// A positive beforeID returns the page of messages older than it.
+func (s *Store) GetMessages(conversationID int, beforeID int64, limit int) ([]models.Message, error) {
+	query := `
+		SELECT id, conversation_id, sender_id, body, created_at, read_at
+		FROM messages
+		WHERE conversation_id = $1 AND ($2 = 0 OR id < $2)
+		ORDER BY id DESC
+		LIMIT $3`
+
+	rows, err := s.db.Query(query, conversationID, beforeID, limit)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []models.Message{}
+	for rows.Next() {
+		var m models.Message
+		var readAt sql.NullTime
+		if err := rows.Scan(&m.ID, &m.ConversationID, &m.SenderID, &m.Body, &m.CreatedAt, &readAt); err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		if readAt.Valid {
+			m.ReadAt = &readAt.Time
+		}
+		messages = append(messages, m)
+	}
+
+	return messages, rows.Err()
+}
+
+// MarkRead marks the messages other participants sent to the reader as read.
+func (s *Store) MarkRead(conversationID int, readerID uuid.UUID) error {
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+	query := `
+		UPDATE messages
+		SET read_at = $1
+		WHERE conversation_id = $2 AND sender_id <> $3 AND read_at IS NULL`
+	_, err := s.db.Exec(query, currentTime, conversationID, readerID)
+	if err != nil {
+		log.Printf("Error executing update query: %v\n", err)
+	}
+	return err
+}
+
+// ReportConversation flags the conversation for moderators. Only the first
+// report is kept.
+func (s *Store) ReportConversation(id int, reason string) error {
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+	query := `
+		UPDATE conversations
+		SET reported_at = $1, report_reason = $2
+		WHERE id = $3 AND reported_at IS NULL`
+	_, err := s.db.Exec(query, currentTime, reason, id)
+	if err != nil {
+		log.Printf("Error executing update query: %v\n", err)
+	}
+	return err
+}
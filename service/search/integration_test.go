@@ -0,0 +1,119 @@
+package search
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/service/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var searchRows = []string{"id", "user_id", "name", "email", "locale", "rooms_min", "rooms_max", "price_min", "price_max",
+	"year_min", "year_max", "developer", "address", "created_at", "updated_at"}
+
+func TestHandleCreateSearch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	userID := uuid.New()
+	handler := NewHandler(NewStore(db), auth.NewStore(db))
+	r := gin.Default()
+	r.POST("/searches", func(c *gin.Context) {
+		c.Set("userID", userID)
+	}, handler.handleCreateSearch)
+
+	post := func(payload any) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, err := http.NewRequest("POST", "/searches", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+		return recorder
+	}
+	two, tenMillion := 2, 10000000
+
+	t.Run("should use the registered email of the user", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT user_id, email, password, user_type FROM users WHERE user_id = \$1`).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "email", "password", "user_type"}).
+				AddRow(userID, "user@example.com", "hash", "client"))
+		mock.ExpectQuery(`INSERT INTO saved_searches`).
+			WithArgs(userID, "Двушка до 10 млн", "user@example.com", models.LocaleRU, two, two, nil, tenMillion, nil, nil,
+				sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows(searchRows).
+				AddRow(1, userID, "Двушка до 10 млн", "user@example.com", models.LocaleRU, two, two, nil, tenMillion, nil, nil, "", "", time.Now(), time.Now()))
+
+		// An email in the payload is ignored.
+		recorder := post(map[string]any{"name": "Двушка до 10 млн", "email": "someone@example.com", "rooms_min": two, "rooms_max": two, "price_max": tenMillion})
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		var search models.SavedSearch
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &search))
+		assert.Equal(t, "user@example.com", search.Email)
+		assert.Equal(t, &tenMillion, search.PriceMax)
+		assert.Nil(t, search.PriceMin)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should require a registered user", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT user_id, email, password, user_type FROM users WHERE user_id = \$1`).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "email", "password", "user_type"}))
+
+		recorder := post(models.SavedSearchPayload{Name: "Двушка", RoomsMin: &two})
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject searches without criteria", func(t *testing.T) {
+		recorder := post(models.SavedSearchPayload{Name: "Всё подряд"})
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("should reject a minimum above the maximum", func(t *testing.T) {
+		three := 3
+		recorder := post(models.SavedSearchPayload{Name: "Комнаты", RoomsMin: &three, RoomsMax: &two})
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "rooms_min must be less or equal than rooms_max")
+	})
+}
+
+func TestGetMatchingSearches(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(db)
+
+	t.Run("should match by flat and house criteria", func(t *testing.T) {
+		mock.ExpectQuery(`FROM saved_searches s\s+JOIN house h ON h.id = \$1`).
+			WithArgs(1, 2, 9000000).
+			WillReturnRows(sqlmock.NewRows(searchRows).
+				AddRow(1, uuid.New(), "Двушка", "user@example.com", models.LocaleRU, 2, 2, nil, 10000000, nil, nil, "", "Москва", time.Now(), time.Now()))
+
+		searches, err := store.GetMatchingSearches(models.Flat{Id: 10, House_id: 1, Price: 9000000, Rooms: 2})
+
+		assert.NoError(t, err)
+		assert.Len(t, searches, 1)
+		assert.Equal(t, "Москва", searches[0].Address)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
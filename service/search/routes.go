@@ -0,0 +1,278 @@
+package search
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	store models.SavedSearchStore
+	users models.UserStore
+}
+
+func NewHandler(store models.SavedSearchStore, users models.UserStore) *Handler {
+	return &Handler{store: store, users: users}
+}
+
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+
+	allUsers := router.Group("/")
+	allUsers.Use(middleware.AuthMiddleware("moderator", "client"))
+	{
+		allUsers.POST("/searches", h.handleCreateSearch)
+		allUsers.GET("/searches", h.handleGetSearches)
+		allUsers.GET("/searches/:id", h.handleGetSearch)
+		allUsers.PUT("/searches/:id", h.handleUpdateSearch)
+		allUsers.DELETE("/searches/:id", h.handleDeleteSearch)
+	}
+}
+
+// searchID parses the :id path parameter, writing 400 when it is invalid.
+func searchID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		utils.WriteError(c, http.StatusBadRequest, "invalid saved search id")
+		return 0, false
+	}
+	return id, true
+}
+
+// parseSearch reads and validates the payload, writing 400 when it is invalid.
+// Matches always go to the email the user registered with, so a search
+// cannot make the server email someone else.
+func (h *Handler) parseSearch(c *gin.Context, userID uuid.UUID) (models.SavedSearch, bool) {
+	var payload models.SavedSearchPayload
+	if err := utils.ParseJSON(c, &payload); err != nil {
+		utils.WriteError(c, http.StatusBadRequest, err.Error())
+		return models.SavedSearch{}, false
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		errors := err.(validator.ValidationErrors)
+		utils.WriteError(c, http.StatusBadRequest, utils.FormatValidationError(errors))
+		return models.SavedSearch{}, false
+	}
+
+	search := models.SavedSearch{
+		UserID:    userID,
+		Name:      strings.TrimSpace(payload.Name),
+		Locale:    payload.Locale,
+		RoomsMin:  payload.RoomsMin,
+		RoomsMax:  payload.RoomsMax,
+		PriceMin:  payload.PriceMin,
+		PriceMax:  payload.PriceMax,
+		YearMin:   payload.YearMin,
+		YearMax:   payload.YearMax,
+		Developer: strings.TrimSpace(payload.Developer),
+		Address:   strings.TrimSpace(payload.Address),
+	}
+	if search.Name == "" {
+		utils.WriteError(c, http.StatusBadRequest, "name cannot be empty")
+		return models.SavedSearch{}, false
+	}
+	if search.RoomsMin == nil && search.RoomsMax == nil && search.PriceMin == nil && search.PriceMax == nil &&
+		search.YearMin == nil && search.YearMax == nil && search.Developer == "" && search.Address == "" {
+		utils.WriteError(c, http.StatusBadRequest, "at least one criterion is required")
+		return models.SavedSearch{}, false
+	}
+	for _, r := range []struct {
+		name     string
+		min, max *int
+	}{
+		{"rooms", search.RoomsMin, search.RoomsMax},
+		{"price", search.PriceMin, search.PriceMax},
+		{"year", search.YearMin, search.YearMax},
+	} {
+		if r.min != nil && r.max != nil && *r.min > *r.max {
+			utils.WriteError(c, http.StatusBadRequest, r.name+"_min must be less or equal than "+r.name+"_max")
+			return models.SavedSearch{}, false
+		}
+	}
+
+	if search.Locale == "" {
+		search.Locale = models.LocaleRU
+	}
+	user, err := h.users.GetUserById(userID)
+	if err != nil || user == nil {
+		utils.WriteError(c, http.StatusBadRequest, "saved searches are only available to registered users")
+		return models.SavedSearch{}, false
+	}
+	search.Email = user.Email
+
+	return search, true
+}
+
+// @Summary Create Saved Search
+// @Description Save search criteria. Every newly approved flat matching all set criteria is emailed to the user. Matches are sent to the email the user registered with, so users from /dummyLogin cannot save searches. Requires authorization for both moderator and client.
+// @Tags Searches
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.SavedSearchPayload true "Search criteria"
+// @Success 201 {object} models.SavedSearch "Saved search created"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /searches [post]
+func (h *Handler) handleCreateSearch(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	search, ok := h.parseSearch(c, userID)
+	if !ok {
+		return
+	}
+
+	created, err := h.store.CreateSavedSearch(search)
+	if err != nil {
+		utils.WriteStoreError(c, err, "saved search not found")
+		return
+	}
+
+	middleware.RecordAudit(c.Request.Context(), models.AuditSearchCreate, "search", created.ID, nil, created)
+	utils.WriteJSON(c, http.StatusCreated, created)
+}
+
+// @Summary Get Saved Searches
+// @Description List saved searches of the current user. Requires authorization for both moderator and client.
+// @Tags Searches
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} utils.SavedSearchesResponse "Saved searches retrieved"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /searches [get]
+func (h *Handler) handleGetSearches(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	searches, err := h.store.GetSavedSearches(userID)
+	if err != nil {
+		utils.WriteStoreError(c, err, "saved search not found")
+		return
+	}
+
+	utils.WriteJSON(c, http.StatusOK, gin.H{"searches": searches})
+}
+
+// @Summary Get Saved Search
+// @Description Get a saved search of the current user. Requires authorization for both moderator and client.
+// @Tags Searches
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Saved search ID"
+// @Success 200 {object} models.SavedSearch "Saved search retrieved"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Saved search not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /searches/{id} [get]
+func (h *Handler) handleGetSearch(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := searchID(c)
+	if !ok {
+		return
+	}
+
+	search, err := h.store.GetSavedSearch(id, userID)
+	if err != nil {
+		utils.WriteStoreError(c, err, "saved search not found")
+		return
+	}
+
+	utils.WriteJSON(c, http.StatusOK, search)
+}
+
+// @Summary Update Saved Search
+// @Description Replace the criteria of a saved search of the current user. Requires authorization for both moderator and client.
+// @Tags Searches
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Saved search ID"
+// @Param request body models.SavedSearchPayload true "Search criteria"
+// @Success 200 {object} models.SavedSearch "Saved search updated"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Saved search not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /searches/{id} [put]
+func (h *Handler) handleUpdateSearch(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := searchID(c)
+	if !ok {
+		return
+	}
+	search, ok := h.parseSearch(c, userID)
+	if !ok {
+		return
+	}
+	search.ID = id
+
+	before, err := h.store.GetSavedSearch(id, userID)
+	if err != nil {
+		utils.WriteStoreError(c, err, "saved search not found")
+		return
+	}
+
+	updated, err := h.store.UpdateSavedSearch(search)
+	if err != nil {
+		utils.WriteStoreError(c, err, "saved search not found")
+		return
+	}
+
+	middleware.RecordAudit(c.Request.Context(), models.AuditSearchUpdate, "search", id, before, updated)
+	utils.WriteJSON(c, http.StatusOK, updated)
+}
+
+// @Summary Delete Saved Search
+// @Description Delete a saved search of the current user. Requires authorization for both moderator and client.
+// @Tags Searches
+// @Security Bearer
+// @Param id path int true "Saved search ID"
+// @Success 204 "Saved search deleted"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Saved search not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /searches/{id} [delete]
+func (h *Handler) handleDeleteSearch(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := searchID(c)
+	if !ok {
+		return
+	}
+
+	before, err := h.store.GetSavedSearch(id, userID)
+	if err != nil {
+		utils.WriteStoreError(c, err, "saved search not found")
+		return
+	}
+	if err := h.store.DeleteSavedSearch(id, userID); err != nil {
+		utils.WriteStoreError(c, err, "saved search not found")
+		return
+	}
+
+	middleware.RecordAudit(c.Request.Context(), models.AuditSearchDelete, "search", id, before, nil)
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,154 @@
+package search
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/utils"
+	"github.com/google/uuid"
+)
+
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+const searchColumns = `id, user_id, name, email, locale, rooms_min, rooms_max, price_min, price_max,
+	year_min, year_max, COALESCE(developer, ''), COALESCE(address, ''), created_at, updated_at`
+
+func scanSearch(row utils.Scanner) (models.SavedSearch, error) {
+	var s models.SavedSearch
+	var roomsMin, roomsMax, priceMin, priceMax, yearMin, yearMax sql.NullInt64
+	err := row.Scan(&s.ID, &s.UserID, &s.Name, &s.Email, &s.Locale, &roomsMin, &roomsMax, &priceMin, &priceMax,
+		&yearMin, &yearMax, &s.Developer, &s.Address, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return models.SavedSearch{}, err
+	}
+	s.RoomsMin, s.RoomsMax = intPtr(roomsMin), intPtr(roomsMax)
+	s.PriceMin, s.PriceMax = intPtr(priceMin), intPtr(priceMax)
+	s.YearMin, s.YearMax = intPtr(yearMin), intPtr(yearMax)
+	return s, nil
+}
+
+func intPtr(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int64)
+	return &v
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func (s *Store) querySearches(query string, args ...any) ([]models.SavedSearch, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	searches := []models.SavedSearch{}
+	for rows.Next() {
+		search, err := scanSearch(rows)
+		if err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		searches = append(searches, search)
+	}
+
+	return searches, rows.Err()
+}
+
+func (s *Store) CreateSavedSearch(search models.SavedSearch) (models.SavedSearch, error) {
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+	query := `
+		INSERT INTO saved_searches (user_id, name, email, locale, rooms_min, rooms_max, price_min, price_max,
+			year_min, year_max, developer, address, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $13)
+		RETURNING ` + searchColumns
+
+	created, err := scanSearch(s.db.QueryRow(query, search.UserID, search.Name, search.Email, search.Locale,
+		search.RoomsMin, search.RoomsMax, search.PriceMin, search.PriceMax, search.YearMin, search.YearMax,
+		nullString(search.Developer), nullString(search.Address), currentTime))
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return models.SavedSearch{}, err
+	}
+
+	return created, nil
+}
+
+func (s *Store) GetSavedSearches(userID uuid.UUID) ([]models.SavedSearch, error) {
+	return s.querySearches(`SELECT `+searchColumns+` FROM saved_searches WHERE user_id = $1 ORDER BY id`, userID)
+}
+
+func (s *Store) GetSavedSearch(id int, userID uuid.UUID) (models.SavedSearch, error) {
+	query := `SELECT ` + searchColumns + ` FROM saved_searches WHERE id = $1 AND user_id = $2`
+
+	return scanSearch(s.db.QueryRow(query, id, userID))
+}
+
+func (s *Store) UpdateSavedSearch(search models.SavedSearch) (models.SavedSearch, error) {
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+	query := `
+		UPDATE saved_searches
+		SET name = $3, email = $4, locale = $5, rooms_min = $6, rooms_max = $7, price_min = $8, price_max = $9,
+			year_min = $10, year_max = $11, developer = $12, address = $13, updated_at = $14
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + searchColumns
+
+	return scanSearch(s.db.QueryRow(query, search.ID, search.UserID, search.Name, search.Email, search.Locale,
+		search.RoomsMin, search.RoomsMax, search.PriceMin, search.PriceMax, search.YearMin, search.YearMax,
+		nullString(search.Developer), nullString(search.Address), currentTime))
+}
+
+func (s *Store) DeleteSavedSearch(id int, userID uuid.UUID) error {
+	res, err := s.db.Exec(`DELETE FROM saved_searches WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetMatchingSearches returns the searches whose every set criterion matches
+// the flat and its house.
+func (s *Store) GetMatchingSearches(flat models.Flat) ([]models.SavedSearch, error) {
+	query := `
+		SELECT ` + searchColumns + `
+		FROM saved_searches
+		WHERE id IN (
+			SELECT s.id
+			FROM saved_searches s
+			JOIN house h ON h.id = $1
+			LEFT JOIN developers d ON d.id = h.developer_id
+			WHERE (s.rooms_min IS NULL OR s.rooms_min <= $2)
+				AND (s.rooms_max IS NULL OR s.rooms_max >= $2)
+				AND (s.price_min IS NULL OR s.price_min <= $3)
+				AND (s.price_max IS NULL OR s.price_max >= $3)
+				AND (s.year_min IS NULL OR s.year_min <= h.year)
+				AND (s.year_max IS NULL OR s.year_max >= h.year)
+				AND (s.developer IS NULL OR lower(s.developer) = lower(d.name))
+				AND (s.address IS NULL OR strpos(lower(h.address), lower(s.address)) > 0)
+		)
+		ORDER BY id`
+
+	return s.querySearches(query, flat.House_id, flat.Rooms, flat.Price)
+}
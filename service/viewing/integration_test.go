@@ -0,0 +1,319 @@
+package viewing
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/notification"
+	"github.com/delapaska/avito-rent/service/auth"
+	"github.com/delapaska/avito-rent/service/flat"
+	"github.com/delapaska/avito-rent/service/house"
+	"github.com/delapaska/avito-rent/service/search"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	flatRows    = []string{"id", "house_id", "price", "rooms", "status", "owner_id", "archived_at", "number", "floor", "total_area", "living_area", "balcony", "listing_type", "rent_period", "deposit", "commission", "utilities_included", "price_per_m2", "description", "high_risk", "rule_hits", "decline_reason", "expires_at", "final_price", "closed_at"}
+	viewingRows = []string{"id", "slot_id", "user_id", "email", "locale", "starts_at", "ends_at", "status", "created_at", "cancelled_at",
+		"flat_id", "house_id", "price", "rooms", "flat_status", "owner_id"}
+)
+
+// newHandler builds a handler whose notifications go to a separate mock
+// database, so the emails sent in the background do not consume expectations.
+func newHandler(t *testing.T) (*Handler, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	notifyDB, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	t.Cleanup(func() { notifyDB.Close() })
+
+	store := NewStore(db)
+	notifier := notification.NewNotifier(house.NewStore(notifyDB), search.NewStore(notifyDB), NewStore(notifyDB))
+	return NewHandler(store, flat.NewStore(db), auth.NewStore(db), notifier), mock
+}
+
+func newRouter(handler *Handler, userID uuid.UUID, userType string) *gin.Engine {
+	r := gin.Default()
+	setUser := func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("userType", userType)
+	}
+	r.POST("/flat/:id/slots", setUser, handler.handleCreateSlot)
+	r.DELETE("/flat/:id/slots/:slot_id", setUser, handler.handleDeleteSlot)
+	r.POST("/flat/:id/slots/:slot_id/book", setUser, handler.handleBookSlot)
+	r.POST("/viewings/:id/cancel", setUser, handler.handleCancelViewing)
+	return r
+}
+
+func serve(t *testing.T, r *gin.Engine, method, path string, payload any) *httptest.ResponseRecorder {
+	var body []byte
+	if payload != nil {
+		body, _ = json.Marshal(payload)
+	}
+	req, err := http.NewRequest(method, path, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestHandleCreateSlot(t *testing.T) {
+	handler, mock := newHandler(t)
+	ownerID := uuid.New()
+	startsAt := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Second)
+	endsAt := startsAt.Add(30 * time.Minute)
+
+	t.Run("should let the owner add a slot", func(t *testing.T) {
+		r := newRouter(handler, ownerID, "client")
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, .* FROM flat WHERE id = \$1`).
+			WithArgs(10).
+			WillReturnRows(flatRow(models.StatusCreated, ownerID))
+		mock.ExpectQuery(`INSERT INTO viewing_slots \(flat_id, starts_at, ends_at, created_at\)`).
+			WithArgs(10, startsAt, endsAt, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "flat_id", "starts_at", "ends_at", "created_at"}).
+				AddRow(1, 10, startsAt, endsAt, time.Now()))
+
+		recorder := serve(t, r, "POST", "/flat/10/slots", models.ViewingSlotPayload{StartsAt: startsAt, EndsAt: endsAt})
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should report overlapping slots as a conflict", func(t *testing.T) {
+		r := newRouter(handler, ownerID, "client")
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, .* FROM flat WHERE id = \$1`).
+			WithArgs(10).
+			WillReturnRows(flatRow(models.StatusApproved, ownerID))
+		mock.ExpectQuery(`INSERT INTO viewing_slots`).
+			WillReturnError(&pq.Error{Code: exclusionViolation})
+
+		recorder := serve(t, r, "POST", "/flat/10/slots", models.ViewingSlotPayload{StartsAt: startsAt, EndsAt: endsAt})
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should forbid other clients", func(t *testing.T) {
+		r := newRouter(handler, uuid.New(), "client")
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, .* FROM flat WHERE id = \$1`).
+			WithArgs(10).
+			WillReturnRows(flatRow(models.StatusApproved, ownerID))
+
+		recorder := serve(t, r, "POST", "/flat/10/slots", models.ViewingSlotPayload{StartsAt: startsAt, EndsAt: endsAt})
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject slots in the past or longer than 4 hours", func(t *testing.T) {
+		r := newRouter(handler, ownerID, "client")
+		for _, payload := range []models.ViewingSlotPayload{
+			{StartsAt: time.Now().Add(-time.Hour), EndsAt: time.Now()},
+			{StartsAt: startsAt, EndsAt: startsAt.Add(5 * time.Hour)},
+			{StartsAt: startsAt, EndsAt: startsAt},
+		} {
+			mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, .* FROM flat WHERE id = \$1`).
+				WithArgs(10).
+				WillReturnRows(flatRow(models.StatusApproved, ownerID))
+
+			recorder := serve(t, r, "POST", "/flat/10/slots", payload)
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleBookSlot(t *testing.T) {
+	handler, mock := newHandler(t)
+	ownerID, clientID := uuid.New(), uuid.New()
+	startsAt := time.Now().UTC().Add(48 * time.Hour)
+
+	t.Run("should book a slot of an approved flat", func(t *testing.T) {
+		r := newRouter(handler, clientID, "client")
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, .* FROM flat WHERE id = \$1`).
+			WithArgs(10).
+			WillReturnRows(flatRow(models.StatusApproved, ownerID))
+		mock.ExpectQuery(`INSERT INTO viewings`).
+			WithArgs(1, 10, clientID, "user@example.com", models.LocaleEN, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows(viewingRows).
+				AddRow(5, 1, clientID, "user@example.com", models.LocaleEN, startsAt, startsAt.Add(30*time.Minute), models.ViewingBooked, time.Now(), nil,
+					10, 1, 12500000, 3, models.StatusApproved, ownerID))
+
+		recorder := serve(t, r, "POST", "/flat/10/slots/1/book", models.BookViewingPayload{Email: "user@example.com", Locale: models.LocaleEN})
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		var viewing models.Viewing
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &viewing))
+		assert.Equal(t, 5, viewing.ID)
+		assert.Equal(t, models.ViewingBooked, viewing.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should report a booked slot as a conflict", func(t *testing.T) {
+		r := newRouter(handler, clientID, "client")
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, .* FROM flat WHERE id = \$1`).
+			WithArgs(10).
+			WillReturnRows(flatRow(models.StatusApproved, ownerID))
+		mock.ExpectQuery(`INSERT INTO viewings`).
+			WillReturnError(&pq.Error{Code: exclusionViolation})
+
+		recorder := serve(t, r, "POST", "/flat/10/slots/1/book", models.BookViewingPayload{Email: "user@example.com"})
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should hide flats that are not approved from clients", func(t *testing.T) {
+		r := newRouter(handler, clientID, "client")
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, .* FROM flat WHERE id = \$1`).
+			WithArgs(10).
+			WillReturnRows(flatRow(models.StatusOnModeration, ownerID))
+
+		recorder := serve(t, r, "POST", "/flat/10/slots/1/book", nil)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should hide archived flats from everyone", func(t *testing.T) {
+		r := newRouter(handler, clientID, "moderator")
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, .* FROM flat WHERE id = \$1`).
+			WithArgs(10).
+			WillReturnRows(sqlmock.NewRows(flatRows).
+				AddRow(10, 1, 12500000, 3, models.StatusApproved, ownerID, time.Now(), nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", nil, nil, nil))
+
+		recorder := serve(t, r, "POST", "/flat/10/slots/1/book", nil)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should not let owners book their own flats", func(t *testing.T) {
+		r := newRouter(handler, ownerID, "client")
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, .* FROM flat WHERE id = \$1`).
+			WithArgs(10).
+			WillReturnRows(flatRow(models.StatusApproved, ownerID))
+
+		recorder := serve(t, r, "POST", "/flat/10/slots/1/book", nil)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleCancelViewing(t *testing.T) {
+	handler, mock := newHandler(t)
+	ownerID, clientID := uuid.New(), uuid.New()
+	startsAt := time.Now().UTC().Add(48 * time.Hour)
+	row := func(status string) *sqlmock.Rows {
+		return sqlmock.NewRows(viewingRows).
+			AddRow(5, 1, clientID, "user@example.com", models.LocaleRU, startsAt, startsAt.Add(30*time.Minute), status, time.Now(), nil,
+				10, 1, 12500000, 3, models.StatusApproved, ownerID)
+	}
+
+	t.Run("should let the owner cancel a viewing", func(t *testing.T) {
+		r := newRouter(handler, ownerID, "client")
+		mock.ExpectQuery(`FROM viewings v JOIN flat f ON f.id = v.flat_id WHERE v.id = \$1`).
+			WithArgs(5).
+			WillReturnRows(row(models.ViewingBooked))
+		mock.ExpectQuery(`UPDATE viewings\s+SET status = 'cancelled'`).
+			WithArgs(5, sqlmock.AnyArg()).
+			WillReturnRows(row(models.ViewingCancelled))
+
+		recorder := serve(t, r, "POST", "/viewings/5/cancel", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should report an already cancelled viewing as a conflict", func(t *testing.T) {
+		r := newRouter(handler, clientID, "client")
+		mock.ExpectQuery(`FROM viewings v JOIN flat f ON f.id = v.flat_id WHERE v.id = \$1`).
+			WithArgs(5).
+			WillReturnRows(row(models.ViewingCancelled))
+		mock.ExpectQuery(`UPDATE viewings\s+SET status = 'cancelled'`).
+			WithArgs(5, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows(viewingRows))
+
+		recorder := serve(t, r, "POST", "/viewings/5/cancel", nil)
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should hide viewings of other users", func(t *testing.T) {
+		r := newRouter(handler, uuid.New(), "client")
+		mock.ExpectQuery(`FROM viewings v JOIN flat f ON f.id = v.flat_id WHERE v.id = \$1`).
+			WithArgs(5).
+			WillReturnRows(row(models.ViewingBooked))
+
+		recorder := serve(t, r, "POST", "/viewings/5/cancel", nil)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestHandleDeleteSlot(t *testing.T) {
+	handler, mock := newHandler(t)
+	ownerID := uuid.New()
+	r := newRouter(handler, ownerID, "client")
+
+	t.Run("should refuse to delete a booked slot", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, .* FROM flat WHERE id = \$1`).
+			WithArgs(10).
+			WillReturnRows(flatRow(models.StatusApproved, ownerID))
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM viewing_slots s\s+WHERE s.id = \$1 AND s.flat_id = \$2\s+FOR UPDATE`).
+			WithArgs(1, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"booked"}).AddRow(true))
+		mock.ExpectRollback()
+
+		recorder := serve(t, r, "DELETE", "/flat/10/slots/1", nil)
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should delete a free slot", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id, house_id, price, rooms, status, owner_id, archived_at, .* FROM flat WHERE id = \$1`).
+			WithArgs(10).
+			WillReturnRows(flatRow(models.StatusApproved, ownerID))
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM viewing_slots s\s+WHERE s.id = \$1 AND s.flat_id = \$2\s+FOR UPDATE`).
+			WithArgs(1, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"booked"}).AddRow(false))
+		mock.ExpectExec(`DELETE FROM viewing_slots WHERE id = \$1`).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		recorder := serve(t, r, "DELETE", "/flat/10/slots/1", nil)
+
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// flatRow is flat 10 of house 1 as the flat store selects it.
+func flatRow(status string, ownerID any) *sqlmock.Rows {
+	return sqlmock.NewRows(flatRows).
+		AddRow(10, 1, 12500000, 3, status, ownerID, nil, nil, nil, nil, nil, nil, "sale", "", nil, nil, nil, nil, "", false, nil, "", nil, nil, nil)
+}
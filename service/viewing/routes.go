@@ -0,0 +1,422 @@
+package viewing
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/notification"
+	"github.com/delapaska/avito-rent/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// maxSlotDuration limits how long a single viewing slot can be.
+const maxSlotDuration = 4 * time.Hour
+
+type Handler struct {
+	store    models.ViewingStore
+	flats    models.FlatStore
+	users    models.UserStore
+	notifier *notification.Notifier
+}
+
+func NewHandler(store models.ViewingStore, flats models.FlatStore, users models.UserStore, notifier *notification.Notifier) *Handler {
+	return &Handler{store: store, flats: flats, users: users, notifier: notifier}
+}
+
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+
+	allUsers := router.Group("/")
+	allUsers.Use(middleware.AuthMiddleware("moderator", "client"))
+	{
+		allUsers.POST("/flat/:id/slots", h.handleCreateSlot)
+		allUsers.GET("/flat/:id/slots", h.handleGetSlots)
+		allUsers.DELETE("/flat/:id/slots/:slot_id", h.handleDeleteSlot)
+		allUsers.POST("/flat/:id/slots/:slot_id/book", h.handleBookSlot)
+		allUsers.GET("/flat/:id/viewings", h.handleGetFlatViewings)
+		allUsers.GET("/viewings", h.handleGetViewings)
+		allUsers.POST("/viewings/:id/cancel", h.handleCancelViewing)
+	}
+}
+
+// pathID parses a positive integer path parameter, writing 400 when it is invalid.
+func pathID(c *gin.Context, param, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(param))
+	if err != nil || id <= 0 {
+		utils.WriteError(c, http.StatusBadRequest, "invalid "+name+" id")
+		return 0, false
+	}
+	return id, true
+}
+
+func isOwner(flat models.Flat, userID uuid.UUID) bool {
+	return flat.OwnerID != nil && *flat.OwnerID == userID
+}
+
+// visibleFlat loads the flat of the :id path parameter. Archived flats are
+// missing for everyone, and clients only see approved flats and their own
+// ones.
+func (h *Handler) visibleFlat(c *gin.Context, userID uuid.UUID) (models.Flat, bool) {
+	id, ok := pathID(c, "id", "flat")
+	if !ok {
+		return models.Flat{}, false
+	}
+
+	flat, err := h.flats.GetFlat(id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && flat.ArchivedAt != nil) ||
+		(err == nil && c.GetString("userType") != "moderator" && flat.Status != models.StatusApproved && !isOwner(flat, userID)) {
+		utils.WriteError(c, http.StatusNotFound, "flat not found")
+		return models.Flat{}, false
+	}
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return models.Flat{}, false
+	}
+	return flat, true
+}
+
+// ownFlat is visibleFlat that also writes 403 unless the user created the flat.
+func (h *Handler) ownFlat(c *gin.Context, userID uuid.UUID) (models.Flat, bool) {
+	flat, ok := h.visibleFlat(c, userID)
+	if !ok {
+		return models.Flat{}, false
+	}
+	if !isOwner(flat, userID) {
+		utils.WriteError(c, http.StatusForbidden, "only the owner of the flat can manage its viewings")
+		return models.Flat{}, false
+	}
+	return flat, true
+}
+
+// @Summary Create Viewing Slot
+// @Description Add a time when the flat can be shown. Slots are up to 4 hours long and cannot overlap other slots of the flat. Only the client who created the flat can add slots.
+// @Tags Viewings
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Flat ID"
+// @Param request body models.ViewingSlotPayload true "Slot time"
+// @Success 201 {object} models.ViewingSlot "Slot created"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Not the owner of the flat"
+// @Failure 404 {object} utils.ErrorResponse "Flat not found"
+// @Failure 409 {object} utils.ErrorResponse "Slot overlaps another slot"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /flat/{id}/slots [post]
+func (h *Handler) handleCreateSlot(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	flat, ok := h.ownFlat(c, userID)
+	if !ok {
+		return
+	}
+
+	var payload models.ViewingSlotPayload
+	if err := utils.ParseJSON(c, &payload); err != nil {
+		utils.WriteError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		errors := err.(validator.ValidationErrors)
+		utils.WriteError(c, http.StatusBadRequest, utils.FormatValidationError(errors))
+		return
+	}
+	if !payload.StartsAt.After(time.Now()) {
+		utils.WriteError(c, http.StatusBadRequest, "starts_at must be in the future")
+		return
+	}
+	if payload.EndsAt.Sub(payload.StartsAt) > maxSlotDuration {
+		utils.WriteError(c, http.StatusBadRequest, "slot cannot be longer than 4 hours")
+		return
+	}
+
+	slot, err := h.store.CreateSlot(models.ViewingSlot{FlatID: flat.Id, StartsAt: payload.StartsAt, EndsAt: payload.EndsAt})
+	if errors.Is(err, ErrConflict) {
+		utils.WriteError(c, http.StatusConflict, "slot overlaps another slot of the flat")
+		return
+	}
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	middleware.RecordAudit(c.Request.Context(), models.AuditSlotCreate, "viewing_slot", slot.ID, nil, slot)
+	utils.WriteJSON(c, http.StatusCreated, slot)
+}
+
+// @Summary Get Viewing Slots
+// @Description Upcoming viewing slots of the flat, in time order. Clients see the slots of approved flats and of their own ones. Requires authorization for both moderator and client.
+// @Tags Viewings
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Flat ID"
+// @Success 200 {object} utils.ViewingSlotsResponse "Slots retrieved"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Flat not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /flat/{id}/slots [get]
+func (h *Handler) handleGetSlots(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	flat, ok := h.visibleFlat(c, userID)
+	if !ok {
+		return
+	}
+
+	slots, err := h.store.GetSlots(flat.Id, time.Now().UTC())
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(c, http.StatusOK, gin.H{"slots": slots})
+}
+
+// @Summary Delete Viewing Slot
+// @Description Remove a viewing slot nobody has booked. Only the client who created the flat can remove slots.
+// @Tags Viewings
+// @Security Bearer
+// @Param id path int true "Flat ID"
+// @Param slot_id path int true "Slot ID"
+// @Success 204 "Slot deleted"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Not the owner of the flat"
+// @Failure 404 {object} utils.ErrorResponse "Flat or slot not found"
+// @Failure 409 {object} utils.ErrorResponse "Slot is booked"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /flat/{id}/slots/{slot_id} [delete]
+func (h *Handler) handleDeleteSlot(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	flat, ok := h.ownFlat(c, userID)
+	if !ok {
+		return
+	}
+	slotID, ok := pathID(c, "slot_id", "slot")
+	if !ok {
+		return
+	}
+
+	err := h.store.DeleteSlot(flat.Id, slotID)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.WriteError(c, http.StatusNotFound, "slot not found")
+		return
+	}
+	if errors.Is(err, ErrConflict) {
+		utils.WriteError(c, http.StatusConflict, "slot is booked, cancel the viewing first")
+		return
+	}
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	middleware.RecordAudit(c.Request.Context(), models.AuditSlotDelete, "viewing_slot", slotID, nil, nil)
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Book Viewing
+// @Description Book a viewing slot of the flat. A slot can be booked by one client at a time. The confirmation and a reminder a day before are emailed to the given address, which defaults to the one the user registered with. Requires authorization for both moderator and client.
+// @Tags Viewings
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Flat ID"
+// @Param slot_id path int true "Slot ID"
+// @Param request body models.BookViewingPayload false "Notification settings"
+// @Success 201 {object} models.Viewing "Viewing booked"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Flat or slot not found"
+// @Failure 409 {object} utils.ErrorResponse "Slot is already booked"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /flat/{id}/slots/{slot_id}/book [post]
+func (h *Handler) handleBookSlot(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	flat, ok := h.visibleFlat(c, userID)
+	if !ok {
+		return
+	}
+	slotID, ok := pathID(c, "slot_id", "slot")
+	if !ok {
+		return
+	}
+	if isOwner(flat, userID) {
+		utils.WriteError(c, http.StatusBadRequest, "owners cannot book viewings of their own flats")
+		return
+	}
+
+	var payload models.BookViewingPayload
+	if c.Request.ContentLength != 0 {
+		if err := utils.ParseJSON(c, &payload); err != nil {
+			utils.WriteError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		errors := err.(validator.ValidationErrors)
+		utils.WriteError(c, http.StatusBadRequest, utils.FormatValidationError(errors))
+		return
+	}
+
+	viewing := models.Viewing{
+		SlotID: slotID,
+		Flat:   flat,
+		UserID: userID,
+		Email:  payload.Email,
+		Locale: payload.Locale,
+	}
+	if viewing.Locale == "" {
+		viewing.Locale = models.LocaleRU
+	}
+	if viewing.Email == "" {
+		user, err := h.users.GetUserById(userID)
+		if err != nil || user == nil {
+			utils.WriteError(c, http.StatusBadRequest, "email is required for users without a registered email")
+			return
+		}
+		viewing.Email = user.Email
+	}
+
+	booked, err := h.store.BookSlot(viewing)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.WriteError(c, http.StatusNotFound, "slot not found or already started")
+		return
+	}
+	if errors.Is(err, ErrConflict) {
+		utils.WriteError(c, http.StatusConflict, "slot is already booked")
+		return
+	}
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	middleware.RecordAudit(c.Request.Context(), models.AuditViewingBook, "viewing", booked.ID, nil, booked)
+	utils.WriteJSON(c, http.StatusCreated, booked)
+
+	go h.notifier.NotifyViewingBooked(booked)
+}
+
+// @Summary Get Flat Viewings
+// @Description Upcoming booked viewings of the flat, in time order. Only the client who created the flat can list them.
+// @Tags Viewings
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Flat ID"
+// @Success 200 {object} utils.ViewingsResponse "Viewings retrieved"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrorResponse "Not the owner of the flat"
+// @Failure 404 {object} utils.ErrorResponse "Flat not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /flat/{id}/viewings [get]
+func (h *Handler) handleGetFlatViewings(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	flat, ok := h.ownFlat(c, userID)
+	if !ok {
+		return
+	}
+
+	viewings, err := h.store.GetFlatViewings(flat.Id, time.Now().UTC())
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(c, http.StatusOK, gin.H{"viewings": viewings})
+}
+
+// @Summary Get Viewings
+// @Description Viewings booked by the current user, latest first, including cancelled ones. Requires authorization for both moderator and client.
+// @Tags Viewings
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} utils.ViewingsResponse "Viewings retrieved"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /viewings [get]
+func (h *Handler) handleGetViewings(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	viewings, err := h.store.GetViewings(userID)
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(c, http.StatusOK, gin.H{"viewings": viewings})
+}
+
+// @Summary Cancel Viewing
+// @Description Cancel a booked viewing, freeing its slot for other clients. Both the client who booked it and the owner of the flat can cancel; the client is notified by email.
+// @Tags Viewings
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Viewing ID"
+// @Success 200 {object} models.Viewing "Viewing cancelled"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Viewing not found"
+// @Failure 409 {object} utils.ErrorResponse "Viewing is already cancelled"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /viewings/{id}/cancel [post]
+func (h *Handler) handleCancelViewing(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := pathID(c, "id", "viewing")
+	if !ok {
+		return
+	}
+
+	viewing, err := h.store.GetViewing(id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && viewing.UserID != userID && !isOwner(viewing.Flat, userID)) {
+		utils.WriteError(c, http.StatusNotFound, "viewing not found")
+		return
+	}
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	cancelled, err := h.store.CancelViewing(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.WriteError(c, http.StatusConflict, "viewing is already cancelled")
+		return
+	}
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	middleware.RecordAudit(c.Request.Context(), models.AuditViewingCancel, "viewing", id, viewing, cancelled)
+	utils.WriteJSON(c, http.StatusOK, cancelled)
+
+	go h.notifier.NotifyViewingCancelled(cancelled)
+}
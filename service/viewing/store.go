@@ -0,0 +1,258 @@
+package viewing
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/utils"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrConflict is returned when a slot or a booking overlaps an existing one
+// of the same flat, or when a booked slot is deleted.
+var ErrConflict = errors.New("the time overlaps another slot or viewing of the flat")
+
+// exclusionViolation is the Postgres error code raised by EXCLUDE constraints.
+const exclusionViolation = "23P01"
+
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+const viewingColumns = `v.id, v.slot_id, v.user_id, v.email, v.locale, v.starts_at, v.ends_at, v.status, v.created_at, v.cancelled_at,
+	f.id, f.house_id, f.price, f.rooms, f.status, f.owner_id`
+
+func scanViewing(row utils.Scanner) (models.Viewing, error) {
+	var v models.Viewing
+	var cancelledAt sql.NullTime
+	var ownerID uuid.NullUUID
+	err := row.Scan(&v.ID, &v.SlotID, &v.UserID, &v.Email, &v.Locale, &v.StartsAt, &v.EndsAt, &v.Status, &v.CreatedAt, &cancelledAt,
+		&v.Flat.Id, &v.Flat.House_id, &v.Flat.Price, &v.Flat.Rooms, &v.Flat.Status, &ownerID)
+	if err != nil {
+		return models.Viewing{}, err
+	}
+	if cancelledAt.Valid {
+		v.CancelledAt = &cancelledAt.Time
+	}
+	if ownerID.Valid {
+		v.Flat.OwnerID = &ownerID.UUID
+	}
+	return v, nil
+}
+
+func isConflict(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == exclusionViolation
+}
+
+func (s *Store) queryViewings(query string, args ...any) ([]models.Viewing, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	viewings := []models.Viewing{}
+	for rows.Next() {
+		viewing, err := scanViewing(rows)
+		if err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		viewings = append(viewings, viewing)
+	}
+
+	return viewings, rows.Err()
+}
+
+func (s *Store) CreateSlot(slot models.ViewingSlot) (models.ViewingSlot, error) {
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+	query := `
+		INSERT INTO viewing_slots (flat_id, starts_at, ends_at, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, flat_id, starts_at, ends_at, created_at`
+
+	var created models.ViewingSlot
+	err := s.db.QueryRow(query, slot.FlatID, slot.StartsAt, slot.EndsAt, currentTime).
+		Scan(&created.ID, &created.FlatID, &created.StartsAt, &created.EndsAt, &created.CreatedAt)
+	if isConflict(err) {
+		return models.ViewingSlot{}, ErrConflict
+	}
+	if err != nil {
+		log.Printf("Error executing insert query: %v\n", err)
+		return models.ViewingSlot{}, err
+	}
+	return created, nil
+}
+
+// GetSlots returns the slots of the flat starting after from, in time order.
+func (s *Store) GetSlots(flatID int, from time.Time) ([]models.ViewingSlot, error) {
+	query := `
+		SELECT s.id, s.flat_id, s.starts_at, s.ends_at, s.created_at,
+			EXISTS (SELECT 1 FROM viewings v WHERE v.slot_id = s.id AND v.status = 'booked')
+		FROM viewing_slots s
+		WHERE s.flat_id = $1 AND s.starts_at > $2
+		ORDER BY s.starts_at`
+
+	rows, err := s.db.Query(query, flatID, from)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	slots := []models.ViewingSlot{}
+	for rows.Next() {
+		var slot models.ViewingSlot
+		if err := rows.Scan(&slot.ID, &slot.FlatID, &slot.StartsAt, &slot.EndsAt, &slot.CreatedAt, &slot.Booked); err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		slots = append(slots, slot)
+	}
+
+	return slots, rows.Err()
+}
+
+// DeleteSlot removes a slot that nobody has booked. Booked slots return
+// ErrConflict; the viewing has to be cancelled first.
+func (s *Store) DeleteSlot(flatID, slotID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v\n", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var booked bool
+	query := `
+		SELECT EXISTS (SELECT 1 FROM viewings v WHERE v.slot_id = s.id AND v.status = 'booked')
+		FROM viewing_slots s
+		WHERE s.id = $1 AND s.flat_id = $2
+		FOR UPDATE`
+	if err := tx.QueryRow(query, slotID, flatID).Scan(&booked); err != nil {
+		return err
+	}
+	if booked {
+		return ErrConflict
+	}
+
+	if _, err := tx.Exec(`DELETE FROM viewing_slots WHERE id = $1`, slotID); err != nil {
+		log.Printf("Error executing delete query: %v\n", err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// BookSlot books the whole slot for the user. It returns sql.ErrNoRows when
+// the slot does not exist or has already started, and ErrConflict when
+// someone else has booked it.
+func (s *Store) BookSlot(viewing models.Viewing) (models.Viewing, error) {
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+	query := `
+		WITH booked AS (
+			INSERT INTO viewings (slot_id, flat_id, user_id, email, locale, starts_at, ends_at, status, created_at)
+			SELECT s.id, s.flat_id, $3, $4, $5, s.starts_at, s.ends_at, 'booked', $6
+			FROM viewing_slots s
+			WHERE s.id = $1 AND s.flat_id = $2 AND s.starts_at > $7
+			RETURNING *
+		)
+		SELECT ` + viewingColumns + `
+		FROM booked v
+		JOIN flat f ON f.id = v.flat_id`
+
+	booked, err := scanViewing(s.db.QueryRow(query, viewing.SlotID, viewing.Flat.Id, viewing.UserID, viewing.Email, viewing.Locale,
+		currentTime, time.Now().UTC()))
+	if isConflict(err) {
+		return models.Viewing{}, ErrConflict
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Error executing insert query: %v\n", err)
+	}
+	return booked, err
+}
+
+func (s *Store) GetViewing(id int) (models.Viewing, error) {
+	query := `SELECT ` + viewingColumns + ` FROM viewings v JOIN flat f ON f.id = v.flat_id WHERE v.id = $1`
+	return scanViewing(s.db.QueryRow(query, id))
+}
+
+// GetViewings returns the viewings booked by the user, latest first.
+func (s *Store) GetViewings(userID uuid.UUID) ([]models.Viewing, error) {
+	query := `
+		SELECT ` + viewingColumns + `
+		FROM viewings v
+		JOIN flat f ON f.id = v.flat_id
+		WHERE v.user_id = $1
+		ORDER BY v.starts_at DESC`
+	return s.queryViewings(query, userID)
+}
+
+// GetFlatViewings returns the booked viewings of the flat that end after
+// from, in time order.
+func (s *Store) GetFlatViewings(flatID int, from time.Time) ([]models.Viewing, error) {
+	query := `
+		SELECT ` + viewingColumns + `
+		FROM viewings v
+		JOIN flat f ON f.id = v.flat_id
+		WHERE v.flat_id = $1 AND v.status = 'booked' AND v.ends_at > $2
+		ORDER BY v.starts_at`
+	return s.queryViewings(query, flatID, from)
+}
+
+// CancelViewing cancels a booked viewing, freeing its slot. It returns
+// sql.ErrNoRows when the viewing does not exist or is already cancelled.
+func (s *Store) CancelViewing(id int) (models.Viewing, error) {
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+	query := `
+		WITH cancelled AS (
+			UPDATE viewings
+			SET status = 'cancelled', cancelled_at = $2
+			WHERE id = $1 AND status = 'booked'
+			RETURNING *
+		)
+		SELECT ` + viewingColumns + `
+		FROM cancelled v
+		JOIN flat f ON f.id = v.flat_id`
+
+	cancelled, err := scanViewing(s.db.QueryRow(query, id, currentTime))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Error executing update query: %v\n", err)
+	}
+	return cancelled, err
+}
+
+// ClaimViewingReminders marks the booked viewings starting between from and
+// to as reminded and returns them. Viewings booked less than to-from before
+// their start are skipped, the confirmation is enough for them, and so are
+// viewings of flats already rented or sold. A viewing is claimed once, so
+// running the reminders on every instance sends each of them once.
+func (s *Store) ClaimViewingReminders(from, to time.Time) ([]models.Viewing, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE viewings
+			SET reminder_sent_at = $1
+			WHERE status = 'booked' AND reminder_sent_at IS NULL AND starts_at > $1 AND starts_at <= $2
+				AND created_at <= starts_at - ($2::timestamptz - $1::timestamptz)
+				AND flat_id NOT IN (SELECT id FROM flat WHERE status IN ('rented', 'sold'))
+			RETURNING *
+		)
+		SELECT ` + viewingColumns + `
+		FROM claimed v
+		JOIN flat f ON f.id = v.flat_id
+		ORDER BY v.starts_at`
+	return s.queryViewings(query, from, to)
+}
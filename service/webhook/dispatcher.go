@@ -0,0 +1,187 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/delapaska/avito-rent/models"
+)
+
+const (
+	EventHeader     = "X-Avito-Event"
+	DeliveryHeader  = "X-Avito-Delivery"
+	TimestampHeader = "X-Avito-Timestamp"
+	SignatureHeader = "X-Avito-Signature"
+)
+
+const (
+	pollInterval   = time.Second
+	claimBatch     = 32
+	claimLease     = time.Minute
+	requestTimeout = 10 * time.Second
+	maxAttempts    = 8
+	baseBackoff    = 10 * time.Second
+	maxBackoff     = time.Hour
+	disableAfter   = 10
+)
+
+// Dispatcher sends pending webhook deliveries. Deliveries are leased in the
+// database, so every API instance can run its own dispatcher.
+type Dispatcher struct {
+	store  models.WebhookStore
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewDispatcher(store models.WebhookStore) *Dispatcher {
+	return &Dispatcher{
+		store: store,
+		client: &http.Client{
+			Timeout: requestTimeout,
+			// No proxy, so that dialControl sees the address of the endpoint.
+			Transport: &http.Transport{
+				DialContext:         (&net.Dialer{Timeout: requestTimeout, Control: dialControl}).DialContext,
+				TLSHandshakeTimeout: requestTimeout,
+			},
+			// A redirect counts as a failed delivery rather than a way around
+			// the https and address checks.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Keep draining while full batches come back.
+		for d.dispatch(ctx) == claimBatch {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatch sends one batch of due deliveries and returns its size.
+func (d *Dispatcher) dispatch(ctx context.Context) int {
+	deliveries, err := d.store.ClaimDueDeliveries(claimBatch, claimLease)
+	if err != nil {
+		log.Printf("Error claiming webhook deliveries: %v\n", err)
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery models.WebhookDelivery) {
+			defer wg.Done()
+			d.deliver(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+
+	return len(deliveries)
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery models.WebhookDelivery) {
+	attempt := models.WebhookDeliveryAttempt{
+		DeliveryID: delivery.ID,
+		WebhookID:  delivery.WebhookID,
+		EventID:    delivery.Event.ID,
+		EventType:  delivery.Event.Type,
+		Attempt:    delivery.Attempts + 1,
+		CreatedAt:  d.now(),
+	}
+
+	statusCode, err := d.send(ctx, delivery, attempt.CreatedAt)
+	attempt.DurationMs = int(d.now().Sub(attempt.CreatedAt).Milliseconds())
+	if statusCode != 0 {
+		attempt.StatusCode = &statusCode
+	}
+
+	var retryAt *time.Time
+	if err != nil {
+		attempt.Error = err.Error()
+		if attempt.Attempt < maxAttempts {
+			next := attempt.CreatedAt.Add(backoff(attempt.Attempt))
+			retryAt = &next
+		}
+	}
+
+	if err := d.store.RecordDeliveryAttempt(attempt, retryAt, disableAfter); err != nil {
+		log.Printf("Error recording webhook delivery %d: %v\n", delivery.ID, err)
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery models.WebhookDelivery, now time.Time) (int, error) {
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	if req.URL.Scheme != "https" {
+		return 0, models.ErrWebhookNotHTTPS
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "avito-rent-webhooks/1.0")
+	req.Header.Set(EventHeader, delivery.Event.Type)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the X-Avito-Signature value: the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the webhook secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff doubles the delay after every failed attempt, up to maxBackoff.
+func backoff(attempt int) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
@@ -0,0 +1,240 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleCreateWebhook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	userID := uuid.New()
+	handler := NewHandler(NewStore(db))
+	handler.lookupIP = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		switch host {
+		case "partner.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+		case "internal.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}, {IP: net.ParseIP("10.0.0.5")}}, nil
+		}
+		return net.DefaultResolver.LookupIPAddr(ctx, host)
+	}
+	r := gin.Default()
+	r.POST("/webhooks", func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("userType", "client")
+	}, handler.handleCreateWebhook)
+
+	t.Run("should generate a secret and return it once", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO webhooks`).
+			WithArgs(userID, "client", "https://partner.example.com/hook", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "user_type", "url", "event_types", "house_ids", "active", "failure_count", "disabled_at", "created_at"}).
+				AddRow(1, userID, "client", "https://partner.example.com/hook", "{flat_approved}", "{}", true, 0, nil, time.Now()))
+
+		body, _ := json.Marshal(models.WebhookPayload{
+			URL:        "https://partner.example.com/hook",
+			EventTypes: []string{models.EventFlatApproved},
+		})
+		req, err := http.NewRequest("POST", "/webhooks", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		var webhook models.Webhook
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &webhook))
+		assert.Len(t, webhook.Secret, 64)
+		assert.Equal(t, []string{models.EventFlatApproved}, webhook.EventTypes)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject urls that are not https or point at internal addresses", func(t *testing.T) {
+		for _, url := range []string{
+			"http://partner.example.com/hook",
+			"https://127.0.0.1/hook",
+			"https://169.254.169.254/latest/meta-data",
+			"https://10.0.0.1/hook",
+			"https://[::1]/hook",
+			"https://internal.example.com/hook",
+		} {
+			body, _ := json.Marshal(models.WebhookPayload{
+				URL:        url,
+				EventTypes: []string{models.EventFlatApproved},
+			})
+			req, err := http.NewRequest("POST", "/webhooks", bytes.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, url)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should reject unknown event types", func(t *testing.T) {
+		body, _ := json.Marshal(models.WebhookPayload{
+			URL:        "https://partner.example.com/hook",
+			EventTypes: []string{"flat_deleted"},
+		})
+		req, err := http.NewRequest("POST", "/webhooks", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("should report failed inserts as server errors", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO webhooks`).WillReturnError(sql.ErrNoRows)
+
+		body, _ := json.Marshal(models.WebhookPayload{
+			URL:        "https://partner.example.com/hook",
+			EventTypes: []string{models.EventFlatApproved},
+		})
+		req, err := http.NewRequest("POST", "/webhooks", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "failed to create webhook")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDispatcherDeliver(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open mock sql database: %v", err)
+	}
+	defer db.Close()
+
+	dispatcher := NewDispatcher(NewStore(db))
+	delivery := models.WebhookDelivery{
+		ID:        5,
+		WebhookID: 1,
+		Secret:    "partner-signing-secret",
+		Event: models.HouseEvent{
+			ID:      42,
+			HouseID: 1,
+			Type:    models.EventFlatApproved,
+			Flat:    models.Flat{Id: 10, House_id: 1, Price: 100000, Rooms: 3, Status: models.StatusApproved},
+		},
+	}
+
+	t.Run("should sign the payload and mark the delivery succeeded", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, models.EventFlatApproved, r.Header.Get(EventHeader))
+			assert.Equal(t, "5", r.Header.Get(DeliveryHeader))
+			assert.Equal(t, Sign("partner-signing-secret", r.Header.Get(TimestampHeader), body), r.Header.Get(SignatureHeader))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+		// The test server listens on loopback, which the default client refuses.
+		dispatcher.client = server.Client()
+		delivery.URL = server.URL
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO webhook_delivery_attempts`).
+			WithArgs(int64(5), 1, 1, http.StatusNoContent, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`UPDATE webhook_deliveries SET status = \$1, attempts = \$2 WHERE id = \$3`).
+			WithArgs(models.DeliverySucceeded, 1, int64(5)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE webhooks SET failure_count = 0 WHERE id = \$1`).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		dispatcher.deliver(context.Background(), delivery)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should schedule a retry with backoff and disable the webhook after repeated failures", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+		dispatcher.client = server.Client()
+		delivery.URL = server.URL
+		delivery.Attempts = 2
+
+		now := time.Date(2024, 8, 11, 9, 0, 0, 0, time.UTC)
+		dispatcher.now = func() time.Time { return now }
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO webhook_delivery_attempts`).
+			WithArgs(int64(5), 1, 3, http.StatusInternalServerError, "unexpected status 500", 0, now).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectExec(`UPDATE webhook_deliveries SET status = \$1, attempts = \$2, next_attempt_at = \$3 WHERE id = \$4`).
+			WithArgs(models.DeliveryPending, 3, now.Add(40*time.Second), int64(5)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`UPDATE webhooks SET failure_count = failure_count \+ 1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"failure_count"}).AddRow(disableAfter))
+		mock.ExpectExec(`UPDATE webhooks SET active = FALSE, disabled_at = \$1 WHERE id = \$2 AND active`).
+			WithArgs(now, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE webhook_deliveries SET status = \$1 WHERE webhook_id = \$2 AND status = \$3`).
+			WithArgs(models.DeliveryFailed, 1, models.DeliveryPending).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		dispatcher.deliver(context.Background(), delivery)
+
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 10*time.Second, backoff(1))
+	assert.Equal(t, 80*time.Second, backoff(4))
+	assert.Equal(t, time.Hour, backoff(20))
+}
+
+func TestDispatcherRefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the request reached a loopback address")
+	}))
+	defer server.Close()
+
+	dispatcher := NewDispatcher(nil)
+
+	_, err := dispatcher.send(context.Background(), models.WebhookDelivery{ID: 5, URL: server.URL}, time.Now())
+	assert.ErrorIs(t, err, models.ErrWebhookAddress)
+
+	_, err = dispatcher.send(context.Background(), models.WebhookDelivery{ID: 5, URL: "http://partner.example.com/hook"}, time.Now())
+	assert.ErrorIs(t, err, models.ErrWebhookNotHTTPS)
+}
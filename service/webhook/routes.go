@@ -0,0 +1,269 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/delapaska/avito-rent/middleware"
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 200
+)
+
+type Handler struct {
+	store    models.WebhookStore
+	lookupIP lookupFunc
+}
+
+func NewHandler(store models.WebhookStore) *Handler {
+	return &Handler{store: store, lookupIP: net.DefaultResolver.LookupIPAddr}
+}
+
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+
+	allUsers := router.Group("/")
+	allUsers.Use(middleware.AuthMiddleware("moderator", "client"))
+	{
+		allUsers.POST("/webhooks", h.handleCreateWebhook)
+		allUsers.GET("/webhooks", h.handleGetWebhooks)
+		allUsers.DELETE("/webhooks/:id", h.handleDeleteWebhook)
+		allUsers.POST("/webhooks/:id/enable", h.handleEnableWebhook)
+		allUsers.GET("/webhooks/:id/deliveries", h.handleGetDeliveries)
+	}
+}
+
+// webhookID parses the :id path parameter, writing 400 when it is invalid.
+func webhookID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		utils.WriteError(c, http.StatusBadRequest, "invalid webhook id")
+		return 0, false
+	}
+	return id, true
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// @Summary Create Webhook
+// @Description Register an endpoint that receives flat events as signed JSON POST requests. Every request carries the X-Avito-Event, X-Avito-Delivery, X-Avito-Timestamp and X-Avito-Signature headers; the signature is "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret. Failed deliveries are retried with exponential backoff, and the webhook is disabled after repeated failures. The URL has to use https and its host has to resolve to public addresses; loopback, private, link-local and other special-purpose addresses, including carrier-grade NAT and IPv4-mapped or NAT64 forms of them, are rejected here and again when delivering. Redirects are not followed. The secret is generated when omitted and is only returned in this response. Clients only receive events about approved flats. Requires authorization for both moderator and client.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body models.WebhookPayload true "Webhook details"
+// @Success 201 {object} models.Webhook "Webhook created"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /webhooks [post]
+func (h *Handler) handleCreateWebhook(c *gin.Context) {
+	var payload models.WebhookPayload
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := utils.ParseJSON(c, &payload); err != nil {
+		utils.WriteError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		errors := err.(validator.ValidationErrors)
+		utils.WriteError(c, http.StatusBadRequest, utils.FormatValidationError(errors))
+		return
+	}
+
+	if err := checkURL(c.Request.Context(), h.lookupIP, payload.URL); err != nil {
+		utils.WriteError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if payload.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			utils.WriteError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		payload.Secret = secret
+	}
+	if payload.HouseIDs == nil {
+		payload.HouseIDs = []int{}
+	}
+
+	webhook, err := h.store.CreateWebhook(models.Webhook{
+		UserID:     userID,
+		UserType:   c.GetString("userType"),
+		URL:        payload.URL,
+		Secret:     payload.Secret,
+		EventTypes: payload.EventTypes,
+		HouseIDs:   payload.HouseIDs,
+	})
+	if err != nil {
+		utils.WriteError(c, http.StatusInternalServerError, "failed to create webhook")
+		return
+	}
+
+	recorded := webhook
+	recorded.Secret = ""
+	middleware.RecordAudit(c.Request.Context(), models.AuditWebhookCreate, "webhook", webhook.ID, nil, recorded)
+	utils.WriteJSON(c, http.StatusCreated, webhook)
+}
+
+// @Summary Get Webhooks
+// @Description List webhooks registered by the current user. Requires authorization for both moderator and client.
+// @Tags Webhooks
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} utils.WebhooksResponse "Webhooks retrieved"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /webhooks [get]
+func (h *Handler) handleGetWebhooks(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+
+	webhooks, err := h.store.GetWebhooks(userID)
+	if err != nil {
+		utils.WriteStoreError(c, err, "webhook not found")
+		return
+	}
+
+	utils.WriteJSON(c, http.StatusOK, gin.H{"webhooks": webhooks})
+}
+
+// @Summary Delete Webhook
+// @Description Delete a webhook of the current user together with its delivery log. Requires authorization for both moderator and client.
+// @Tags Webhooks
+// @Security Bearer
+// @Param id path int true "Webhook ID"
+// @Success 204 "Webhook deleted"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Webhook not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /webhooks/{id} [delete]
+func (h *Handler) handleDeleteWebhook(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	before, err := h.store.GetWebhook(id, userID)
+	if err != nil {
+		utils.WriteStoreError(c, err, "webhook not found")
+		return
+	}
+	if err := h.store.DeleteWebhook(id, userID); err != nil {
+		utils.WriteStoreError(c, err, "webhook not found")
+		return
+	}
+
+	middleware.RecordAudit(c.Request.Context(), models.AuditWebhookDelete, "webhook", id, before, nil)
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Enable Webhook
+// @Description Re-enable a webhook that was disabled after repeated delivery failures and reset its failure counter. Events that happen while the webhook is disabled are not delivered. Requires authorization for both moderator and client.
+// @Tags Webhooks
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} models.Webhook "Webhook enabled"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Webhook not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /webhooks/{id}/enable [post]
+func (h *Handler) handleEnableWebhook(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	before, err := h.store.GetWebhook(id, userID)
+	if err != nil {
+		utils.WriteStoreError(c, err, "webhook not found")
+		return
+	}
+	webhook, err := h.store.EnableWebhook(id, userID)
+	if err != nil {
+		utils.WriteStoreError(c, err, "webhook not found")
+		return
+	}
+
+	middleware.RecordAudit(c.Request.Context(), models.AuditWebhookEnable, "webhook", id, before, webhook)
+	utils.WriteJSON(c, http.StatusOK, webhook)
+}
+
+// @Summary Get Webhook Deliveries
+// @Description Delivery attempts of a webhook, newest first. Requires authorization for both moderator and client.
+// @Tags Webhooks
+// @Produce json
+// @Security Bearer
+// @Param id path int true "Webhook ID"
+// @Param limit query int false "Maximum number of attempts, 50 by default and at most 200"
+// @Success 200 {object} utils.WebhookDeliveriesResponse "Delivery attempts retrieved"
+// @Failure 400 {object} utils.ErrorResponse "Bad request"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrorResponse "Webhook not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /webhooks/{id}/deliveries [get]
+func (h *Handler) handleGetDeliveries(c *gin.Context) {
+	userID, ok := utils.CurrentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	limit := defaultDeliveryLimit
+	if s := c.Query("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 || n > maxDeliveryLimit {
+			utils.WriteError(c, http.StatusBadRequest, "limit must be between 1 and 200")
+			return
+		}
+		limit = n
+	}
+
+	if _, err := h.store.GetWebhook(id, userID); err != nil {
+		utils.WriteStoreError(c, err, "webhook not found")
+		return
+	}
+
+	attempts, err := h.store.GetDeliveryAttempts(id, limit)
+	if err != nil {
+		utils.WriteStoreError(c, err, "webhook not found")
+		return
+	}
+
+	utils.WriteJSON(c, http.StatusOK, gin.H{"attempts": attempts})
+}
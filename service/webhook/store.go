@@ -0,0 +1,273 @@
+package webhook
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/delapaska/avito-rent/models"
+	"github.com/delapaska/avito-rent/utils"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+const webhookColumns = `id, user_id, user_type, url, event_types, house_ids, active, failure_count, disabled_at, created_at`
+
+func scanWebhook(row utils.Scanner) (models.Webhook, error) {
+	var w models.Webhook
+	var houseIDs []int64
+	var disabledAt sql.NullTime
+	err := row.Scan(&w.ID, &w.UserID, &w.UserType, &w.URL, pq.Array(&w.EventTypes), pq.Array(&houseIDs),
+		&w.Active, &w.FailureCount, &disabledAt, &w.CreatedAt)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	w.HouseIDs = make([]int, len(houseIDs))
+	for i, id := range houseIDs {
+		w.HouseIDs[i] = int(id)
+	}
+	if disabledAt.Valid {
+		w.DisabledAt = &disabledAt.Time
+	}
+	return w, nil
+}
+
+func (s *Store) CreateWebhook(webhook models.Webhook) (models.Webhook, error) {
+	currentTime := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+
+	query := `
+		INSERT INTO webhooks (user_id, user_type, url, secret, event_types, house_ids, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + webhookColumns
+
+	created, err := scanWebhook(s.db.QueryRow(query, webhook.UserID, webhook.UserType, webhook.URL, webhook.Secret,
+		pq.Array(webhook.EventTypes), pq.Array(webhook.HouseIDs), currentTime))
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return models.Webhook{}, err
+	}
+	created.Secret = webhook.Secret
+
+	return created, nil
+}
+
+func (s *Store) GetWebhooks(userID uuid.UUID) ([]models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id = $1 ORDER BY id`
+
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (s *Store) GetWebhook(id int, userID uuid.UUID) (models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1 AND user_id = $2`
+
+	return scanWebhook(s.db.QueryRow(query, id, userID))
+}
+
+func (s *Store) DeleteWebhook(id int, userID uuid.UUID) error {
+	res, err := s.db.Exec(`DELETE FROM webhooks WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *Store) EnableWebhook(id int, userID uuid.UUID) (models.Webhook, error) {
+	query := `
+		UPDATE webhooks
+		SET active = TRUE, failure_count = 0, disabled_at = NULL
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + webhookColumns
+
+	return scanWebhook(s.db.QueryRow(query, id, userID))
+}
+
+func (s *Store) GetDeliveryAttempts(webhookID int, limit int) ([]models.WebhookDeliveryAttempt, error) {
+	query := `
+		SELECT a.id, a.delivery_id, a.webhook_id, d.event_id, e.type, a.attempt, a.status_code, a.error, a.duration_ms, a.created_at
+		FROM webhook_delivery_attempts a
+		JOIN webhook_deliveries d ON d.id = a.delivery_id
+		JOIN house_events e ON e.id = d.event_id
+		WHERE a.webhook_id = $1
+		ORDER BY a.id DESC
+		LIMIT $2`
+
+	rows, err := s.db.Query(query, webhookID, limit)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := []models.WebhookDeliveryAttempt{}
+	for rows.Next() {
+		var a models.WebhookDeliveryAttempt
+		var statusCode sql.NullInt64
+		var errText sql.NullString
+		if err := rows.Scan(&a.ID, &a.DeliveryID, &a.WebhookID, &a.EventID, &a.EventType, &a.Attempt,
+			&statusCode, &errText, &a.DurationMs, &a.CreatedAt); err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		if statusCode.Valid {
+			code := int(statusCode.Int64)
+			a.StatusCode = &code
+		}
+		a.Error = errText.String
+		attempts = append(attempts, a)
+	}
+
+	return attempts, rows.Err()
+}
+
+// ClaimDueDeliveries leases up to limit pending deliveries by pushing their
+// next attempt forward, so other instances skip them while they are sent.
+func (s *Store) ClaimDueDeliveries(limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	now := time.Now().UTC()
+
+	query := `
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = $2
+		FROM webhooks w, house_events e
+		WHERE d.id IN (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		AND w.id = d.webhook_id AND e.id = d.event_id
+		RETURNING d.id, d.webhook_id, w.url, w.secret, d.attempts,
+			e.id, e.house_id, e.type, e.flat, e.old_price, e.created_at`
+
+	rows, err := s.db.Query(query, now, now.Add(lease), limit)
+	if err != nil {
+		log.Printf("Error executing query: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var d models.WebhookDelivery
+		var flat []byte
+		var oldPrice sql.NullInt64
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.URL, &d.Secret, &d.Attempts,
+			&d.Event.ID, &d.Event.HouseID, &d.Event.Type, &flat, &oldPrice, &d.Event.CreatedAt); err != nil {
+			log.Printf("Error scanning row: %v\n", err)
+			return nil, err
+		}
+		if err := json.Unmarshal(flat, &d.Event.Flat); err != nil {
+			return nil, err
+		}
+		if oldPrice.Valid {
+			price := int(oldPrice.Int64)
+			d.Event.OldPrice = &price
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// RecordDeliveryAttempt logs the attempt and updates the delivery: it succeeds
+// when attempt.Error is empty, is retried at retryAt, or fails for good when
+// retryAt is nil. The webhook is disabled after disableAfter failures in a row.
+func (s *Store) RecordDeliveryAttempt(attempt models.WebhookDeliveryAttempt, retryAt *time.Time, disableAfter int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v\n", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var errText *string
+	if attempt.Error != "" {
+		errText = &attempt.Error
+	}
+	_, err = tx.Exec(`
+		INSERT INTO webhook_delivery_attempts (delivery_id, webhook_id, attempt, status_code, error, duration_ms, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		attempt.DeliveryID, attempt.WebhookID, attempt.Attempt, attempt.StatusCode, errText, attempt.DurationMs, attempt.CreatedAt)
+	if err != nil {
+		log.Printf("Error executing insert query: %v\n", err)
+		return err
+	}
+
+	if attempt.Error == "" {
+		if _, err := tx.Exec(`UPDATE webhook_deliveries SET status = $1, attempts = $2 WHERE id = $3`,
+			models.DeliverySucceeded, attempt.Attempt, attempt.DeliveryID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE webhooks SET failure_count = 0 WHERE id = $1`, attempt.WebhookID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	status := models.DeliveryFailed
+	nextAttempt := attempt.CreatedAt
+	if retryAt != nil {
+		status = models.DeliveryPending
+		nextAttempt = *retryAt
+	}
+	if _, err := tx.Exec(`UPDATE webhook_deliveries SET status = $1, attempts = $2, next_attempt_at = $3 WHERE id = $4`,
+		status, attempt.Attempt, nextAttempt, attempt.DeliveryID); err != nil {
+		return err
+	}
+
+	var failures int
+	if err := tx.QueryRow(`UPDATE webhooks SET failure_count = failure_count + 1 WHERE id = $1 RETURNING failure_count`,
+		attempt.WebhookID).Scan(&failures); err != nil {
+		return err
+	}
+	if failures >= disableAfter {
+		if _, err := tx.Exec(`UPDATE webhooks SET active = FALSE, disabled_at = $1 WHERE id = $2 AND active`,
+			attempt.CreatedAt, attempt.WebhookID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE webhook_deliveries SET status = $1 WHERE webhook_id = $2 AND status = $3`,
+			models.DeliveryFailed, attempt.WebhookID, models.DeliveryPending); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v\n", err)
+		return err
+	}
+	return nil
+}
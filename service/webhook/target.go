@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"syscall"
+
+	"github.com/delapaska/avito-rent/models"
+)
+
+// lookupFunc resolves a host name, net.DefaultResolver.LookupIPAddr in production.
+type lookupFunc func(ctx context.Context, host string) ([]net.IPAddr, error)
+
+// checkURL makes sure a webhook URL uses https and that every address of its
+// host is public, so webhooks cannot be pointed at the internal network.
+func checkURL(ctx context.Context, lookup lookupFunc, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return models.ErrWebhookNotHTTPS
+	}
+
+	addrs, err := lookup(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return models.ErrWebhookAddress
+	}
+	for _, addr := range addrs {
+		if !publicIP(addr.IP) {
+			return models.ErrWebhookAddress
+		}
+	}
+	return nil
+}
+
+// deniedNets are special-purpose ranges that are not reachable on the public
+// internet but that the net.IP predicates do not cover.
+var deniedNets = parseCIDRs(
+	"0.0.0.0/8",       // this network
+	"100.64.0.0/10",   // carrier-grade NAT
+	"192.0.0.0/24",    // IETF protocol assignments
+	"192.0.2.0/24",    // documentation
+	"198.18.0.0/15",   // benchmarking
+	"198.51.100.0/24", // documentation
+	"203.0.113.0/24",  // documentation
+	"240.0.0.0/4",     // reserved and broadcast
+	"64:ff9b:1::/48",  // local-use NAT64
+	"100::/64",        // discard-only
+	"2001:db8::/32",   // documentation
+)
+
+// nat64Net embeds an IPv4 address in its last 32 bits, which is checked
+// instead.
+var nat64Net = parseCIDRs("64:ff9b::/96")[0]
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets[i] = n
+	}
+	return nets
+}
+
+func publicIP(ip net.IP) bool {
+	if v4 := ip.To4(); v4 != nil {
+		// Also unwraps IPv4-mapped addresses such as ::ffff:10.0.0.1.
+		ip = v4
+	} else if len(ip) == net.IPv6len && nat64Net.Contains(ip) {
+		return publicIP(ip[12:])
+	}
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, n := range deniedNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// dialControl rejects connections to non-public addresses. The host is checked
+// again when dialing because its DNS records may have changed since the
+// webhook was registered.
+func dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+		return fmt.Errorf("%w: %s", models.ErrWebhookAddress, host)
+	}
+	return nil
+}
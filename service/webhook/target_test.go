@@ -0,0 +1,43 @@
+package webhook
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicIP(t *testing.T) {
+	for ip, public := range map[string]bool{
+		"93.184.216.34":        true,
+		"2606:2800:220:1::":    true,
+		"64:ff9b::5db8:d822":   true, // NAT64 form of 93.184.216.34
+		"127.0.0.1":            false,
+		"10.0.0.5":             false,
+		"172.16.0.1":           false,
+		"192.168.1.1":          false,
+		"169.254.169.254":      false,
+		"0.0.0.0":              false,
+		"0.1.2.3":              false,
+		"100.64.0.1":           false,
+		"100.127.255.254":      false,
+		"192.0.0.8":            false,
+		"198.18.0.1":           false,
+		"198.19.255.255":       false,
+		"203.0.113.7":          false,
+		"255.255.255.255":      false,
+		"::1":                  false,
+		"::":                   false,
+		"fd00::1":              false,
+		"fe80::1":              false,
+		"::ffff:10.0.0.5":      false,
+		"::ffff:127.0.0.1":     false,
+		"::ffff:100.64.0.1":    false,
+		"64:ff9b::a00:5":       false, // NAT64 form of 10.0.0.5
+		"64:ff9b::7f00:1":      false, // NAT64 form of 127.0.0.1
+		"64:ff9b:1::5db8:d822": false,
+		"2001:db8::1":          false,
+	} {
+		assert.Equal(t, public, publicIP(net.ParseIP(ip)), ip)
+	}
+}
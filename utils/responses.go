@@ -55,3 +55,82 @@ type SubscriptionResponse struct {
 	RequestID string `json:"request_id"`
 	Code      int    `json:"code"`
 }
+
+// @Description Response model for listing webhooks
+// @Name WebhooksResponse
+// @Example { "webhooks": [{"id": 1, "url": "https://partner.example.com/hooks/avito", "event_types": ["flat_approved"], "house_ids": [], "active": true, "failure_count": 0, "created_at": "2024-08-11T09:00:00Z"}] }
+type WebhooksResponse struct {
+	Webhooks []models.Webhook `json:"webhooks"`
+}
+
+// @Description Response model for the delivery log of a webhook
+// @Name WebhookDeliveriesResponse
+// @Example { "attempts": [{"id": 10, "delivery_id": 5, "event_id": 42, "event_type": "flat_approved", "attempt": 1, "status_code": 200, "duration_ms": 85, "created_at": "2024-08-11T09:00:10Z"}] }
+type WebhookDeliveriesResponse struct {
+	Attempts []models.WebhookDeliveryAttempt `json:"attempts"`
+}
+
+// @Description Response model for listing saved searches
+// @Name SavedSearchesResponse
+// @Example { "searches": [{"id": 1, "name": "Двушка до 10 млн", "email": "user@example.com", "locale": "ru", "rooms_min": 2, "rooms_max": 2, "price_max": 10000000, "created_at": "2024-08-14T09:00:00Z", "updated_at": "2024-08-14T09:00:00Z"}] }
+type SavedSearchesResponse struct {
+	Searches []models.SavedSearch `json:"searches"`
+}
+
+// @Description Response model for the favorites of a user
+// @Name FavoritesResponse
+// @Example { "favorites": [{"flat": {"id": 1, "house_id": 101, "price": 1200, "rooms": 3, "status": "approved"}, "created_at": "2024-08-15T09:00:00Z"}] }
+type FavoritesResponse struct {
+	Favorites []models.Favorite `json:"favorites"`
+}
+
+// @Description Response model for the viewing slots of a flat
+// @Name ViewingSlotsResponse
+// @Example { "slots": [{"id": 1, "flat_id": 10, "starts_at": "2024-08-20T15:00:00Z", "ends_at": "2024-08-20T15:30:00Z", "booked": false, "created_at": "2024-08-16T09:00:00Z"}] }
+type ViewingSlotsResponse struct {
+	Slots []models.ViewingSlot `json:"slots"`
+}
+
+// @Description Response model for listing viewings
+// @Name ViewingsResponse
+// @Example { "viewings": [{"id": 1, "slot_id": 1, "flat": {"id": 10, "house_id": 1, "price": 12500000, "rooms": 3, "status": "approved"}, "email": "user@example.com", "locale": "ru", "starts_at": "2024-08-20T15:00:00Z", "ends_at": "2024-08-20T15:30:00Z", "status": "booked", "created_at": "2024-08-16T10:00:00Z"}] }
+type ViewingsResponse struct {
+	Viewings []models.Viewing `json:"viewings"`
+}
+
+// @Description Response model for listing conversations
+// @Name ConversationsResponse
+// @Example { "conversations": [{"id": 1, "flat": {"id": 10, "house_id": 1, "price": 12500000, "rooms": 3, "status": "approved"}, "client_id": "a4b4a122-11c1-4b52-bd95-4a5d3c4be616", "owner_id": "b540a379-94ac-4eee-8c4e-83faf2f2d508", "unread": 2, "created_at": "2024-08-17T09:00:00Z", "last_message_at": "2024-08-17T09:30:00Z"}], "unread": 2 }
+type ConversationsResponse struct {
+	Conversations []models.Conversation `json:"conversations"`
+	// Total number of unread messages, not set for reported conversations
+	Unread int `json:"unread,omitempty"`
+}
+
+// @Description Response model for a page of messages
+// @Name MessagesResponse
+// @Example { "messages": [{"id": 42, "conversation_id": 1, "sender_id": "a4b4a122-11c1-4b52-bd95-4a5d3c4be616", "body": "Здравствуйте! Квартира ещё сдаётся?", "created_at": "2024-08-17T09:00:00Z"}] }
+type MessagesResponse struct {
+	Messages []models.Message `json:"messages"`
+}
+
+// @Description Response model for a page of the audit log
+// @Name AuditResponse
+// @Example { "entries": [{"id": 1001, "actor_id": "b540a379-94ac-4eee-8c4e-83faf2f2d508", "actor_role": "moderator", "action": "flat.moderate", "entity_type": "flat", "entity_id": "10", "before": {"status": "on moderation"}, "after": {"status": "approved"}, "request_id": "8f14e45fceea167a5a36dedd4bea2543", "client_ip": "203.0.113.7", "created_at": "2024-08-19T09:00:00Z"}] }
+type AuditResponse struct {
+	Entries []models.AuditEntry `json:"entries"`
+}
+
+// @Description Response model for listing developers
+// @Name DevelopersResponse
+// @Example { "developers": [{"id": 3, "name": "ПИК", "created_at": "2024-08-20T09:00:00Z", "updated_at": "2024-08-20T09:00:00Z"}] }
+type DevelopersResponse struct {
+	Developers []models.Developer `json:"developers"`
+}
+
+// @Description Response model for listing houses
+// @Name HousesResponse
+// @Example { "houses": [{"id": 1, "address": "Лесная улица, 7", "year": 2003, "developer": "ПИК", "developer_id": 3, "created_at": "2024-08-04T00:00:00Z", "updated_at": "2024-08-04T00:00:00Z"}] }
+type HousesResponse struct {
+	Houses []models.House `json:"houses"`
+}
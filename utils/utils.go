@@ -1,12 +1,16 @@
 package utils
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 )
 
 var Validate = validator.New()
@@ -26,6 +30,44 @@ func WriteJSON(c *gin.Context, status int, v any) error {
 	return json.NewEncoder(c.Writer).Encode(v)
 }
 
+// WriteError writes an ErrorResponse with the request id of the context and
+// a Retry-After header, as every handler does for its errors.
+func WriteError(c *gin.Context, status int, message any) {
+	requestId, _ := c.Get("RequestId")
+	c.Header("Retry-After", "30")
+	WriteJSON(c, status, gin.H{
+		"message":    message,
+		"request_id": requestId,
+		"code":       status,
+	})
+}
+
+// WriteStoreError writes 404 with notFound for sql.ErrNoRows and 500 for
+// any other store error.
+func WriteStoreError(c *gin.Context, err error, notFound string) {
+	if errors.Is(err, sql.ErrNoRows) {
+		WriteError(c, http.StatusNotFound, notFound)
+		return
+	}
+	WriteError(c, http.StatusInternalServerError, err.Error())
+}
+
+// CurrentUser returns the id of the authenticated user. When the context has
+// none it writes 401 and returns false.
+func CurrentUser(c *gin.Context) (uuid.UUID, bool) {
+	userID, _ := c.Get("userID")
+	id, ok := userID.(uuid.UUID)
+	if !ok {
+		WriteError(c, http.StatusUnauthorized, "userID not found in context")
+	}
+	return id, ok
+}
+
+// Scanner is implemented by *sql.Row and *sql.Rows.
+type Scanner interface {
+	Scan(dest ...any) error
+}
+
 func FormatValidationError(err error) map[string]string {
 	errors := make(map[string]string)
 	if validationErrors, ok := err.(validator.ValidationErrors); ok {